@@ -23,6 +23,7 @@ const (
 	CommentService_DeleteComment_FullMethodName  = "/comment.v1.CommentService/DeleteComment"
 	CommentService_CreateComment_FullMethodName  = "/comment.v1.CommentService/CreateComment"
 	CommentService_GetMoreReplies_FullMethodName = "/comment.v1.CommentService/GetMoreReplies"
+	CommentService_CountByBiz_FullMethodName     = "/comment.v1.CommentService/CountByBiz"
 )
 
 // CommentServiceClient is the client API for CommentService service.
@@ -36,6 +37,8 @@ type CommentServiceClient interface {
 	// CreateComment 创建评论
 	CreateComment(ctx context.Context, in *CreateCommentRequest, opts ...grpc.CallOption) (*CreateCommentResponse, error)
 	GetMoreReplies(ctx context.Context, in *GetMoreRepliesRequest, opts ...grpc.CallOption) (*GetMoreRepliesResponse, error)
+	// CountByBiz 每个资源的评论数，包括回复
+	CountByBiz(ctx context.Context, in *CountByBizRequest, opts ...grpc.CallOption) (*CountByBizResponse, error)
 }
 
 type commentServiceClient struct {
@@ -86,6 +89,16 @@ func (c *commentServiceClient) GetMoreReplies(ctx context.Context, in *GetMoreRe
 	return out, nil
 }
 
+func (c *commentServiceClient) CountByBiz(ctx context.Context, in *CountByBizRequest, opts ...grpc.CallOption) (*CountByBizResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountByBizResponse)
+	err := c.cc.Invoke(ctx, CommentService_CountByBiz_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CommentServiceServer is the server API for CommentService service.
 // All implementations must embed UnimplementedCommentServiceServer
 // for forward compatibility.
@@ -97,6 +110,8 @@ type CommentServiceServer interface {
 	// CreateComment 创建评论
 	CreateComment(context.Context, *CreateCommentRequest) (*CreateCommentResponse, error)
 	GetMoreReplies(context.Context, *GetMoreRepliesRequest) (*GetMoreRepliesResponse, error)
+	// CountByBiz 每个资源的评论数，包括回复
+	CountByBiz(context.Context, *CountByBizRequest) (*CountByBizResponse, error)
 	mustEmbedUnimplementedCommentServiceServer()
 }
 
@@ -119,6 +134,9 @@ func (UnimplementedCommentServiceServer) CreateComment(context.Context, *CreateC
 func (UnimplementedCommentServiceServer) GetMoreReplies(context.Context, *GetMoreRepliesRequest) (*GetMoreRepliesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMoreReplies not implemented")
 }
+func (UnimplementedCommentServiceServer) CountByBiz(context.Context, *CountByBizRequest) (*CountByBizResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountByBiz not implemented")
+}
 func (UnimplementedCommentServiceServer) mustEmbedUnimplementedCommentServiceServer() {}
 func (UnimplementedCommentServiceServer) testEmbeddedByValue()                        {}
 
@@ -212,6 +230,24 @@ func _CommentService_GetMoreReplies_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CommentService_CountByBiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountByBizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).CountByBiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_CountByBiz_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).CountByBiz(ctx, req.(*CountByBizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CommentService_ServiceDesc is the grpc.ServiceDesc for CommentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -235,6 +271,10 @@ var CommentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMoreReplies",
 			Handler:    _CommentService_GetMoreReplies_Handler,
 		},
+		{
+			MethodName: "CountByBiz",
+			Handler:    _CommentService_CountByBiz_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "comment/v1/comment.proto",
@@ -19,10 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FollowService_Follow_FullMethodName       = "/follow.v1.FollowService/Follow"
-	FollowService_CancelFollow_FullMethodName = "/follow.v1.FollowService/CancelFollow"
-	FollowService_GetFollowee_FullMethodName  = "/follow.v1.FollowService/GetFollowee"
-	FollowService_FollowInfo_FullMethodName   = "/follow.v1.FollowService/FollowInfo"
+	FollowService_Follow_FullMethodName          = "/follow.v1.FollowService/Follow"
+	FollowService_CancelFollow_FullMethodName    = "/follow.v1.FollowService/CancelFollow"
+	FollowService_GetFollowee_FullMethodName     = "/follow.v1.FollowService/GetFollowee"
+	FollowService_FollowInfo_FullMethodName      = "/follow.v1.FollowService/FollowInfo"
+	FollowService_GetFollowStatic_FullMethodName = "/follow.v1.FollowService/GetFollowStatic"
 )
 
 // FollowServiceClient is the client API for FollowService service.
@@ -36,6 +37,8 @@ type FollowServiceClient interface {
 	GetFollowee(ctx context.Context, in *GetFolloweeRequest, opts ...grpc.CallOption) (*GetFolloweeResponse, error)
 	// 获得某个人关注另外一个人的详细信息
 	FollowInfo(ctx context.Context, in *FollowInfoRequest, opts ...grpc.CallOption) (*FollowInfoResponse, error)
+	// 获得某个人的粉丝数量和关注了多少人
+	GetFollowStatic(ctx context.Context, in *GetFollowStaticRequest, opts ...grpc.CallOption) (*GetFollowStaticResponse, error)
 }
 
 type followServiceClient struct {
@@ -86,6 +89,16 @@ func (c *followServiceClient) FollowInfo(ctx context.Context, in *FollowInfoRequ
 	return out, nil
 }
 
+func (c *followServiceClient) GetFollowStatic(ctx context.Context, in *GetFollowStaticRequest, opts ...grpc.CallOption) (*GetFollowStaticResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFollowStaticResponse)
+	err := c.cc.Invoke(ctx, FollowService_GetFollowStatic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FollowServiceServer is the server API for FollowService service.
 // All implementations must embed UnimplementedFollowServiceServer
 // for forward compatibility.
@@ -97,6 +110,8 @@ type FollowServiceServer interface {
 	GetFollowee(context.Context, *GetFolloweeRequest) (*GetFolloweeResponse, error)
 	// 获得某个人关注另外一个人的详细信息
 	FollowInfo(context.Context, *FollowInfoRequest) (*FollowInfoResponse, error)
+	// 获得某个人的粉丝数量和关注了多少人
+	GetFollowStatic(context.Context, *GetFollowStaticRequest) (*GetFollowStaticResponse, error)
 	mustEmbedUnimplementedFollowServiceServer()
 }
 
@@ -119,6 +134,9 @@ func (UnimplementedFollowServiceServer) GetFollowee(context.Context, *GetFollowe
 func (UnimplementedFollowServiceServer) FollowInfo(context.Context, *FollowInfoRequest) (*FollowInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FollowInfo not implemented")
 }
+func (UnimplementedFollowServiceServer) GetFollowStatic(context.Context, *GetFollowStaticRequest) (*GetFollowStaticResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFollowStatic not implemented")
+}
 func (UnimplementedFollowServiceServer) mustEmbedUnimplementedFollowServiceServer() {}
 func (UnimplementedFollowServiceServer) testEmbeddedByValue()                       {}
 
@@ -212,6 +230,24 @@ func _FollowService_FollowInfo_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FollowService_GetFollowStatic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFollowStaticRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowServiceServer).GetFollowStatic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FollowService_GetFollowStatic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowServiceServer).GetFollowStatic(ctx, req.(*GetFollowStaticRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FollowService_ServiceDesc is the grpc.ServiceDesc for FollowService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -235,6 +271,10 @@ var FollowService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FollowInfo",
 			Handler:    _FollowService_FollowInfo_Handler,
 		},
+		{
+			MethodName: "GetFollowStatic",
+			Handler:    _FollowService_GetFollowStatic_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "follow/v1/follow.proto",
@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: job/v1/job.proto
+
+package jobv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	JobExecutorService_Execute_FullMethodName = "/job.v1.JobExecutorService/Execute"
+)
+
+// JobExecutorServiceClient is the client API for JobExecutorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// JobExecutorService 业务方实现这个服务并注册到 etcd，webook 的调度器就可以统一调度它的 job
+type JobExecutorServiceClient interface {
+	// Execute 执行一次 job，超时通过 deadline 传过来，执行失败直接返回错误
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error)
+}
+
+type jobExecutorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJobExecutorServiceClient(cc grpc.ClientConnInterface) JobExecutorServiceClient {
+	return &jobExecutorServiceClient{cc}
+}
+
+func (c *jobExecutorServiceClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteResponse)
+	err := c.cc.Invoke(ctx, JobExecutorService_Execute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// JobExecutorServiceServer is the server API for JobExecutorService service.
+// All implementations must embed UnimplementedJobExecutorServiceServer
+// for forward compatibility.
+//
+// JobExecutorService 业务方实现这个服务并注册到 etcd，webook 的调度器就可以统一调度它的 job
+type JobExecutorServiceServer interface {
+	// Execute 执行一次 job，超时通过 deadline 传过来，执行失败直接返回错误
+	Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
+	mustEmbedUnimplementedJobExecutorServiceServer()
+}
+
+// UnimplementedJobExecutorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedJobExecutorServiceServer struct{}
+
+func (UnimplementedJobExecutorServiceServer) Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedJobExecutorServiceServer) mustEmbedUnimplementedJobExecutorServiceServer() {}
+func (UnimplementedJobExecutorServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeJobExecutorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JobExecutorServiceServer will
+// result in compilation errors.
+type UnsafeJobExecutorServiceServer interface {
+	mustEmbedUnimplementedJobExecutorServiceServer()
+}
+
+func RegisterJobExecutorServiceServer(s grpc.ServiceRegistrar, srv JobExecutorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedJobExecutorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&JobExecutorService_ServiceDesc, srv)
+}
+
+func _JobExecutorService_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobExecutorServiceServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobExecutorService_Execute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobExecutorServiceServer).Execute(ctx, req.(*ExecuteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// JobExecutorService_ServiceDesc is the grpc.ServiceDesc for JobExecutorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var JobExecutorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "job.v1.JobExecutorService",
+	HandlerType: (*JobExecutorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _JobExecutorService_Execute_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "job/v1/job.proto",
+}
@@ -1,11 +1,19 @@
 package main
 
 import (
+	"geektime/webook/internal/job"
+	"geektime/webook/pkg/ginx"
+	"geektime/webook/pkg/saramax"
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
 )
 
 type App struct {
-	server *gin.Engine
-	cron   *cron.Cron
+	server    *gin.Engine
+	consumers []saramax.Consumer
+	cron      *cron.Cron
+	// 基于 MySQL 抢占的调度器
+	scheduler *job.Scheduler
+	// 管理后台
+	webAdmin *ginx.Server
 }
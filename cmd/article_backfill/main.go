@@ -0,0 +1,39 @@
+// article_backfill 把线上库还存放在 MySQL 里面的帖子内容搬到对象存储
+// 在把 article.storage 切换成 oss 之后运行，可以重复运行
+package main
+
+import (
+	"context"
+	"geektime/webook/internal/repository/dao"
+	"geektime/webook/ioc"
+	"geektime/webook/pkg/logger"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"time"
+)
+
+func main() {
+	cfile := pflag.String("config", "config/config.yaml", "配置文件路径")
+	batch := pflag.Int("batch", 100, "每一批搬多少篇")
+	pflag.Parse()
+	viper.SetConfigFile(*cfile)
+	err := viper.ReadInConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	l := ioc.InitLoggerV1()
+	db := ioc.InitDB(l)
+	artDAO := dao.NewArticleS3DAO(db, ioc.InitObjectStore(), 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	start := time.Now()
+	cnt, err := artDAO.Backfill(ctx, *batch)
+	if err != nil {
+		l.Error("搬迁帖子内容失败", logger.Int("cnt", cnt), logger.Error(err))
+		return
+	}
+	l.Info("搬迁帖子内容完成", logger.Int("cnt", cnt),
+		logger.String("cost", time.Since(start).String()))
+}
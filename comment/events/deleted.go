@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"geektime/webook/comment/repository"
+	"geektime/webook/pkg/logger"
+	"geektime/webook/pkg/saramax"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+var _ saramax.Consumer = &DeletedEventConsumer{}
+
+// DeletedEvent 某个资源被彻底删除了
+type DeletedEvent struct {
+	Biz   string
+	BizId int64
+}
+
+// DeletedEventConsumer 资源被彻底删除之后，删除它下面的评论
+type DeletedEventConsumer struct {
+	repo   repository.CommentRepository
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewDeletedEventConsumer(repo repository.CommentRepository,
+	client sarama.Client, l logger.LoggerV1) *DeletedEventConsumer {
+	return &DeletedEventConsumer{repo: repo, client: client, l: l}
+}
+
+func (d *DeletedEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("comment", d.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(),
+			[]string{"article_deleted"},
+			saramax.NewHandler[DeletedEvent](d.l, d.Consume))
+		if er != nil {
+			d.l.Error("退出消费", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+func (d *DeletedEventConsumer) Consume(msg *sarama.ConsumerMessage, evt DeletedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return d.repo.DeleteByBiz(ctx, evt.Biz, evt.BizId)
+}
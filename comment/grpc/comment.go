@@ -36,6 +36,14 @@ func (c *CommentServiceServer) GetMoreReplies(ctx context.Context, req *commentv
 	}, nil
 }
 
+func (c *CommentServiceServer) CountByBiz(ctx context.Context, req *commentv1.CountByBizRequest) (*commentv1.CountByBizResponse, error) {
+	cnts, err := c.svc.CountByBiz(ctx, req.GetBiz(), req.GetBizids())
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.CountByBizResponse{Cnts: cnts}, nil
+}
+
 func (c *CommentServiceServer) GetCommentList(ctx context.Context, request *commentv1.CommentListRequest) (*commentv1.CommentListResponse, error) {
 	minID := request.MinId
 	// 第一次查询
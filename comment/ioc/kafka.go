@@ -0,0 +1,28 @@
+package ioc
+
+import (
+	"geektime/webook/comment/events"
+	"geektime/webook/pkg/saramax"
+	"github.com/IBM/sarama"
+	"github.com/spf13/viper"
+)
+
+func InitKafkaClient() sarama.Client {
+	type Config struct {
+		Addr []string `yaml:"addr"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("kafka", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	client, err := sarama.NewClient(cfg.Addr, sarama.NewConfig())
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func InitConsumers(deleted *events.DeletedEventConsumer) []saramax.Consumer {
+	return []saramax.Consumer{deleted}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"geektime/webook/pkg/grpcx"
+	"geektime/webook/pkg/saramax"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -9,6 +10,12 @@ import (
 func main() {
 	initViperV2Watch()
 	app := Init()
+	for _, c := range app.consumers {
+		err := c.Start()
+		if err != nil {
+			panic(err)
+		}
+	}
 	err := app.server.Serve()
 	if err != nil {
 		panic(err)
@@ -29,5 +36,6 @@ func initViperV2Watch() {
 }
 
 type App struct {
-	server *grpcx.Server
+	server    *grpcx.Server
+	consumers []saramax.Consumer
 }
@@ -22,6 +22,9 @@ type CommentRepository interface {
 	// GetCommentByIds 获取单条评论 支持批量获取
 	GetCommentByIds(ctx context.Context, id []int64) ([]domain.Comment, error)
 	GetMoreReplies(ctx context.Context, rid int64, id int64, limit int64) ([]domain.Comment, error)
+	// DeleteByBiz 删除某个资源下面的所有评论
+	DeleteByBiz(ctx context.Context, biz string, bizId int64) error
+	CountByBiz(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error)
 }
 
 type CachedCommentRepo struct {
@@ -79,6 +82,14 @@ func (c *CachedCommentRepo) DeleteComment(ctx context.Context, comment domain.Co
 	})
 }
 
+func (c *CachedCommentRepo) DeleteByBiz(ctx context.Context, biz string, bizId int64) error {
+	return c.dao.DeleteByBiz(ctx, biz, bizId)
+}
+
+func (c *CachedCommentRepo) CountByBiz(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	return c.dao.CountByBiz(ctx, biz, bizIds)
+}
+
 func (c *CachedCommentRepo) CreateComment(ctx context.Context, comment domain.Comment) error {
 	return c.dao.Insert(ctx, c.toEntity(comment))
 }
@@ -22,6 +22,10 @@ type CommentDAO interface {
 	Delete(ctx context.Context, u Comment) error
 	FindOneByIDs(ctx context.Context, id []int64) ([]Comment, error)
 	FindRepliesByRid(ctx context.Context, rid int64, id int64, limit int64) ([]Comment, error)
+	// DeleteByBiz 被评价的东西彻底删除之后，删除它下面所有的评论
+	DeleteByBiz(ctx context.Context, biz string, bizId int64) error
+	// CountByBiz 每个 bizId 的评论数，没有评论的不在结果里面
+	CountByBiz(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error)
 }
 
 type TreeBase struct {
@@ -141,3 +145,29 @@ func (c *GORMCommentDAO) Delete(ctx context.Context, u Comment) error {
 		Id: u.Id,
 	}).Error
 }
+
+func (c *GORMCommentDAO) DeleteByBiz(ctx context.Context, biz string, bizId int64) error {
+	return c.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ?", biz, bizId).
+		Delete(&Comment{}).Error
+}
+
+func (c *GORMCommentDAO) CountByBiz(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	var cnts []struct {
+		BizID int64
+		Cnt   int64
+	}
+	err := c.db.WithContext(ctx).Model(&Comment{}).
+		Select("biz_id, COUNT(*) AS cnt").
+		Where("biz = ? AND biz_id IN ?", biz, bizIds).
+		Group("biz_id").
+		Scan(&cnts).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]int64, len(cnts))
+	for _, cnt := range cnts {
+		res[cnt.BizID] = cnt.Cnt
+	}
+	return res, nil
+}
@@ -5,14 +5,15 @@
 //
 //	mockgen -source=./comment.go -package=daomocks -destination=mocks/comment.mock.go CommentDAO
 //
+
 // Package daomocks is a generated GoMock package.
 package daomocks
 
 import (
 	context "context"
+	dao "geektime/webook/comment/repository/dao"
 	reflect "reflect"
 
-	dao "geektime/webook/comment/repository/dao"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,6 +40,21 @@ func (m *MockCommentDAO) EXPECT() *MockCommentDAOMockRecorder {
 	return m.recorder
 }
 
+// CountByBiz mocks base method.
+func (m *MockCommentDAO) CountByBiz(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByBiz", ctx, biz, bizIds)
+	ret0, _ := ret[0].(map[int64]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByBiz indicates an expected call of CountByBiz.
+func (mr *MockCommentDAOMockRecorder) CountByBiz(ctx, biz, bizIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByBiz", reflect.TypeOf((*MockCommentDAO)(nil).CountByBiz), ctx, biz, bizIds)
+}
+
 // Delete mocks base method.
 func (m *MockCommentDAO) Delete(ctx context.Context, u dao.Comment) error {
 	m.ctrl.T.Helper()
@@ -53,6 +69,20 @@ func (mr *MockCommentDAOMockRecorder) Delete(ctx, u any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCommentDAO)(nil).Delete), ctx, u)
 }
 
+// DeleteByBiz mocks base method.
+func (m *MockCommentDAO) DeleteByBiz(ctx context.Context, biz string, bizId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByBiz", ctx, biz, bizId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByBiz indicates an expected call of DeleteByBiz.
+func (mr *MockCommentDAOMockRecorder) DeleteByBiz(ctx, biz, bizId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByBiz", reflect.TypeOf((*MockCommentDAO)(nil).DeleteByBiz), ctx, biz, bizId)
+}
+
 // FindByBiz mocks base method.
 func (m *MockCommentDAO) FindByBiz(ctx context.Context, biz string, bizId, minID, limit int64) ([]dao.Comment, error) {
 	m.ctrl.T.Helper()
@@ -15,6 +15,8 @@ type CommentService interface {
 	// CreateComment 创建评论
 	CreateComment(ctx context.Context, comment domain.Comment) error
 	GetMoreReplies(ctx context.Context, rid int64, maxID int64, limit int64) ([]domain.Comment, error)
+	// CountByBiz 每个资源的评论数，包括回复
+	CountByBiz(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error)
 }
 
 type commentService struct {
@@ -53,3 +55,7 @@ func (c *commentService) DeleteComment(ctx context.Context, id int64) error {
 func (c *commentService) CreateComment(ctx context.Context, comment domain.Comment) error {
 	return c.repo.CreateComment(ctx, comment)
 }
+
+func (c *commentService) CountByBiz(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	return c.repo.CountByBiz(ctx, biz, bizIds)
+}
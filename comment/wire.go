@@ -3,6 +3,7 @@
 package main
 
 import (
+	"geektime/webook/comment/events"
 	grpc2 "geektime/webook/comment/grpc"
 	"geektime/webook/comment/ioc"
 	"geektime/webook/comment/repository"
@@ -21,6 +22,7 @@ var serviceProviderSet = wire.NewSet(
 var thirdProvider = wire.NewSet(
 	ioc.InitLogger,
 	ioc.InitDB,
+	ioc.InitKafkaClient,
 )
 
 func Init() *App {
@@ -28,6 +30,8 @@ func Init() *App {
 		thirdProvider,
 		serviceProviderSet,
 		ioc.InitGRPCxServer,
+		events.NewDeletedEventConsumer,
+		ioc.InitConsumers,
 		wire.Struct(new(App), "*"),
 	)
 	return new(App)
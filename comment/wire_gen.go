@@ -7,6 +7,7 @@
 package main
 
 import (
+	"geektime/webook/comment/events"
 	"geektime/webook/comment/grpc"
 	"geektime/webook/comment/ioc"
 	"geektime/webook/comment/repository"
@@ -25,8 +26,12 @@ func Init() *App {
 	commentService := service.NewCommentSvc(commentRepo)
 	commentServiceServer := grpc.NewGrpcServer(commentService)
 	server := ioc.InitGRPCxServer(commentServiceServer)
+	client := ioc.InitKafkaClient()
+	deletedEventConsumer := events.NewDeletedEventConsumer(commentRepo, client, loggerV1)
+	v := ioc.InitConsumers(deletedEventConsumer)
 	app := &App{
-		server: server,
+		server:    server,
+		consumers: v,
 	}
 	return app
 }
@@ -35,4 +40,4 @@ func Init() *App {
 
 var serviceProviderSet = wire.NewSet(dao.NewCommentDAO, repository.NewCommentRepo, service.NewCommentSvc, grpc.NewGrpcServer)
 
-var thirdProvider = wire.NewSet(ioc.InitLogger, ioc.InitDB)
+var thirdProvider = wire.NewSet(ioc.InitLogger, ioc.InitDB, ioc.InitKafkaClient)
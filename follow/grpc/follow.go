@@ -58,6 +58,17 @@ func (f *FollowServiceServer) CancelFollow(ctx context.Context, request *followv
 	return &followv1.CancelFollowResponse{}, err
 }
 
+func (f *FollowServiceServer) GetFollowStatic(ctx context.Context, request *followv1.GetFollowStaticRequest) (*followv1.GetFollowStaticResponse, error) {
+	res, err := f.svc.GetFollowStatic(ctx, request.Uid)
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.GetFollowStaticResponse{
+		Followers: res.Followers,
+		Followees: res.Followees,
+	}, nil
+}
+
 func (f *FollowServiceServer) convertToView(relation domain.FollowRelation) *followv1.FollowRelation {
 	return &followv1.FollowRelation{
 		Followee: relation.Followee,
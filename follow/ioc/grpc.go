@@ -3,16 +3,21 @@ package ioc
 import (
 	grpc2 "geektime/webook/follow/grpc"
 	"geektime/webook/pkg/grpcx"
+	"geektime/webook/pkg/logger"
 	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"google.golang.org/grpc"
 )
 
-func InitGRPCxServer(followRelation *grpc2.FollowServiceServer) *grpcx.Server {
+// InitGRPCxServer 注册到 etcd 上，webook 通过服务发现调用
+func InitGRPCxServer(followRelation *grpc2.FollowServiceServer,
+	ecli *clientv3.Client,
+	l logger.LoggerV1) *grpcx.Server {
 	type Config struct {
-		Addr string `yaml:"addr"`
+		Port int `yaml:"port"`
 	}
 	var cfg Config
-	err := viper.UnmarshalKey("grpc", &cfg)
+	err := viper.UnmarshalKey("grpc.server", &cfg)
 	if err != nil {
 		panic(err)
 	}
@@ -20,6 +25,9 @@ func InitGRPCxServer(followRelation *grpc2.FollowServiceServer) *grpcx.Server {
 	followRelation.Register(server)
 	return &grpcx.Server{
 		Server: server,
-		Addr:   cfg.Addr,
+		Port:   cfg.Port,
+		Name:   "follow",
+		L:      l,
+		Client: ecli,
 	}
 }
@@ -12,6 +12,8 @@ type FollowRelationService interface {
 		follower, followee int64) (domain.FollowRelation, error)
 	Follow(ctx context.Context, follower, followee int64) error
 	CancelFollow(ctx context.Context, follower, followee int64) error
+	// GetFollowStatic 粉丝数量和关注了多少人
+	GetFollowStatic(ctx context.Context, uid int64) (domain.FollowStatics, error)
 }
 
 type followRelationService struct {
@@ -45,3 +47,7 @@ func (f *followRelationService) GetFollowee(ctx context.Context,
 	follower, offset, limit int64) ([]domain.FollowRelation, error) {
 	return f.repo.GetFollowee(ctx, follower, offset, limit)
 }
+
+func (f *followRelationService) GetFollowStatic(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	return f.repo.GetFollowStatics(ctx, uid)
+}
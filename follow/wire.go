@@ -6,6 +6,7 @@ import (
 	grpc2 "geektime/webook/follow/grpc"
 	"geektime/webook/follow/ioc"
 	"geektime/webook/follow/repository"
+	"geektime/webook/follow/repository/cache"
 	"geektime/webook/follow/repository/dao"
 	"geektime/webook/follow/service"
 	"github.com/google/wire"
@@ -13,6 +14,7 @@ import (
 
 var serviceProviderSet = wire.NewSet(
 	dao.NewGORMFollowRelationDAO,
+	cache.NewRedisFollowCache,
 	repository.NewFollowRelationRepository,
 	service.NewFollowRelationService,
 	grpc2.NewFollowRelationServiceServer,
@@ -21,6 +23,8 @@ var serviceProviderSet = wire.NewSet(
 var thirdProvider = wire.NewSet(
 	ioc.InitDB,
 	ioc.InitLogger,
+	ioc.InitRedis,
+	ioc.InitEtcdClient,
 )
 
 func Init() *App {
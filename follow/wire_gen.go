@@ -10,6 +10,7 @@ import (
 	"geektime/webook/follow/grpc"
 	"geektime/webook/follow/ioc"
 	"geektime/webook/follow/repository"
+	"geektime/webook/follow/repository/cache"
 	"geektime/webook/follow/repository/dao"
 	"geektime/webook/follow/service"
 	"github.com/google/wire"
@@ -21,10 +22,13 @@ func Init() *App {
 	loggerV1 := ioc.InitLogger()
 	db := ioc.InitDB(loggerV1)
 	followRelationDao := dao.NewGORMFollowRelationDAO(db)
-	followRelationRepository := repository.NewFollowRelationRepository(followRelationDao)
+	cmdable := ioc.InitRedis()
+	followCache := cache.NewRedisFollowCache(cmdable)
+	followRelationRepository := repository.NewFollowRelationRepository(followRelationDao, followCache, loggerV1)
 	followRelationService := service.NewFollowRelationService(followRelationRepository)
 	followRelationServiceServer := grpc.NewFollowRelationServiceServer(followRelationService)
-	server := ioc.InitGRPCxServer(followRelationServiceServer)
+	client := ioc.InitEtcdClient()
+	server := ioc.InitGRPCxServer(followRelationServiceServer, client, loggerV1)
 	app := &App{
 		server: server,
 	}
@@ -33,6 +37,6 @@ func Init() *App {
 
 // wire.go:
 
-var serviceProviderSet = wire.NewSet(dao.NewGORMFollowRelationDAO, repository.NewFollowRelationRepository, service.NewFollowRelationService, grpc.NewFollowRelationServiceServer)
+var serviceProviderSet = wire.NewSet(dao.NewGORMFollowRelationDAO, cache.NewRedisFollowCache, repository.NewFollowRelationRepository, service.NewFollowRelationService, grpc.NewFollowRelationServiceServer)
 
-var thirdProvider = wire.NewSet(ioc.InitDB, ioc.InitLogger)
+var thirdProvider = wire.NewSet(ioc.InitDB, ioc.InitLogger, ioc.InitRedis, ioc.InitEtcdClient)
@@ -4,10 +4,12 @@ import (
 	"geektime/webook/pkg/ginx"
 	"geektime/webook/pkg/grpcx"
 	"geektime/webook/pkg/saramax"
+	"github.com/robfig/cron/v3"
 )
 
 type App struct {
 	server    *grpcx.Server
 	consumers []saramax.Consumer
 	webAdmin  *ginx.Server
+	cron      *cron.Cron
 }
@@ -0,0 +1,8 @@
+package domain
+
+// 目前接入交互服务的资源类型，新的资源要在 BizRegistry 里面注册
+const (
+	BizArticle = "article"
+	BizSeries  = "series"
+	BizComment = "comment"
+)
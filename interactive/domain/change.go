@@ -0,0 +1,16 @@
+package domain
+
+// 计数变化的字段，热榜等下游按照这个区分权重
+const (
+	CntFieldLike    = "like"
+	CntFieldCollect = "collect"
+	CntFieldRead    = "read"
+)
+
+// CntChange 某个资源的某项计数变了多少，取消点赞、取消收藏的时候 Delta 是负数
+type CntChange struct {
+	Biz   string
+	BizId int64
+	Field string
+	Delta int64
+}
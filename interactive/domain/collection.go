@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// MaxCollectionNameLen 收藏夹名字最多多少个字
+const MaxCollectionNameLen = 32
+
+// Collection 收藏夹，没有选收藏夹的收藏放在 Id 为 0 的默认收藏夹
+type Collection struct {
+	Id    int64
+	Uid   int64
+	Name  string
+	Ctime time.Time
+	Utime time.Time
+}
+
+// CollectionItem 收藏夹里面的一条收藏
+type CollectionItem struct {
+	Cid   int64
+	Biz   string
+	BizId int64
+	Ctime time.Time
+}
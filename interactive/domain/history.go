@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// HistoryRecord 阅读历史，同一个人读同一个资源只有一条记录，ReadTime 是最后一次阅读的时间
+type HistoryRecord struct {
+	Id       int64
+	Biz      string
+	BizId    int64
+	Uid      int64
+	ReadTime time.Time
+}
+
+// HistoryCursor 按照阅读时间、id 倒序翻页的位置，零值表示第一页
+type HistoryCursor struct {
+	ReadTime time.Time
+	Id       int64
+}
@@ -0,0 +1,18 @@
+package domain
+
+const (
+	ReconcileStoreDB    = "mysql"
+	ReconcileStoreCache = "redis"
+)
+
+// CntDrift 对账发现的计数偏差，Want 是按照明细表算出来的
+type CntDrift struct {
+	Biz   string
+	BizId int64
+	// Field like_cnt 或者 collect_cnt
+	Field string
+	// Store 偏差出现在数据库还是缓存
+	Store string
+	Got   int64
+	Want  int64
+}
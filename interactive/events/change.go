@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"geektime/webook/interactive/domain"
+	"geektime/webook/interactive/service"
+	"github.com/IBM/sarama"
+	"strconv"
+	"time"
+)
+
+const topicInteractiveChanges = "interactive_changes"
+
+var _ service.ChangeProducer = &SaramaChangeProducer{}
+
+type SaramaChangeProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewSaramaChangeProducer(producer sarama.SyncProducer) service.ChangeProducer {
+	return &SaramaChangeProducer{producer: producer}
+}
+
+// ProduceChanges 一批变化一起发，同一个资源的消息进同一个分区
+func (s *SaramaChangeProducer) ProduceChanges(ctx context.Context, changes []domain.CntChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	msgs := make([]*sarama.ProducerMessage, 0, len(changes))
+	for _, c := range changes {
+		data, err := json.Marshal(CntChangeEvent{
+			Biz:   c.Biz,
+			BizId: c.BizId,
+			Field: c.Field,
+			Delta: c.Delta,
+			Ctime: now,
+		})
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic: topicInteractiveChanges,
+			Key:   sarama.StringEncoder(c.Biz + ":" + strconv.FormatInt(c.BizId, 10)),
+			Value: sarama.ByteEncoder(data),
+		})
+	}
+	return s.producer.SendMessages(msgs)
+}
+
+// CntChangeEvent 计数变化的消息，Field 是 like、collect 或者 read
+type CntChangeEvent struct {
+	Biz   string
+	BizId int64
+	Field string
+	Delta int64
+	// Ctime 毫秒数
+	Ctime int64
+}
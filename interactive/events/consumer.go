@@ -2,8 +2,10 @@ package events
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"geektime/webook/interactive/domain"
 	"geektime/webook/interactive/repository"
+	"geektime/webook/interactive/service"
 	"geektime/webook/pkg/logger"
 	"geektime/webook/pkg/saramax"
 	"github.com/IBM/sarama"
@@ -12,15 +14,35 @@ import (
 
 var _ saramax.Consumer = &InteractiveReadEventConsumer{}
 
+const (
+	// readCntFlushSize 攒够这么多条阅读事件就写一次数据库
+	readCntFlushSize = 500
+	// readCntFlushInterval 攒不够的时候最多等这么久
+	readCntFlushInterval = time.Second
+)
+
+// InteractiveReadEventConsumer 阅读数先在内存里面按照资源合并，
+// 一批只写一次数据库，写成功了才提交偏移量
 type InteractiveReadEventConsumer struct {
-	repo   repository.InteractiveRepository
-	client sarama.Client
-	l      logger.LoggerV1
+	repo     repository.InteractiveRepository
+	bizs     *service.BizRegistry
+	producer service.ChangeProducer
+	client   sarama.Client
+	l        logger.LoggerV1
+	m        *saramax.FlushMetrics
 }
 
 func NewInteractiveReadEventConsumer(repo repository.InteractiveRepository,
+	bizs *service.BizRegistry, producer service.ChangeProducer,
 	client sarama.Client, l logger.LoggerV1) *InteractiveReadEventConsumer {
-	return &InteractiveReadEventConsumer{repo: repo, client: client, l: l}
+	return &InteractiveReadEventConsumer{
+		repo:     repo,
+		bizs:     bizs,
+		producer: producer,
+		client:   client,
+		l:        l,
+		m:        saramax.NewFlushMetrics("lll", "interactive", "read_cnt"),
+	}
 }
 
 func (i *InteractiveReadEventConsumer) Start() error {
@@ -29,9 +51,9 @@ func (i *InteractiveReadEventConsumer) Start() error {
 		return err
 	}
 	go func() {
-		er := cg.Consume(context.Background(),
-			[]string{"article_read"},
-			saramax.NewHandler[ReadEvent](i.l, i.Consume))
+		er := cg.Consume(context.Background(), []string{"article_read"},
+			saramax.NewFlushHandler[ReadEvent](i.l, i.m,
+				readCntFlushSize, readCntFlushInterval, i.BatchConsume))
 		if er != nil {
 			i.l.Error("退出消费", logger.Error(er))
 		}
@@ -39,49 +61,97 @@ func (i *InteractiveReadEventConsumer) Start() error {
 	return err
 }
 
-// Consume 消费处理函数
-func (i *InteractiveReadEventConsumer) Consume(msg *sarama.ConsumerMessage,
-	event ReadEvent) error {
-	start := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+// BatchConsume 同一个资源的阅读合并成一次 read_cnt = read_cnt + N
+func (i *InteractiveReadEventConsumer) BatchConsume(msgs []*sarama.ConsumerMessage,
+	events []ReadEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
-	err := i.repo.IncrReadCnt(ctx, "article", event.Aid)
-	t := time.Since(start).String()
-	fmt.Println(t)
-	return err
-}
-
-func (i *InteractiveReadEventConsumer) StartV1() error {
-	cg, err := sarama.NewConsumerGroupFromClient("interactive", i.client)
+	type key struct {
+		biz   string
+		bizId int64
+	}
+	cnts := make(map[key]int64, len(events))
+	for _, evt := range events {
+		// 重复阅读和刷量的只记阅读历史，不计数
+		if !evt.counted() {
+			continue
+		}
+		evt = evt.normalize()
+		cnts[key{biz: evt.Biz, bizId: evt.BizId}]++
+	}
+	// 合并之后每个资源只校验一次
+	for k := range cnts {
+		err := i.bizs.Validate(ctx, k.biz, k.bizId)
+		if err == nil {
+			continue
+		}
+		// 超时、对端不可用之类的错误返回出去，不提交偏移量，这一批会重新消费
+		if !invalidBiz(err) {
+			return err
+		}
+		i.l.Warn("忽略阅读事件",
+			logger.String("biz", k.biz),
+			logger.Int64("bizId", k.bizId),
+			logger.Int64("cnt", cnts[k]),
+			logger.Error(err))
+		delete(cnts, k)
+	}
+	if len(cnts) == 0 {
+		return nil
+	}
+	bizs := make([]string, 0, len(cnts))
+	bizIds := make([]int64, 0, len(cnts))
+	deltas := make([]int64, 0, len(cnts))
+	changes := make([]domain.CntChange, 0, len(cnts))
+	for k, cnt := range cnts {
+		bizs = append(bizs, k.biz)
+		bizIds = append(bizIds, k.bizId)
+		deltas = append(deltas, cnt)
+		changes = append(changes, domain.CntChange{
+			Biz:   k.biz,
+			BizId: k.bizId,
+			Field: domain.CntFieldRead,
+			Delta: cnt,
+		})
+	}
+	err := i.repo.BatchIncrReadCnt(ctx, bizs, bizIds, deltas)
 	if err != nil {
 		return err
 	}
-	go func() {
-		er := cg.Consume(context.Background(), []string{"article_read"},
-			saramax.NewBatchHandler[ReadEvent](i.l, i.BatchConsume))
-		if er != nil {
-			i.l.Error("退出消费", logger.Error(er))
-		}
-	}()
-	return err
+	// 阅读数已经写进去了，这里失败了重新消费会重复计数，只记日志
+	if err = i.producer.ProduceChanges(ctx, changes); err != nil {
+		i.l.Error("发送阅读数变化失败", logger.Int("size", len(changes)), logger.Error(err))
+	}
+	return nil
 }
 
-// BatchConsume 批量消费处理函数
-func (i *InteractiveReadEventConsumer) BatchConsume(msgs []*sarama.ConsumerMessage,
-	events []ReadEvent) error {
-	bizs := make([]string, 0, len(events))
-	bizIds := make([]int64, 0, len(events))
-	for _, evt := range events {
-		bizs = append(bizs, "article")
-		bizIds = append(bizIds, evt.Aid)
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-	return i.repo.BatchIncrReadCnt(ctx, bizs, bizIds)
+// invalidBiz 资源类型不认识或者资源不存在，重试也没有用，这种事件可以直接丢掉
+func invalidBiz(err error) bool {
+	return errors.Is(err, service.ErrBizNotFound) || errors.Is(err, service.ErrUnknownBiz)
 }
 
-// ReadEvent 某个用户读了某篇文章
+// ReadEvent 某个用户读了某个资源
 type ReadEvent struct {
-	Uid int64
+	Uid   int64
+	Biz   string
+	BizId int64
+	// Aid 老版本的消息只有这个字段
 	Aid int64
+	// Counted 要不要计入阅读数，只有阅读数的消费者看这个字段
+	// 老版本的消息没有这个字段，都是要计数的
+	Counted *bool
+}
+
+// counted 老消息没有 Counted，当成要计数
+func (r ReadEvent) counted() bool {
+	return r.Counted == nil || *r.Counted
+}
+
+// normalize 没有 Biz 的是老消息，都是帖子
+func (r ReadEvent) normalize() ReadEvent {
+	if r.Biz == "" {
+		r.Biz = domain.BizArticle
+		r.BizId = r.Aid
+	}
+	return r
 }
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"geektime/webook/interactive/repository"
+	"geektime/webook/pkg/logger"
+	"geektime/webook/pkg/saramax"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+var _ saramax.Consumer = &InteractiveDeletedEventConsumer{}
+
+// InteractiveDeletedEventConsumer 资源被彻底删除之后，清理对应的计数、点赞和收藏
+type InteractiveDeletedEventConsumer struct {
+	repo   repository.InteractiveRepository
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewInteractiveDeletedEventConsumer(repo repository.InteractiveRepository,
+	client sarama.Client, l logger.LoggerV1) *InteractiveDeletedEventConsumer {
+	return &InteractiveDeletedEventConsumer{repo: repo, client: client, l: l}
+}
+
+func (i *InteractiveDeletedEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("interactive_purge", i.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(),
+			[]string{"article_deleted"},
+			saramax.NewHandler[DeletedEvent](i.l, i.Consume))
+		if er != nil {
+			i.l.Error("退出消费", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+func (i *InteractiveDeletedEventConsumer) Consume(msg *sarama.ConsumerMessage,
+	event DeletedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return i.repo.DeleteBiz(ctx, event.Biz, event.BizId)
+}
+
+// DeletedEvent 某个资源被彻底删除了
+type DeletedEvent struct {
+	Biz   string
+	BizId int64
+}
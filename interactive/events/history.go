@@ -2,30 +2,40 @@ package events
 
 import (
 	"context"
-	"geektime/webook/internal/domain"
-	"geektime/webook/internal/events/article"
-	"geektime/webook/internal/repository"
+	"geektime/webook/interactive/domain"
+	"geektime/webook/interactive/repository"
+	"geektime/webook/interactive/service"
 	"geektime/webook/pkg/logger"
 	"geektime/webook/pkg/saramax"
 	"github.com/IBM/sarama"
 	"time"
 )
 
+var _ saramax.Consumer = &HistoryRecordConsumer{}
+
+// HistoryRecordConsumer 和阅读计数用不同的消费者组，各自消费一遍 article_read
 type HistoryRecordConsumer struct {
 	repo   repository.HistoryRecordRepository
+	bizs   *service.BizRegistry
 	client sarama.Client
 	l      logger.LoggerV1
 }
 
+func NewHistoryRecordConsumer(repo repository.HistoryRecordRepository,
+	bizs *service.BizRegistry,
+	client sarama.Client, l logger.LoggerV1) *HistoryRecordConsumer {
+	return &HistoryRecordConsumer{repo: repo, bizs: bizs, client: client, l: l}
+}
+
 func (i *HistoryRecordConsumer) Start() error {
-	cg, err := sarama.NewConsumerGroupFromClient("interactive", i.client)
+	cg, err := sarama.NewConsumerGroupFromClient("interactive_history", i.client)
 	if err != nil {
 		return err
 	}
 	go func() {
 		er := cg.Consume(context.Background(),
 			[]string{"article_read"},
-			saramax.NewHandler[article.ReadEvent](i.l, i.Consume))
+			saramax.NewHandler[ReadEvent](i.l, i.Consume))
 		if er != nil {
 			i.l.Error("退出消费", logger.Error(er))
 		}
@@ -34,12 +44,27 @@ func (i *HistoryRecordConsumer) Start() error {
 }
 
 func (i *HistoryRecordConsumer) Consume(msg *sarama.ConsumerMessage,
-	event article.ReadEvent) error {
+	event ReadEvent) error {
+	// 没有登录的阅读不记录
+	if event.Uid <= 0 {
+		return nil
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
+	event = event.normalize()
+	if err := i.bizs.Validate(ctx, event.Biz, event.BizId); err != nil {
+		if !invalidBiz(err) {
+			return err
+		}
+		i.l.Warn("忽略阅读事件",
+			logger.String("biz", event.Biz),
+			logger.Int64("bizId", event.BizId),
+			logger.Error(err))
+		return nil
+	}
 	return i.repo.AddRecord(ctx, domain.HistoryRecord{
-		BizId: event.Aid,
-		Biz:   "article",
+		BizId: event.BizId,
+		Biz:   event.Biz,
 		Uid:   event.Uid,
 	})
 }
@@ -2,12 +2,14 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	intrv1 "geektime/webook/api/proto/gen/intr/v1"
 	"geektime/webook/interactive/domain"
 	"geektime/webook/interactive/service"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 // InteractiveServiceServer 这里只是把service包装成一个grpc
@@ -28,12 +30,18 @@ func (i *InteractiveServiceServer) Register(server *grpc.Server) {
 
 func (i *InteractiveServiceServer) IncrReadCnt(ctx context.Context, request *intrv1.IncrReadCntRequest) (*intrv1.IncrReadCntResponse, error) {
 	err := i.svc.IncrReadCnt(ctx, request.Biz, request.BizId)
-	return &intrv1.IncrReadCntResponse{}, err
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.IncrReadCntResponse{}, nil
 }
 
 func (i InteractiveServiceServer) Like(ctx context.Context, request *intrv1.LikeRequest) (*intrv1.LikeResponse, error) {
 	err := i.svc.Like(ctx, request.Biz, request.BizId, request.Uid)
-	return &intrv1.LikeResponse{}, err
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.LikeResponse{}, nil
 }
 
 func (i InteractiveServiceServer) CancelLike(ctx context.Context, request *intrv1.CancelLikeRequest) (*intrv1.CancelLikeResponse, error) {
@@ -43,18 +51,112 @@ func (i InteractiveServiceServer) CancelLike(ctx context.Context, request *intrv
 		return nil, status.Error(codes.InvalidArgument, "uid 错误")
 	}
 	err := i.svc.CancelLike(ctx, request.Biz, request.BizId, request.Uid)
-	return &intrv1.CancelLikeResponse{}, err
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.CancelLikeResponse{}, nil
 }
 
 func (i InteractiveServiceServer) Collect(ctx context.Context, request *intrv1.CollectRequest) (*intrv1.CollectResponse, error) {
 	err := i.svc.Collect(ctx, request.Biz, request.BizId, request.Cid, request.Uid)
-	return &intrv1.CollectResponse{}, err
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.CollectResponse{}, nil
+}
+
+func (i *InteractiveServiceServer) CancelCollect(ctx context.Context, request *intrv1.CancelCollectRequest) (*intrv1.CancelCollectResponse, error) {
+	if request.Uid <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "uid 错误")
+	}
+	err := i.svc.CancelCollect(ctx, request.Biz, request.BizId, request.Uid)
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.CancelCollectResponse{}, nil
+}
+
+func (i *InteractiveServiceServer) CreateCollection(ctx context.Context, request *intrv1.CreateCollectionRequest) (*intrv1.CreateCollectionResponse, error) {
+	id, err := i.svc.CreateCollection(ctx, domain.Collection{
+		Uid:  request.Uid,
+		Name: request.Name,
+	})
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.CreateCollectionResponse{Id: id}, nil
+}
+
+func (i *InteractiveServiceServer) RenameCollection(ctx context.Context, request *intrv1.RenameCollectionRequest) (*intrv1.RenameCollectionResponse, error) {
+	err := i.svc.RenameCollection(ctx, request.Id, request.Uid, request.Name)
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.RenameCollectionResponse{}, nil
+}
+
+func (i *InteractiveServiceServer) DeleteCollection(ctx context.Context, request *intrv1.DeleteCollectionRequest) (*intrv1.DeleteCollectionResponse, error) {
+	err := i.svc.DeleteCollection(ctx, request.Id, request.Uid)
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.DeleteCollectionResponse{}, nil
+}
+
+func (i *InteractiveServiceServer) ListCollections(ctx context.Context, request *intrv1.ListCollectionsRequest) (*intrv1.ListCollectionsResponse, error) {
+	cols, err := i.svc.ListCollections(ctx, request.Uid)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*intrv1.Collection, 0, len(cols))
+	for _, c := range cols {
+		res = append(res, &intrv1.Collection{
+			Id:    c.Id,
+			Uid:   c.Uid,
+			Name:  c.Name,
+			Ctime: c.Ctime.UnixMilli(),
+			Utime: c.Utime.UnixMilli(),
+		})
+	}
+	return &intrv1.ListCollectionsResponse{Collections: res}, nil
+}
+
+func (i *InteractiveServiceServer) ListCollection(ctx context.Context, request *intrv1.ListCollectionRequest) (*intrv1.ListCollectionResponse, error) {
+	items, err := i.svc.ListCollection(ctx, request.Uid, request.Cid,
+		int(request.Offset), int(request.Limit))
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	res := make([]*intrv1.CollectionItem, 0, len(items))
+	for _, item := range items {
+		res = append(res, &intrv1.CollectionItem{
+			Cid:   item.Cid,
+			Biz:   item.Biz,
+			BizId: item.BizId,
+			Ctime: item.Ctime.UnixMilli(),
+		})
+	}
+	return &intrv1.ListCollectionResponse{Items: res}, nil
+}
+
+// toStatusErr 业务错误转成 grpc 的错误码，调用方才能区分
+func (i *InteractiveServiceServer) toStatusErr(err error) error {
+	switch {
+	case errors.Is(err, service.ErrCollectionNotFound),
+		errors.Is(err, service.ErrBizNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrIllegalCollection),
+		errors.Is(err, service.ErrUnknownBiz):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
 }
 
 func (i InteractiveServiceServer) Get(ctx context.Context, request *intrv1.GetRequest) (*intrv1.GetResponse, error) {
 	res, err := i.svc.Get(ctx, request.Biz, request.BizId, request.Uid)
 	if err != nil {
-		return nil, err
+		return nil, i.toStatusErr(err)
 	}
 	return &intrv1.GetResponse{
 		Intr: i.toDTO(res),
@@ -64,7 +166,7 @@ func (i InteractiveServiceServer) Get(ctx context.Context, request *intrv1.GetRe
 func (i InteractiveServiceServer) GetByIds(ctx context.Context, request *intrv1.GetByIdsRequest) (*intrv1.GetByIdsResponse, error) {
 	res, err := i.svc.GetByIds(ctx, request.Biz, request.Ids)
 	if err != nil {
-		return nil, err
+		return nil, i.toStatusErr(err)
 	}
 	m := make(map[int64]*intrv1.Interactive, len(res))
 	for bizId, intr := range res {
@@ -75,6 +177,66 @@ func (i InteractiveServiceServer) GetByIds(ctx context.Context, request *intrv1.
 	}, nil
 }
 
+func (i *InteractiveServiceServer) ListHistory(ctx context.Context, request *intrv1.ListHistoryRequest) (*intrv1.ListHistoryResponse, error) {
+	if request.Uid <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "uid 错误")
+	}
+	var cur domain.HistoryCursor
+	if request.CursorReadTime > 0 {
+		cur = domain.HistoryCursor{
+			ReadTime: time.UnixMilli(request.CursorReadTime),
+			Id:       request.CursorId,
+		}
+	}
+	records, err := i.svc.ListHistory(ctx, request.Uid, cur, int(request.Limit))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*intrv1.HistoryRecord, 0, len(records))
+	for _, r := range records {
+		res = append(res, &intrv1.HistoryRecord{
+			Id:       r.Id,
+			Biz:      r.Biz,
+			BizId:    r.BizId,
+			Uid:      r.Uid,
+			ReadTime: r.ReadTime.UnixMilli(),
+		})
+	}
+	return &intrv1.ListHistoryResponse{
+		Records: res,
+	}, nil
+}
+
+func (i *InteractiveServiceServer) DeleteHistory(ctx context.Context, request *intrv1.DeleteHistoryRequest) (*intrv1.DeleteHistoryResponse, error) {
+	if request.Uid <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "uid 错误")
+	}
+	err := i.svc.DeleteHistory(ctx, request.Uid, request.Biz, request.BizId)
+	return &intrv1.DeleteHistoryResponse{}, err
+}
+
+func (i *InteractiveServiceServer) ClearHistory(ctx context.Context, request *intrv1.ClearHistoryRequest) (*intrv1.ClearHistoryResponse, error) {
+	if request.Uid <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "uid 错误")
+	}
+	err := i.svc.ClearHistory(ctx, request.Uid)
+	return &intrv1.ClearHistoryResponse{}, err
+}
+
+func (i InteractiveServiceServer) GetByIdsWithUser(ctx context.Context, request *intrv1.GetByIdsWithUserRequest) (*intrv1.GetByIdsWithUserResponse, error) {
+	res, err := i.svc.GetByIdsWithUser(ctx, request.Biz, request.Ids, request.Uid)
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	m := make(map[int64]*intrv1.Interactive, len(res))
+	for bizId, intr := range res {
+		m[bizId] = i.toDTO(intr)
+	}
+	return &intrv1.GetByIdsWithUserResponse{
+		Intrs: m,
+	}, nil
+}
+
 // DTO data transfer object
 func (i *InteractiveServiceServer) toDTO(intr domain.Interactive) *intrv1.Interactive {
 	return &intrv1.Interactive{
@@ -8,7 +8,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -35,6 +38,10 @@ func (s *InteractiveTestSuite) TearDownSuite() {
 	assert.NoError(s.T(), err)
 	err = s.db.Exec("TRUNCATE TABLE `user_like_bizs`").Error
 	assert.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `history_records`").Error
+	assert.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `collections`").Error
+	assert.NoError(s.T(), err)
 	// 清空 Redis
 	err = s.rdb.FlushDB(ctx).Err()
 	assert.NoError(s.T(), err)
@@ -821,6 +828,296 @@ func (s *InteractiveTestSuite) TestGetByIds() {
 	}
 }
 
+func (s *InteractiveTestSuite) TestHistory() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	// 同一篇帖子读两次只有一条记录，阅读时间是第二次的
+	for _, r := range []dao.HistoryRecord{
+		{Id: 1, Uid: 123, Biz: "test", BizId: 1, Ctime: 100, Utime: 100},
+		{Id: 2, Uid: 123, Biz: "test", BizId: 2, Ctime: 200, Utime: 200},
+		{Id: 3, Uid: 123, Biz: "test", BizId: 3, Ctime: 200, Utime: 200},
+		{Id: 4, Uid: 234, Biz: "test", BizId: 1, Ctime: 300, Utime: 300},
+	} {
+		err := s.db.WithContext(ctx).Create(&r).Error
+		assert.NoError(t, err)
+	}
+	err := dao.NewGORMHistoryRecordDAO(s.db).Upsert(ctx, dao.HistoryRecord{
+		Uid: 123, Biz: "test", BizId: 1,
+	})
+	assert.NoError(t, err)
+	var cnt int64
+	err = s.db.WithContext(ctx).Model(&dao.HistoryRecord{}).
+		Where("uid = ?", 123).Count(&cnt).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), cnt)
+
+	svc := startup2.InitInteractiveGRPCService()
+	resp, err := svc.ListHistory(ctx, &intrv1.ListHistoryRequest{Uid: 123, Limit: 2})
+	assert.NoError(t, err)
+	ids := func(records []*intrv1.HistoryRecord) []int64 {
+		res := make([]int64, 0, len(records))
+		for _, r := range records {
+			res = append(res, r.BizId)
+		}
+		return res
+	}
+	assert.Equal(t, []int64{1, 3}, ids(resp.Records))
+	// 同一个阅读时间按照 id 倒序，翻页不会漏
+	last := resp.Records[1]
+	resp, err = svc.ListHistory(ctx, &intrv1.ListHistoryRequest{
+		Uid: 123, CursorReadTime: last.ReadTime, CursorId: last.Id, Limit: 2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, ids(resp.Records))
+
+	_, err = svc.DeleteHistory(ctx, &intrv1.DeleteHistoryRequest{Uid: 123, Biz: "test", BizId: 3})
+	assert.NoError(t, err)
+	resp, err = svc.ListHistory(ctx, &intrv1.ListHistoryRequest{Uid: 123, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids(resp.Records))
+
+	_, err = svc.ClearHistory(ctx, &intrv1.ClearHistoryRequest{Uid: 123})
+	assert.NoError(t, err)
+	resp, err = svc.ListHistory(ctx, &intrv1.ListHistoryRequest{Uid: 123, Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Records)
+	// 别人的阅读历史不受影响
+	resp, err = svc.ListHistory(ctx, &intrv1.ListHistoryRequest{Uid: 234, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, ids(resp.Records))
+}
+
+func (s *InteractiveTestSuite) TestCollection() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	svc := startup2.InitInteractiveGRPCService()
+	const uid = int64(567)
+
+	_, err := svc.CreateCollection(ctx, &intrv1.CreateCollectionRequest{Uid: uid, Name: " "})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	resp, err := svc.CreateCollection(ctx, &intrv1.CreateCollectionRequest{Uid: uid, Name: "Go"})
+	assert.NoError(t, err)
+	cid := resp.Id
+	_, err = svc.RenameCollection(ctx, &intrv1.RenameCollectionRequest{Id: cid, Uid: uid + 1, Name: "别人的"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = svc.RenameCollection(ctx, &intrv1.RenameCollectionRequest{Id: cid, Uid: uid, Name: "Golang"})
+	assert.NoError(t, err)
+	cols, err := svc.ListCollections(ctx, &intrv1.ListCollectionsRequest{Uid: uid})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(cols.Collections))
+	assert.Equal(t, "Golang", cols.Collections[0].Name)
+
+	// 不能收藏到别人的收藏夹
+	_, err = svc.Collect(ctx, &intrv1.CollectRequest{Biz: "test_col", BizId: 1, Uid: uid + 1, Cid: cid})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	for i := int64(1); i <= 3; i++ {
+		_, err = svc.Collect(ctx, &intrv1.CollectRequest{Biz: "test_col", BizId: i, Uid: uid, Cid: cid})
+		assert.NoError(t, err)
+	}
+	// 缓存里面有数据的时候也要一起扣减
+	err = s.rdb.HSet(ctx, "interactive:test_col:1", "collect_cnt", 1).Err()
+	assert.NoError(t, err)
+
+	_, err = svc.CancelCollect(ctx, &intrv1.CancelCollectRequest{Biz: "test_col", BizId: 3, Uid: uid})
+	assert.NoError(t, err)
+	// 重复取消不会再扣减
+	_, err = svc.CancelCollect(ctx, &intrv1.CancelCollectRequest{Biz: "test_col", BizId: 3, Uid: uid})
+	assert.NoError(t, err)
+	var intr dao.Interactive
+	err = s.db.Where("biz = ? AND biz_id = ?", "test_col", 3).First(&intr).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), intr.CollectCnt)
+
+	items, err := svc.ListCollection(ctx, &intrv1.ListCollectionRequest{Uid: uid, Cid: cid, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(items.Items))
+
+	_, err = svc.DeleteCollection(ctx, &intrv1.DeleteCollectionRequest{Id: cid, Uid: uid})
+	assert.NoError(t, err)
+	for i := int64(1); i <= 2; i++ {
+		err = s.db.Where("biz = ? AND biz_id = ?", "test_col", i).First(&intr).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), intr.CollectCnt)
+	}
+	cnt, err := s.rdb.HGet(ctx, "interactive:test_col:1", "collect_cnt").Int64()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), cnt)
+	_, err = svc.ListCollection(ctx, &intrv1.ListCollectionRequest{Uid: uid, Cid: cid, Limit: 10})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func (s *InteractiveTestSuite) TestGetByIdsWithUser() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	const uid = int64(678)
+	err := s.db.WithContext(ctx).Create(&dao.Interactive{
+		Biz: "test_user", BizId: 1, ReadCnt: 10, LikeCnt: 1, CollectCnt: 1,
+	}).Error
+	assert.NoError(t, err)
+	err = s.db.WithContext(ctx).Create(&dao.UserLikeBiz{
+		Uid: uid, Biz: "test_user", BizId: 1, Status: 1,
+	}).Error
+	assert.NoError(t, err)
+	// 取消了的点赞不算
+	err = s.db.WithContext(ctx).Create(&dao.UserLikeBiz{
+		Uid: uid, Biz: "test_user", BizId: 2, Status: 0,
+	}).Error
+	assert.NoError(t, err)
+	err = s.db.WithContext(ctx).Create(&dao.UserCollectionBiz{
+		Uid: uid, Biz: "test_user", BizId: 1,
+	}).Error
+	assert.NoError(t, err)
+
+	svc := startup2.InitInteractiveGRPCService()
+	req := &intrv1.GetByIdsWithUserRequest{Biz: "test_user", Ids: []int64{1, 2, 3}, Uid: uid}
+	want := map[int64]*intrv1.Interactive{
+		1: {Biz: "test_user", BizId: 1, ReadCnt: 10, LikeCnt: 1, CollectCnt: 1, Liked: true, Collected: true},
+		2: {Biz: "test_user", BizId: 2},
+		3: {Biz: "test_user", BizId: 3},
+	}
+	resp, err := svc.GetByIdsWithUser(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, want, resp.Intrs)
+	// 点赞集合回写到了缓存
+	ok, err := s.rdb.SIsMember(ctx, "interactive:user_likes:test_user:678", 1).Result()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// 点赞和取消点赞同步更新点赞集合
+	_, err = svc.Like(ctx, &intrv1.LikeRequest{Biz: "test_user", BizId: 3, Uid: uid})
+	assert.NoError(t, err)
+	_, err = svc.CancelLike(ctx, &intrv1.CancelLikeRequest{Biz: "test_user", BizId: 1, Uid: uid})
+	assert.NoError(t, err)
+	resp, err = svc.GetByIdsWithUser(ctx, req)
+	assert.NoError(t, err)
+	assert.False(t, resp.Intrs[1].Liked)
+	assert.True(t, resp.Intrs[3].Liked)
+}
+
+func (s *InteractiveTestSuite) TestBatchIncrReadCnt() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	err := s.db.WithContext(ctx).Create(&dao.Interactive{
+		Biz: "test_batch", BizId: 1, ReadCnt: 10,
+	}).Error
+	assert.NoError(t, err)
+
+	// 已有的加上 N，没有的新建
+	err = dao.NewGORMInteractiveDAO(s.db).BatchIncrReadCnt(ctx,
+		[]string{"test_batch", "test_batch"}, []int64{1, 2}, []int64{5, 3})
+	assert.NoError(t, err)
+	var intrs []dao.Interactive
+	err = s.db.WithContext(ctx).Where("biz = ?", "test_batch").
+		Order("biz_id ASC").Find(&intrs).Error
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(intrs))
+	assert.Equal(t, int64(15), intrs[0].ReadCnt)
+	assert.Equal(t, int64(3), intrs[1].ReadCnt)
+}
+
+func (s *InteractiveTestSuite) TestUnknownBiz() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	svc := startup2.InitInteractiveGRPCService()
+
+	// 没有注册过的 biz 不能写
+	_, err := svc.Like(ctx, &intrv1.LikeRequest{Biz: "unknown", BizId: 1, Uid: 123})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = svc.IncrReadCnt(ctx, &intrv1.IncrReadCntRequest{Biz: "unknown", BizId: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = svc.GetByIds(ctx, &intrv1.GetByIdsRequest{Biz: "unknown", Ids: []int64{1}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	// 资源不存在
+	_, err = svc.Collect(ctx, &intrv1.CollectRequest{Biz: "test", BizId: 0, Uid: 123})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	var cnt int64
+	err = s.db.WithContext(ctx).Model(&dao.Interactive{}).
+		Where("biz = ?", "unknown").Count(&cnt).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), cnt)
+}
+
+func (s *InteractiveTestSuite) TestLikeIdempotent() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	svc := startup2.InitInteractiveGRPCService()
+	const bizId, uid = int64(100), int64(125)
+	likeCnt := func() int64 {
+		var intr dao.Interactive
+		err := s.db.Where("biz = ? AND biz_id = ?", "test", bizId).First(&intr).Error
+		assert.NoError(t, err)
+		return intr.LikeCnt
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := svc.Like(ctx, &intrv1.LikeRequest{Biz: "test", BizId: bizId, Uid: uid})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(1), likeCnt())
+	for i := 0; i < 2; i++ {
+		_, err := svc.CancelLike(ctx, &intrv1.CancelLikeRequest{Biz: "test", BizId: bizId, Uid: uid})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(0), likeCnt())
+	// 没有点过赞的人取消也不会扣成负数
+	_, err := svc.CancelLike(ctx, &intrv1.CancelLikeRequest{Biz: "test", BizId: bizId, Uid: uid + 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), likeCnt())
+}
+
+func (s *InteractiveTestSuite) TestReconcile() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	// 1 数据库偏了，2 数据库对的但是缓存偏了，3 都是对的
+	err := s.db.WithContext(ctx).Create([]dao.Interactive{
+		{Biz: "test_reconcile", BizId: 1, LikeCnt: -1, CollectCnt: 5},
+		{Biz: "test_reconcile", BizId: 2, LikeCnt: 1},
+		{Biz: "test_reconcile", BizId: 3, LikeCnt: 1},
+	}).Error
+	assert.NoError(t, err)
+	err = s.db.WithContext(ctx).Create([]dao.UserLikeBiz{
+		{Biz: "test_reconcile", BizId: 1, Uid: 1, Status: 1},
+		{Biz: "test_reconcile", BizId: 1, Uid: 2, Status: 1},
+		{Biz: "test_reconcile", BizId: 1, Uid: 3, Status: 0},
+		{Biz: "test_reconcile", BizId: 2, Uid: 1, Status: 1},
+		{Biz: "test_reconcile", BizId: 3, Uid: 1, Status: 1},
+	}).Error
+	assert.NoError(t, err)
+	err = s.db.WithContext(ctx).Create(&dao.UserCollectionBiz{
+		Biz: "test_reconcile", BizId: 1, Uid: 1,
+	}).Error
+	assert.NoError(t, err)
+	for bizId, cnt := range map[int64]int{1: 100, 2: 100, 3: 1} {
+		key := "interactive:test_reconcile:" + strconv.FormatInt(bizId, 10)
+		err = s.rdb.HSet(ctx, key, "like_cnt", cnt).Err()
+		assert.NoError(t, err)
+	}
+
+	// 别的测试留下来的数据也会被修正
+	cnt, err := startup2.InitReconcileService().Reconcile(ctx)
+	assert.NoError(t, err)
+	assert.True(t, cnt >= 3)
+	var intrs []dao.Interactive
+	err = s.db.WithContext(ctx).Where("biz = ?", "test_reconcile").
+		Order("biz_id ASC").Find(&intrs).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), intrs[0].LikeCnt)
+	assert.Equal(t, int64(1), intrs[0].CollectCnt)
+	assert.Equal(t, int64(1), intrs[1].LikeCnt)
+	// 有偏差的缓存删掉，没有偏差的留着
+	exists, err := s.rdb.Exists(ctx, "interactive:test_reconcile:1",
+		"interactive:test_reconcile:2", "interactive:test_reconcile:3").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
+}
+
 func TestInteractiveService(t *testing.T) {
 	suite.Run(t, &InteractiveTestSuite{})
 }
@@ -0,0 +1,16 @@
+package startup
+
+import (
+	"geektime/webook/interactive/domain"
+	"geektime/webook/interactive/service"
+)
+
+// InitBizRegistry 测试里面用到的 biz 都要注册
+func InitBizRegistry() *service.BizRegistry {
+	r := service.NewBizRegistry()
+	for _, biz := range []string{domain.BizArticle, domain.BizSeries, domain.BizComment,
+		"test", "test_col", "test_user", "test_batch", "test_reconcile"} {
+		r.Register(biz, service.IdValidator)
+	}
+	return r
+}
@@ -3,6 +3,7 @@
 package startup
 
 import (
+	"geektime/webook/interactive/events"
 	"geektime/webook/interactive/grpc"
 	repository2 "geektime/webook/interactive/repository"
 	cache2 "geektime/webook/interactive/repository/cache"
@@ -16,17 +17,31 @@ var thirdPartySet = wire.NewSet( // 第三方依赖
 	InitRedis, InitDB,
 	InitSaramaClient,
 	InitSyncProducer,
-	InitLogger)
+	InitLogger,
+	InitBizRegistry)
 
 var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO,
 	cache2.NewInteractiveRedisCache,
 	repository2.NewCachedInteractiveRepository,
+	dao2.NewGORMHistoryRecordDAO,
+	repository2.NewHistoryRecordRepository,
+	dao2.NewGORMCollectionDAO,
+	repository2.NewCollectionRepository,
+	events.NewSaramaChangeProducer,
 	service2.NewInteractiveService,
 )
 
 func InitInteractiveService() service2.InteractiveService {
 	wire.Build(thirdPartySet, interactiveSvcSet)
-	return service2.NewInteractiveService(nil)
+	return service2.NewInteractiveService(nil, nil, nil, nil, nil, nil)
+}
+
+func InitReconcileService() service2.ReconcileService {
+	wire.Build(thirdPartySet, interactiveSvcSet,
+		dao2.NewGORMReconcileDAO,
+		repository2.NewReconcileRepository,
+		service2.NewReconcileService)
+	return nil
 }
 
 func InitInteractiveGRPCService() *grpc.InteractiveServiceServer {
@@ -7,6 +7,7 @@
 package startup
 
 import (
+	"geektime/webook/interactive/events"
 	"geektime/webook/interactive/grpc"
 	"geektime/webook/interactive/repository"
 	"geektime/webook/interactive/repository/cache"
@@ -24,10 +25,29 @@ func InitInteractiveService() service.InteractiveService {
 	cmdable := InitRedis()
 	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
-	interactiveService := service.NewInteractiveService(interactiveRepository)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewHistoryRecordRepository(historyRecordDAO)
+	collectionDAO := dao.NewGORMCollectionDAO(db)
+	collectionRepository := repository.NewCollectionRepository(collectionDAO, interactiveCache, loggerV1)
+	bizRegistry := InitBizRegistry()
+	client := InitSaramaClient()
+	syncProducer := InitSyncProducer(client)
+	changeProducer := events.NewSaramaChangeProducer(syncProducer)
+	interactiveService := service.NewInteractiveService(interactiveRepository, historyRecordRepository, collectionRepository, bizRegistry, changeProducer, loggerV1)
 	return interactiveService
 }
 
+func InitReconcileService() service.ReconcileService {
+	db := InitDB()
+	reconcileDAO := dao.NewGORMReconcileDAO(db)
+	cmdable := InitRedis()
+	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
+	loggerV1 := InitLogger()
+	reconcileRepository := repository.NewReconcileRepository(reconcileDAO, interactiveCache, loggerV1)
+	reconcileService := service.NewReconcileService(reconcileRepository, loggerV1)
+	return reconcileService
+}
+
 func InitInteractiveGRPCService() *grpc.InteractiveServiceServer {
 	db := InitDB()
 	interactiveDAO := dao.NewGORMInteractiveDAO(db)
@@ -35,7 +55,15 @@ func InitInteractiveGRPCService() *grpc.InteractiveServiceServer {
 	cmdable := InitRedis()
 	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
-	interactiveService := service.NewInteractiveService(interactiveRepository)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewHistoryRecordRepository(historyRecordDAO)
+	collectionDAO := dao.NewGORMCollectionDAO(db)
+	collectionRepository := repository.NewCollectionRepository(collectionDAO, interactiveCache, loggerV1)
+	bizRegistry := InitBizRegistry()
+	client := InitSaramaClient()
+	syncProducer := InitSyncProducer(client)
+	changeProducer := events.NewSaramaChangeProducer(syncProducer)
+	interactiveService := service.NewInteractiveService(interactiveRepository, historyRecordRepository, collectionRepository, bizRegistry, changeProducer, loggerV1)
 	interactiveServiceServer := grpc.NewInteractiveServiceServer(interactiveService)
 	return interactiveServiceServer
 }
@@ -47,6 +75,7 @@ var thirdPartySet = wire.NewSet(
 	InitRedis, InitDB,
 	InitSaramaClient,
 	InitSyncProducer,
-	InitLogger)
+	InitLogger,
+	InitBizRegistry)
 
-var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO, cache.NewInteractiveRedisCache, repository.NewCachedInteractiveRepository, service.NewInteractiveService)
+var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO, cache.NewInteractiveRedisCache, repository.NewCachedInteractiveRepository, dao.NewGORMHistoryRecordDAO, repository.NewHistoryRecordRepository, dao.NewGORMCollectionDAO, repository.NewCollectionRepository, events.NewSaramaChangeProducer, service.NewInteractiveService)
@@ -0,0 +1,16 @@
+package ioc
+
+import (
+	"geektime/webook/interactive/domain"
+	"geektime/webook/interactive/service"
+)
+
+// InitBizRegistry 帖子、合集和评论的数据都在别的服务里面，
+// 资源存不存在由持有数据的服务在调用交互服务之前确认，这里只校验 id
+func InitBizRegistry() *service.BizRegistry {
+	r := service.NewBizRegistry()
+	r.Register(domain.BizArticle, service.IdValidator)
+	r.Register(domain.BizSeries, service.IdValidator)
+	r.Register(domain.BizComment, service.IdValidator)
+	return r
+}
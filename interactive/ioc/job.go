@@ -0,0 +1,24 @@
+package ioc
+
+import (
+	"geektime/webook/interactive/job"
+	"geektime/webook/interactive/service"
+	"geektime/webook/pkg/logger"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+func InitReconcileJob(svc service.ReconcileService, l logger.LoggerV1) *job.ReconcileJob {
+	//全表扫描，给足时间
+	return job.NewReconcileJob(svc, l, time.Minute*30)
+}
+
+func InitJobs(rjob *job.ReconcileJob) *cron.Cron {
+	expr := cron.New(cron.WithSeconds())
+	//每天凌晨三点半对账
+	_, err := expr.AddJob("0 30 3 * * *", rjob)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
@@ -36,6 +36,8 @@ func InitSyncProducer(c sarama.Client) sarama.SyncProducer {
 }
 
 func InitConsumers(
-	c1 *events2.InteractiveReadEventConsumer, fixConsumer *fixer.Consumer[dao.Interactive]) []saramax.Consumer {
-	return []saramax.Consumer{c1, fixConsumer}
+	c1 *events2.InteractiveReadEventConsumer, c2 *events2.InteractiveDeletedEventConsumer,
+	c3 *events2.HistoryRecordConsumer,
+	fixConsumer *fixer.Consumer[dao.Interactive]) []saramax.Consumer {
+	return []saramax.Consumer{c1, c2, c3, fixConsumer}
 }
@@ -0,0 +1,37 @@
+package job
+
+import (
+	"context"
+	"geektime/webook/interactive/service"
+	"geektime/webook/pkg/logger"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+var _ cron.Job = &ReconcileJob{}
+
+// ReconcileJob 定时对账，修正本身是幂等的，多个节点同时跑也没有问题
+type ReconcileJob struct {
+	svc     service.ReconcileService
+	l       logger.LoggerV1
+	timeout time.Duration
+}
+
+func NewReconcileJob(svc service.ReconcileService, l logger.LoggerV1,
+	timeout time.Duration) *ReconcileJob {
+	return &ReconcileJob{svc: svc, l: l, timeout: timeout}
+}
+
+func (r *ReconcileJob) Run() {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	start := time.Now()
+	cnt, err := r.svc.Reconcile(ctx)
+	if err != nil {
+		r.l.Error("对账失败", logger.Int("fixed", cnt), logger.Error(err))
+		return
+	}
+	r.l.Info("对账完成",
+		logger.Int("fixed", cnt),
+		logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+}
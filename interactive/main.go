@@ -3,11 +3,14 @@ package main
 import (
 	"fmt"
 	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/viper"
+	"net/http"
 )
 
 func main() {
 	initViper()
+	initPrometheus()
 	app := InitApp()
 	//启动所有消费者
 	for _, c := range app.consumers {
@@ -16,6 +19,11 @@ func main() {
 			panic(err)
 		}
 	}
+	//启动定时对账
+	app.cron.Start()
+	defer func() {
+		<-app.cron.Stop().Done()
+	}()
 	//不停机数据迁移
 	go func() {
 		//后台管理端口
@@ -31,6 +39,19 @@ func main() {
 	}
 }
 
+// 初始化prometheus，消费者刷新阅读数的指标也在这里
+func initPrometheus() {
+	go func() {
+		// 专门给 prometheus 用的端口
+		http.Handle("/metrics", promhttp.Handler())
+		// 端口被占用的时候直接退出，不然指标会悄无声息地没了
+		err := http.ListenAndServe(":8083", nil)
+		if err != nil {
+			panic(err)
+		}
+	}()
+}
+
 func initViper() {
 	viper.SetConfigFile("config/config.yaml")
 	err := viper.ReadInConfig() // 查找并读取配置文件
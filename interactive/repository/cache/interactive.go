@@ -13,19 +13,41 @@ import (
 var (
 	//go:embed lua/interactive_incr_cnt.lua
 	luaIncrCnt string
+	//go:embed lua/user_like.lua
+	luaUserLike string
 )
 
 const fieldReadCnt = "read_cnt"
 const fieldLikeCnt = "like_cnt"
 const fieldCollectCnt = "collect_cnt"
 
+const (
+	// userLikesExist 点赞集合里面一定有的成员，用来区分空集合和没有缓存
+	userLikesExist = -1
+	// userLikesComplete 有这个成员说明用户所有的点赞都在集合里面
+	userLikesComplete = 0
+)
+
 type InteractiveCache interface {
 	IncrReadCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	// BatchIncrReadCntIfPresent 和数据库一样一次加 cnts[i]
+	BatchIncrReadCntIfPresent(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error
 	IncrLikeCntIfPresent(ctx context.Context, biz string, id int64) error
 	DecrLikeCntIfPresent(ctx context.Context, biz string, id int64) error
 	IncrCollectCntIfPresent(ctx context.Context, biz string, id int64) error
+	DecrCollectCntIfPresent(ctx context.Context, biz string, id int64) error
 	Get(ctx context.Context, biz string, id int64) (domain.Interactive, error)
 	Set(ctx context.Context, biz string, bizId int64, res domain.Interactive) error
+	Del(ctx context.Context, biz string, bizId int64) error
+
+	// GetUserLikes ids 里面哪些在用户的点赞集合里面，没有缓存的时候返回 ErrKeyNotExist
+	// complete 为 false 的时候集合里面只有最近的点赞，不在集合里面的要回查数据库
+	GetUserLikes(ctx context.Context, biz string, uid int64, ids []int64) (liked []bool, complete bool, err error)
+	SetUserLikes(ctx context.Context, biz string, uid int64, likedIds []int64, complete bool) error
+	AddUserLikeIfPresent(ctx context.Context, biz string, uid int64, bizId int64) error
+	RemoveUserLikeIfPresent(ctx context.Context, biz string, uid int64, bizId int64) error
+	// DelUserLikes 点赞集合更新失败的时候删掉，下次查询从数据库重建
+	DelUserLikes(ctx context.Context, biz string, uid int64) error
 }
 
 type InteractiveRedisCache struct {
@@ -78,6 +100,12 @@ func (i *InteractiveRedisCache) IncrCollectCntIfPresent(ctx context.Context,
 	return i.client.Eval(ctx, luaIncrCnt, []string{key}, fieldCollectCnt, 1).Err()
 }
 
+func (i *InteractiveRedisCache) DecrCollectCntIfPresent(ctx context.Context,
+	biz string, id int64) error {
+	key := i.key(biz, id)
+	return i.client.Eval(ctx, luaIncrCnt, []string{key}, fieldCollectCnt, -1).Err()
+}
+
 func (i *InteractiveRedisCache) IncrLikeCntIfPresent(ctx context.Context,
 	biz string, bizId int64) error {
 	key := i.key(biz, bizId)
@@ -98,6 +126,74 @@ func (i *InteractiveRedisCache) IncrReadCntIfPresent(ctx context.Context,
 	return i.client.Eval(ctx, luaIncrCnt, []string{key}, fieldReadCnt, 1).Err()
 }
 
+func (i *InteractiveRedisCache) BatchIncrReadCntIfPresent(ctx context.Context,
+	bizs []string, bizIds []int64, cnts []int64) error {
+	_, err := i.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for j := 0; j < len(bizs); j++ {
+			pipe.Eval(ctx, luaIncrCnt, []string{i.key(bizs[j], bizIds[j])}, fieldReadCnt, cnts[j])
+		}
+		return nil
+	})
+	return err
+}
+
+func (i *InteractiveRedisCache) Del(ctx context.Context, biz string, bizId int64) error {
+	return i.client.Del(ctx, i.key(biz, bizId)).Err()
+}
+
+func (i *InteractiveRedisCache) GetUserLikes(ctx context.Context, biz string, uid int64,
+	ids []int64) ([]bool, bool, error) {
+	members := make([]any, 0, len(ids)+2)
+	members = append(members, userLikesExist, userLikesComplete)
+	for _, id := range ids {
+		members = append(members, id)
+	}
+	res, err := i.client.SMIsMember(ctx, i.userLikesKey(biz, uid), members...).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if !res[0] {
+		return nil, false, ErrKeyNotExist
+	}
+	return res[2:], res[1], nil
+}
+
+func (i *InteractiveRedisCache) SetUserLikes(ctx context.Context, biz string, uid int64,
+	likedIds []int64, complete bool) error {
+	members := make([]any, 0, len(likedIds)+2)
+	members = append(members, userLikesExist)
+	if complete {
+		members = append(members, userLikesComplete)
+	}
+	for _, id := range likedIds {
+		members = append(members, id)
+	}
+	key := i.userLikesKey(biz, uid)
+	_, err := i.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.SAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, time.Minute*15)
+		return nil
+	})
+	return err
+}
+
+func (i *InteractiveRedisCache) AddUserLikeIfPresent(ctx context.Context, biz string, uid int64, bizId int64) error {
+	return i.client.Eval(ctx, luaUserLike, []string{i.userLikesKey(biz, uid)}, "SADD", bizId).Err()
+}
+
+func (i *InteractiveRedisCache) RemoveUserLikeIfPresent(ctx context.Context, biz string, uid int64, bizId int64) error {
+	return i.client.Eval(ctx, luaUserLike, []string{i.userLikesKey(biz, uid)}, "SREM", bizId).Err()
+}
+
+func (i *InteractiveRedisCache) DelUserLikes(ctx context.Context, biz string, uid int64) error {
+	return i.client.Del(ctx, i.userLikesKey(biz, uid)).Err()
+}
+
+func (i *InteractiveRedisCache) userLikesKey(biz string, uid int64) string {
+	return fmt.Sprintf("interactive:user_likes:%s:%d", biz, uid)
+}
+
 func (i *InteractiveRedisCache) key(biz string, bizId int64) string {
 	return fmt.Sprintf("interactive:%s:%d", biz, bizId)
 }
@@ -0,0 +1,14 @@
+-- 用户点赞集合
+local key = KEYS[1]
+-- SADD 或者 SREM
+local cmd = ARGV[1]
+local bizId = ARGV[2]
+local exist=redis.call("EXISTS", key)
+
+-- 集合不在缓存里面的时候不能只加一个，不然会被当成完整的数据
+if exist == 1 then
+    redis.call(cmd, key, bizId)
+    return 1
+else
+    return 0
+end
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"geektime/webook/interactive/domain"
+	"geektime/webook/interactive/repository/cache"
+	"geektime/webook/interactive/repository/dao"
+	"geektime/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+var ErrCollectionNotFound = dao.ErrCollectionNotFound
+
+type CollectionRepository interface {
+	Create(ctx context.Context, c domain.Collection) (int64, error)
+	Rename(ctx context.Context, id int64, uid int64, name string) error
+	// Delete 删除收藏夹之后，缓存里面的收藏数也要一起扣减
+	Delete(ctx context.Context, id int64, uid int64) error
+	GetById(ctx context.Context, id int64) (domain.Collection, error)
+	ListByUid(ctx context.Context, uid int64) ([]domain.Collection, error)
+	ListItems(ctx context.Context, uid int64, cid int64, offset int, limit int) ([]domain.CollectionItem, error)
+}
+
+type collectionRepository struct {
+	dao   dao.CollectionDAO
+	cache cache.InteractiveCache
+	l     logger.LoggerV1
+}
+
+func NewCollectionRepository(dao dao.CollectionDAO,
+	cache cache.InteractiveCache, l logger.LoggerV1) CollectionRepository {
+	return &collectionRepository{dao: dao, cache: cache, l: l}
+}
+
+func (c *collectionRepository) Create(ctx context.Context, col domain.Collection) (int64, error) {
+	return c.dao.Insert(ctx, dao.Collection{
+		Uid:  col.Uid,
+		Name: col.Name,
+	})
+}
+
+func (c *collectionRepository) Rename(ctx context.Context, id int64, uid int64, name string) error {
+	return c.dao.UpdateName(ctx, id, uid, name)
+}
+
+func (c *collectionRepository) Delete(ctx context.Context, id int64, uid int64) error {
+	items, err := c.dao.Delete(ctx, id, uid)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		// 数据库已经扣减了，缓存失败了等过期
+		er := c.cache.DecrCollectCntIfPresent(ctx, item.Biz, item.BizId)
+		if er != nil {
+			c.l.Error("扣减缓存收藏数失败",
+				logger.String("biz", item.Biz),
+				logger.Int64("bizId", item.BizId),
+				logger.Error(er))
+		}
+	}
+	return nil
+}
+
+func (c *collectionRepository) GetById(ctx context.Context, id int64) (domain.Collection, error) {
+	col, err := c.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Collection{}, err
+	}
+	return c.toDomain(col), nil
+}
+
+func (c *collectionRepository) ListByUid(ctx context.Context, uid int64) ([]domain.Collection, error) {
+	cols, err := c.dao.ListByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(cols, func(idx int, src dao.Collection) domain.Collection {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *collectionRepository) ListItems(ctx context.Context, uid int64, cid int64, offset int, limit int) ([]domain.CollectionItem, error) {
+	items, err := c.dao.ListItems(ctx, uid, cid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(items, func(idx int, src dao.UserCollectionBiz) domain.CollectionItem {
+		return domain.CollectionItem{
+			Cid:   src.Cid,
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (c *collectionRepository) toDomain(col dao.Collection) domain.Collection {
+	return domain.Collection{
+		Id:    col.Id,
+		Uid:   col.Uid,
+		Name:  col.Name,
+		Ctime: time.UnixMilli(col.Ctime),
+		Utime: time.UnixMilli(col.Utime),
+	}
+}
@@ -0,0 +1,139 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var ErrCollectionNotFound = errors.New("收藏夹不存在")
+
+// CollectionDAO 收藏夹，收藏记录本身还是 UserCollectionBiz
+type CollectionDAO interface {
+	Insert(ctx context.Context, c Collection) (int64, error)
+	// UpdateName 只能修改自己的收藏夹，找不到的时候返回 ErrCollectionNotFound
+	UpdateName(ctx context.Context, id int64, uid int64, name string) error
+	// Delete 连同收藏夹里面的收藏一起删除，并且扣减收藏数，返回被删除的收藏
+	Delete(ctx context.Context, id int64, uid int64) ([]UserCollectionBiz, error)
+	FindById(ctx context.Context, id int64) (Collection, error)
+	ListByUid(ctx context.Context, uid int64) ([]Collection, error)
+	// ListItems 收藏夹里面的收藏，最近收藏的在前面
+	ListItems(ctx context.Context, uid int64, cid int64, offset int, limit int) ([]UserCollectionBiz, error)
+}
+
+type GORMCollectionDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMCollectionDAO(db *gorm.DB) CollectionDAO {
+	return &GORMCollectionDAO{db: db}
+}
+
+func (g *GORMCollectionDAO) Insert(ctx context.Context, c Collection) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := g.db.WithContext(ctx).Create(&c).Error
+	return c.Id, err
+}
+
+func (g *GORMCollectionDAO) UpdateName(ctx context.Context, id int64, uid int64, name string) error {
+	res := g.db.WithContext(ctx).Model(&Collection{}).
+		Where("id = ? AND uid = ?", id, uid).
+		Updates(map[string]any{
+			"name":  name,
+			"utime": time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+func (g *GORMCollectionDAO) Delete(ctx context.Context, id int64, uid int64) ([]UserCollectionBiz, error) {
+	var items []UserCollectionBiz
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND uid = ?", id, uid).Delete(&Collection{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrCollectionNotFound
+		}
+		// 加锁读，避免并发收藏、取消收藏的记录没有扣减收藏数，或者被扣减两次
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uid = ? AND cid = ?", uid, id).Find(&items).Error
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		ids := make([]int64, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.Id)
+		}
+		// 只删除读出来的，收藏数也只扣减这些
+		err = tx.Where("id IN ?", ids).Delete(&UserCollectionBiz{}).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+		// 每一条收藏都要把对应资源的收藏数减 1
+		for _, item := range items {
+			err = tx.Model(&Interactive{}).
+				Where("biz = ? AND biz_id = ? AND collect_cnt > 0", item.Biz, item.BizId).
+				Updates(map[string]any{
+					"collect_cnt": gorm.Expr("`collect_cnt` - 1"),
+					"utime":       now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return items, err
+}
+
+func (g *GORMCollectionDAO) FindById(ctx context.Context, id int64) (Collection, error) {
+	var res Collection
+	err := g.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Collection{}, ErrCollectionNotFound
+	}
+	return res, err
+}
+
+func (g *GORMCollectionDAO) ListByUid(ctx context.Context, uid int64) ([]Collection, error) {
+	var res []Collection
+	err := g.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Order("ctime ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMCollectionDAO) ListItems(ctx context.Context, uid int64, cid int64, offset int, limit int) ([]UserCollectionBiz, error) {
+	var res []UserCollectionBiz
+	err := g.db.WithContext(ctx).
+		Where("uid = ? AND cid = ?", uid, cid).
+		Order("ctime DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// Collection 收藏夹
+type Collection struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"index"`
+	Name  string `gorm:"type:varchar(256)"`
+	Ctime int64
+	Utime int64
+}
@@ -75,7 +75,7 @@ func (d *DoubleWriteDAO) IncrReadCnt(ctx context.Context, biz string, bizId int6
 	}
 }
 
-func (d *DoubleWriteDAO) BatchIncrReadCnt(ctx context.Context, bizs []string, bizIds []int64) error {
+func (d *DoubleWriteDAO) BatchIncrReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error {
 	//TODO implement me
 	panic("implement me")
 }
@@ -142,6 +142,41 @@ func (d *DoubleWriteDAO) GetV1(ctx context.Context, biz string, id int64) (Inter
 	}
 }
 
+func (d *DoubleWriteDAO) DeleteBiz(ctx context.Context, biz string, bizId int64) error {
+	pattern := d.pattern.Load()
+	switch pattern {
+	case PatternSrcOnly:
+		return d.src.DeleteBiz(ctx, biz, bizId)
+	case PatternSrcFirst:
+		err := d.src.DeleteBiz(ctx, biz, bizId)
+		if err != nil {
+			return err
+		}
+		err = d.dst.DeleteBiz(ctx, biz, bizId)
+		if err != nil {
+			d.l.Error("双写删除dst 失败", logger.Error(err),
+				logger.Int64("biz_id", bizId),
+				logger.String("biz", biz))
+		}
+		return nil
+	case PatternDstFirst:
+		err := d.dst.DeleteBiz(ctx, biz, bizId)
+		if err == nil {
+			err1 := d.src.DeleteBiz(ctx, biz, bizId)
+			if err1 != nil {
+				d.l.Error("双写删除 src 失败", logger.Error(err1),
+					logger.Int64("biz_id", bizId),
+					logger.String("biz", biz))
+			}
+		}
+		return err
+	case PatternDstOnly:
+		return d.dst.DeleteBiz(ctx, biz, bizId)
+	default:
+		return errUnknownPattern
+	}
+}
+
 func (d *DoubleWriteDAO) GetByIds(ctx context.Context, biz string, ids []int64) ([]Interactive, error) {
 	//TODO implement me
 	panic("implement me")
@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type HistoryRecordDAO interface {
+	// Upsert 已经读过的只更新阅读时间
+	Upsert(ctx context.Context, r HistoryRecord) error
+	// List 按照阅读时间、id 倒序，从 (utime, id) 之后开始取，utime 为 0 的时候从头开始
+	List(ctx context.Context, uid int64, utime int64, id int64, limit int) ([]HistoryRecord, error)
+	Delete(ctx context.Context, uid int64, biz string, bizId int64) error
+	DeleteAll(ctx context.Context, uid int64) error
+}
+
+type GORMHistoryRecordDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMHistoryRecordDAO(db *gorm.DB) HistoryRecordDAO {
+	return &GORMHistoryRecordDAO{db: db}
+}
+
+func (g *GORMHistoryRecordDAO) Upsert(ctx context.Context, r HistoryRecord) error {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"utime": now,
+		}),
+	}).Create(&r).Error
+}
+
+func (g *GORMHistoryRecordDAO) List(ctx context.Context, uid int64, utime int64, id int64, limit int) ([]HistoryRecord, error) {
+	var res []HistoryRecord
+	db := g.db.WithContext(ctx).Where("uid = ?", uid)
+	if utime > 0 {
+		db = db.Where("utime < ? OR (utime = ? AND id < ?)", utime, utime, id)
+	}
+	err := db.Order("utime DESC, id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMHistoryRecordDAO) Delete(ctx context.Context, uid int64, biz string, bizId int64) error {
+	return g.db.WithContext(ctx).
+		Where("uid = ? AND biz = ? AND biz_id = ?", uid, biz, bizId).
+		Delete(&HistoryRecord{}).Error
+}
+
+func (g *GORMHistoryRecordDAO) DeleteAll(ctx context.Context, uid int64) error {
+	return g.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Delete(&HistoryRecord{}).Error
+}
+
+// HistoryRecord 阅读历史，Utime 就是最后一次阅读的时间
+type HistoryRecord struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 同一个人同一个资源只有一条记录
+	Uid   int64  `gorm:"uniqueIndex:uid_biz_type_id;index:uid_utime"`
+	Biz   string `gorm:"type:varchar(128);uniqueIndex:uid_biz_type_id"`
+	BizId int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	Ctime int64
+	// 按照阅读时间翻页
+	Utime int64 `gorm:"index:uid_utime"`
+}
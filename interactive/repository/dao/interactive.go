@@ -2,22 +2,30 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"geektime/webook/pkg/migrator"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"sort"
 	"time"
 )
 
 var (
 	ErrDataNotFound = gorm.ErrRecordNotFound
+	ErrLikeExists   = errors.New("已经点过赞了")
 )
 
 type InteractiveDAO interface {
 	IncrReadCnt(ctx context.Context, biz string, bizId int64) error
-	BatchIncrReadCnt(ctx context.Context, bizs []string, bizIds []int64) error
+	// BatchIncrReadCnt bizIds[i] 的阅读数加 cnts[i]，同一个资源只能出现一次
+	BatchIncrReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error
+	// InsertLikeInfo 已经点过赞的时候返回 ErrLikeExists，点赞数不变
 	InsertLikeInfo(ctx context.Context, biz string, id int64, uid int64) error
+	// DeleteLikeInfo 没有点过赞的时候返回 ErrDataNotFound，点赞数不变
 	DeleteLikeInfo(ctx context.Context, biz string, id int64, uid int64) error
 	InsertCollectionBiz(ctx context.Context, cb UserCollectionBiz) error
+	// DeleteCollectionBiz 取消收藏，没有收藏过的时候返回 ErrDataNotFound
+	DeleteCollectionBiz(ctx context.Context, biz string, id int64, uid int64) error
 
 	GetLikeInfo(ctx context.Context,
 		biz string, id int64, uid int64) (UserLikeBiz, error)
@@ -25,6 +33,14 @@ type InteractiveDAO interface {
 		biz string, id int64, uid int64) (UserCollectionBiz, error)
 	Get(ctx context.Context, biz string, id int64) (Interactive, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]Interactive, error)
+	// GetLikedBizIds ids 里面 uid 点赞了的
+	GetLikedBizIds(ctx context.Context, biz string, uid int64, ids []int64) ([]int64, error)
+	// GetCollectedBizIds ids 里面 uid 收藏了的
+	GetCollectedBizIds(ctx context.Context, biz string, uid int64, ids []int64) ([]int64, error)
+	// ListRecentLikedBizIds uid 最近点赞的，最近点赞的在前面
+	ListRecentLikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error)
+	// DeleteBiz 资源被彻底删除之后，清理计数、点赞和收藏记录
+	DeleteBiz(ctx context.Context, biz string, bizId int64) error
 }
 
 type GORMInteractiveDAO struct {
@@ -43,6 +59,32 @@ func (dao *GORMInteractiveDAO) GetByIds(ctx context.Context, biz string, ids []i
 	return res, err
 }
 
+func (dao *GORMInteractiveDAO) GetLikedBizIds(ctx context.Context, biz string, uid int64, ids []int64) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&UserLikeBiz{}).
+		Where("uid = ? AND biz = ? AND biz_id IN ? AND status = ?", uid, biz, ids, 1).
+		Pluck("biz_id", &res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) GetCollectedBizIds(ctx context.Context, biz string, uid int64, ids []int64) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&UserCollectionBiz{}).
+		Where("uid = ? AND biz = ? AND biz_id IN ?", uid, biz, ids).
+		Pluck("biz_id", &res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) ListRecentLikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&UserLikeBiz{}).
+		Where("uid = ? AND biz = ? AND status = ?", uid, biz, 1).
+		Order("utime DESC").
+		Limit(limit).
+		Pluck("biz_id", &res).Error
+	return res, err
+}
+
 func (dao *GORMInteractiveDAO) Get(ctx context.Context, biz string, id int64) (Interactive, error) {
 	var res Interactive
 	err := dao.db.WithContext(ctx).
@@ -97,28 +139,62 @@ func (dao *GORMInteractiveDAO) InsertCollectionBiz(ctx context.Context,
 	})
 }
 
+func (dao *GORMInteractiveDAO) DeleteCollectionBiz(ctx context.Context,
+	biz string, id int64, uid int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("uid = ? AND biz_id = ? AND biz = ?", uid, id, biz).
+			Delete(&UserCollectionBiz{})
+		if res.Error != nil {
+			return res.Error
+		}
+		// 没有收藏过就不能扣减收藏数
+		if res.RowsAffected == 0 {
+			return ErrDataNotFound
+		}
+		//收藏数量减1
+		return tx.Model(&Interactive{}).
+			Where("biz = ? AND biz_id = ? AND collect_cnt > 0", biz, id).
+			Updates(map[string]interface{}{
+				"collect_cnt": gorm.Expr("`collect_cnt` - 1"),
+				"utime":       now,
+			}).Error
+	})
+}
+
 // InsertLikeInfo 同时记录点赞记录和更新点赞数
+// 只有点赞记录真的从无到有的时候才加点赞数，重复点赞不会多加
 func (dao *GORMInteractiveDAO) InsertLikeInfo(ctx context.Context,
 	biz string, id int64, uid int64) error {
 	now := time.Now().UnixMilli()
 	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		//记录点赞记录
-		//因为可能数据库中已经存在了该数据，但是被软删除，所以是upsert操作
-		err := tx.Clauses(clause.OnConflict{
-			DoUpdates: clause.Assignments(map[string]interface{}{
+		//之前取消过点赞，记录被软删除了，恢复回来
+		res := tx.Model(&UserLikeBiz{}).
+			Where("uid = ? AND biz_id = ? AND biz = ? AND status = ?", uid, id, biz, 0).
+			Updates(map[string]interface{}{
 				"utime":  now,
 				"status": 1,
-			}),
-		}).Create(&UserLikeBiz{
-			Uid:    uid,
-			Biz:    biz,
-			BizId:  id,
-			Status: 1,
-			Utime:  now,
-			Ctime:  now,
-		}).Error
-		if err != nil {
-			return err
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			//第一次点赞，记录已经存在说明已经点过赞了
+			res = tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&UserLikeBiz{
+					Uid:    uid,
+					Biz:    biz,
+					BizId:  id,
+					Status: 1,
+					Utime:  now,
+					Ctime:  now,
+				})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return ErrLikeExists
+			}
 		}
 		//更新点赞数，upsert
 		return tx.WithContext(ctx).Clauses(clause.OnConflict{
@@ -141,18 +217,22 @@ func (dao *GORMInteractiveDAO) DeleteLikeInfo(ctx context.Context,
 	now := time.Now().UnixMilli()
 	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		//软删除点赞记录
-		err := tx.Model(&UserLikeBiz{}).
-			Where("uid=? AND biz_id = ? AND biz=?", uid, id, biz).
+		res := tx.Model(&UserLikeBiz{}).
+			Where("uid=? AND biz_id = ? AND biz=? AND status = ?", uid, id, biz, 1).
 			Updates(map[string]interface{}{
 				"utime":  now,
 				"status": 0,
-			}).Error
-		if err != nil {
-			return err
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		// 没有点过赞或者已经取消过了，不能再扣减
+		if res.RowsAffected == 0 {
+			return ErrDataNotFound
 		}
 		//点赞数量减1
 		return tx.Model(&Interactive{}).
-			Where("biz =? AND biz_id=?", biz, id).
+			Where("biz =? AND biz_id=? AND like_cnt > 0", biz, id).
 			Updates(map[string]interface{}{
 				"like_cnt": gorm.Expr("`like_cnt` - 1"),
 				"utime":    now,
@@ -179,11 +259,41 @@ func (dao *GORMInteractiveDAO) IncrReadCnt(ctx context.Context, biz string, bizI
 	}).Error
 }
 
-func (dao *GORMInteractiveDAO) BatchIncrReadCnt(ctx context.Context, bizs []string, bizIds []int64) error {
+// BatchIncrReadCnt 一条 upsert 语句，每个资源都是 read_cnt = read_cnt + N
+func (dao *GORMInteractiveDAO) BatchIncrReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error {
+	if len(bizs) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	intrs := make([]Interactive, 0, len(bizs))
+	for i := 0; i < len(bizs); i++ {
+		intrs = append(intrs, Interactive{
+			Biz:     bizs[i],
+			BizId:   bizIds[i],
+			ReadCnt: cnts[i],
+			Ctime:   now,
+			Utime:   now,
+		})
+	}
+	// 按照唯一索引排序，并发的批次加锁顺序一样，避免死锁
+	sort.Slice(intrs, func(i, j int) bool {
+		if intrs[i].Biz != intrs[j].Biz {
+			return intrs[i].Biz < intrs[j].Biz
+		}
+		return intrs[i].BizId < intrs[j].BizId
+	})
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"read_cnt": gorm.Expr("`read_cnt` + VALUES(`read_cnt`)"),
+			"utime":    now,
+		}),
+	}).Create(&intrs).Error
+}
+
+func (dao *GORMInteractiveDAO) DeleteBiz(ctx context.Context, biz string, bizId int64) error {
 	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		txDAO := NewGORMInteractiveDAO(tx)
-		for i := 0; i < len(bizs); i++ {
-			err := txDAO.IncrReadCnt(ctx, bizs[i], bizIds[i])
+		for _, entity := range []any{&UserLikeBiz{}, &UserCollectionBiz{}, &Interactive{}} {
+			err := tx.Where("biz = ? AND biz_id = ?", biz, bizId).Delete(entity).Error
 			if err != nil {
 				return err
 			}
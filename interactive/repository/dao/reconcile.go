@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ReconcileDAO 对账用，按照 id 扫计数表，再去明细表里面数
+type ReconcileDAO interface {
+	// ListInteractives 按照 id 升序，从 startId 之后开始取
+	ListInteractives(ctx context.Context, startId int64, limit int) ([]Interactive, error)
+	// CountLikes 每个 bizId 有效的点赞数，没有点赞的不在结果里面
+	CountLikes(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error)
+	// CountCollects 每个 bizId 的收藏数，没有收藏的不在结果里面
+	CountCollects(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error)
+	// Recount 用明细表重新算点赞数和收藏数
+	Recount(ctx context.Context, biz string, bizId int64) error
+}
+
+type GORMReconcileDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMReconcileDAO(db *gorm.DB) ReconcileDAO {
+	return &GORMReconcileDAO{db: db}
+}
+
+func (g *GORMReconcileDAO) ListInteractives(ctx context.Context, startId int64, limit int) ([]Interactive, error) {
+	var res []Interactive
+	err := g.db.WithContext(ctx).
+		Where("id > ?", startId).
+		Order("id ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+type bizCnt struct {
+	BizId int64
+	Cnt   int64
+}
+
+func (g *GORMReconcileDAO) CountLikes(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	var cnts []bizCnt
+	err := g.db.WithContext(ctx).Model(&UserLikeBiz{}).
+		Select("biz_id, COUNT(*) AS cnt").
+		Where("biz = ? AND biz_id IN ? AND status = ?", biz, bizIds, 1).
+		Group("biz_id").
+		Scan(&cnts).Error
+	return g.toMap(cnts), err
+}
+
+func (g *GORMReconcileDAO) CountCollects(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	var cnts []bizCnt
+	err := g.db.WithContext(ctx).Model(&UserCollectionBiz{}).
+		Select("biz_id, COUNT(*) AS cnt").
+		Where("biz = ? AND biz_id IN ?", biz, bizIds).
+		Group("biz_id").
+		Scan(&cnts).Error
+	return g.toMap(cnts), err
+}
+
+// Recount 一条 UPDATE 里面用子查询重新计数，不会被并发的点赞、收藏覆盖
+func (g *GORMReconcileDAO) Recount(ctx context.Context, biz string, bizId int64) error {
+	db := g.db.WithContext(ctx)
+	likes := db.Model(&UserLikeBiz{}).Select("COUNT(*)").
+		Where("biz = ? AND biz_id = ? AND status = ?", biz, bizId, 1)
+	collects := db.Model(&UserCollectionBiz{}).Select("COUNT(*)").
+		Where("biz = ? AND biz_id = ?", biz, bizId)
+	return db.Model(&Interactive{}).
+		Where("biz = ? AND biz_id = ?", biz, bizId).
+		Updates(map[string]any{
+			"like_cnt":    gorm.Expr("(?)", likes),
+			"collect_cnt": gorm.Expr("(?)", collects),
+			"utime":       time.Now().UnixMilli(),
+		}).Error
+}
+
+func (g *GORMReconcileDAO) toMap(cnts []bizCnt) map[int64]int64 {
+	res := make(map[int64]int64, len(cnts))
+	for _, c := range cnts {
+		res[c.BizId] = c.Cnt
+	}
+	return res
+}
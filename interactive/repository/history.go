@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"geektime/webook/interactive/domain"
+	"geektime/webook/interactive/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+type HistoryRecordRepository interface {
+	AddRecord(ctx context.Context, record domain.HistoryRecord) error
+	List(ctx context.Context, uid int64, cur domain.HistoryCursor, limit int) ([]domain.HistoryRecord, error)
+	Delete(ctx context.Context, uid int64, biz string, bizId int64) error
+	Clear(ctx context.Context, uid int64) error
+}
+
+type historyRecordRepository struct {
+	dao dao.HistoryRecordDAO
+}
+
+func NewHistoryRecordRepository(dao dao.HistoryRecordDAO) HistoryRecordRepository {
+	return &historyRecordRepository{dao: dao}
+}
+
+func (h *historyRecordRepository) AddRecord(ctx context.Context, record domain.HistoryRecord) error {
+	return h.dao.Upsert(ctx, dao.HistoryRecord{
+		Uid:   record.Uid,
+		Biz:   record.Biz,
+		BizId: record.BizId,
+	})
+}
+
+func (h *historyRecordRepository) List(ctx context.Context, uid int64, cur domain.HistoryCursor, limit int) ([]domain.HistoryRecord, error) {
+	var utime int64
+	if !cur.ReadTime.IsZero() {
+		utime = cur.ReadTime.UnixMilli()
+	}
+	records, err := h.dao.List(ctx, uid, utime, cur.Id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(records, func(idx int, src dao.HistoryRecord) domain.HistoryRecord {
+		return domain.HistoryRecord{
+			Id:       src.Id,
+			Biz:      src.Biz,
+			BizId:    src.BizId,
+			Uid:      src.Uid,
+			ReadTime: time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
+
+func (h *historyRecordRepository) Delete(ctx context.Context, uid int64, biz string, bizId int64) error {
+	return h.dao.Delete(ctx, uid, biz, bizId)
+}
+
+func (h *historyRecordRepository) Clear(ctx context.Context, uid int64) error {
+	return h.dao.DeleteAll(ctx, uid)
+}
@@ -11,17 +11,28 @@ import (
 
 type InteractiveRepository interface {
 	IncrReadCnt(ctx context.Context, biz string, bizId int64) error
-	BatchIncrReadCnt(ctx context.Context, bizs []string, ids []int64) error
-	IncrLike(ctx context.Context, biz string, id int64, uid int64) error
-	DecrLike(ctx context.Context, biz string, id int64, uid int64) error
+	// BatchIncrReadCnt 合并之后的阅读数，ids[i] 加 cnts[i]
+	BatchIncrReadCnt(ctx context.Context, bizs []string, ids []int64, cnts []int64) error
+	// IncrLike 和 DecrLike 重复调用不会报错，也不会重复计数，返回点赞数有没有变化
+	IncrLike(ctx context.Context, biz string, id int64, uid int64) (bool, error)
+	DecrLike(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	AddCollectionItem(ctx context.Context, biz string, id int64, cid int64, uid int64) error
+	// DeleteCollectionItem 取消收藏，没有收藏过的时候什么也不做，返回收藏数有没有变化
+	DeleteCollectionItem(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	Get(ctx context.Context, biz string, id int64) (domain.Interactive, error)
 
 	Liked(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	Collected(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]domain.Interactive, error)
+	// GetByIdsWithUser 每个 id 都会返回，带上 uid 有没有点赞和收藏
+	GetByIdsWithUser(ctx context.Context, biz string, ids []int64, uid int64) ([]domain.Interactive, error)
+	// DeleteBiz 先删数据库再删缓存，避免缓存被旧数据回写
+	DeleteBiz(ctx context.Context, biz string, bizId int64) error
 }
 
+// userLikesCacheSize 用户点赞集合最多缓存多少个最近的点赞
+const userLikesCacheSize = 1000
+
 type CachedInteractiveRepository struct {
 	dao   dao.InteractiveDAO
 	cache cache.InteractiveCache
@@ -31,7 +42,7 @@ type CachedInteractiveRepository struct {
 func NewCachedInteractiveRepository(dao dao.InteractiveDAO,
 	l logger.LoggerV1,
 	cache cache.InteractiveCache) InteractiveRepository {
-	return &CachedInteractiveRepository{dao: dao, cache: cache}
+	return &CachedInteractiveRepository{dao: dao, cache: cache, l: l}
 }
 
 func (c *CachedInteractiveRepository) GetByIds(ctx context.Context, biz string, ids []int64) ([]domain.Interactive, error) {
@@ -44,6 +55,96 @@ func (c *CachedInteractiveRepository) GetByIds(ctx context.Context, biz string,
 	}), nil
 }
 
+// GetByIdsWithUser 计数、点赞、收藏各查一次，点赞优先走用户的点赞集合
+func (c *CachedInteractiveRepository) GetByIdsWithUser(ctx context.Context, biz string,
+	ids []int64, uid int64) ([]domain.Interactive, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	intrs, err := c.dao.GetByIds(ctx, biz, ids)
+	if err != nil {
+		return nil, err
+	}
+	liked, err := c.likedIds(ctx, biz, uid, ids)
+	if err != nil {
+		return nil, err
+	}
+	collected, err := c.dao.GetCollectedBizIds(ctx, biz, uid, ids)
+	if err != nil {
+		return nil, err
+	}
+	collectedSet := make(map[int64]bool, len(collected))
+	for _, id := range collected {
+		collectedSet[id] = true
+	}
+	m := make(map[int64]domain.Interactive, len(intrs))
+	for _, intr := range intrs {
+		m[intr.BizId] = c.toDomain(intr)
+	}
+	res := make([]domain.Interactive, 0, len(ids))
+	for _, id := range ids {
+		intr, ok := m[id]
+		if !ok {
+			intr = domain.Interactive{Biz: biz, BizId: id}
+		}
+		intr.Liked = liked[id]
+		intr.Collected = collectedSet[id]
+		res = append(res, intr)
+	}
+	return res, nil
+}
+
+// likedIds 集合不完整的时候，不在集合里面的 id 回查数据库
+func (c *CachedInteractiveRepository) likedIds(ctx context.Context, biz string,
+	uid int64, ids []int64) (map[int64]bool, error) {
+	res := make(map[int64]bool, len(ids))
+	flags, complete, err := c.cache.GetUserLikes(ctx, biz, uid, ids)
+	if err != nil {
+		recent, er := c.dao.ListRecentLikedBizIds(ctx, biz, uid, userLikesCacheSize+1)
+		if er != nil {
+			return nil, er
+		}
+		complete = len(recent) <= userLikesCacheSize
+		if !complete {
+			recent = recent[:userLikesCacheSize]
+		}
+		er = c.cache.SetUserLikes(ctx, biz, uid, recent, complete)
+		if er != nil {
+			c.l.Error("回写点赞集合失败",
+				logger.String("biz", biz),
+				logger.Int64("uid", uid),
+				logger.Error(er))
+		}
+		recentSet := make(map[int64]bool, len(recent))
+		for _, id := range recent {
+			recentSet[id] = true
+		}
+		flags = make([]bool, len(ids))
+		for i, id := range ids {
+			flags[i] = recentSet[id]
+		}
+	}
+	unknown := make([]int64, 0, len(ids))
+	for i, id := range ids {
+		if flags[i] {
+			res[id] = true
+		} else if !complete {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) == 0 {
+		return res, nil
+	}
+	more, err := c.dao.GetLikedBizIds(ctx, biz, uid, unknown)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range more {
+		res[id] = true
+	}
+	return res, nil
+}
+
 func (c *CachedInteractiveRepository) Get(ctx context.Context, biz string, id int64) (domain.Interactive, error) {
 	//先查询缓存
 	intr, err := c.cache.Get(ctx, biz, id)
@@ -70,6 +171,14 @@ func (c *CachedInteractiveRepository) Get(ctx context.Context, biz string, id in
 	return intr, err
 }
 
+func (c *CachedInteractiveRepository) DeleteBiz(ctx context.Context, biz string, bizId int64) error {
+	err := c.dao.DeleteBiz(ctx, biz, bizId)
+	if err != nil {
+		return err
+	}
+	return c.cache.Del(ctx, biz, bizId)
+}
+
 // Liked 查看用户是否点赞biz:id
 func (c *CachedInteractiveRepository) Liked(ctx context.Context,
 	biz string, id int64, uid int64) (bool, error) {
@@ -113,22 +222,65 @@ func (c *CachedInteractiveRepository) AddCollectionItem(ctx context.Context,
 	return c.cache.IncrCollectCntIfPresent(ctx, biz, id)
 }
 
-func (c *CachedInteractiveRepository) IncrLike(ctx context.Context, biz string, id int64, uid int64) error {
+func (c *CachedInteractiveRepository) DeleteCollectionItem(ctx context.Context,
+	biz string, id int64, uid int64) (bool, error) {
+	err := c.dao.DeleteCollectionBiz(ctx, biz, id, uid)
+	if err == dao.ErrDataNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, c.cache.DecrCollectCntIfPresent(ctx, biz, id)
+}
+
+func (c *CachedInteractiveRepository) IncrLike(ctx context.Context, biz string, id int64, uid int64) (bool, error) {
 	//记录点赞记录，并在数据库中贴子点赞数量加1
 	err := c.dao.InsertLikeInfo(ctx, biz, id, uid)
+	// 重复点赞，计数没有变化
+	if err == dao.ErrLikeExists {
+		return false, nil
+	}
 	if err != nil {
-		return err
+		return false, err
 	}
-	//点赞记录不用缓存
-	return c.cache.IncrLikeCntIfPresent(ctx, biz, id)
+	// 数据库已经提交了，缓存失败不能再报错，不然调用者会以为没有点赞成功
+	err = c.cache.AddUserLikeIfPresent(ctx, biz, uid, id)
+	if err != nil {
+		c.dropUserLikes(ctx, biz, uid, err)
+	}
+	return true, c.cache.IncrLikeCntIfPresent(ctx, biz, id)
 }
 
-func (c *CachedInteractiveRepository) DecrLike(ctx context.Context, biz string, id int64, uid int64) error {
+func (c *CachedInteractiveRepository) DecrLike(ctx context.Context, biz string, id int64, uid int64) (bool, error) {
 	err := c.dao.DeleteLikeInfo(ctx, biz, id, uid)
+	if err == dao.ErrDataNotFound {
+		return false, nil
+	}
 	if err != nil {
-		return err
+		return false, err
+	}
+	err = c.cache.RemoveUserLikeIfPresent(ctx, biz, uid, id)
+	if err != nil {
+		c.dropUserLikes(ctx, biz, uid, err)
+	}
+	return true, c.cache.DecrLikeCntIfPresent(ctx, biz, id)
+}
+
+// dropUserLikes 点赞集合没有跟上数据库，删掉整个集合，下次查询回源重建
+// 删除也失败的话只能等集合过期
+func (c *CachedInteractiveRepository) dropUserLikes(ctx context.Context, biz string, uid int64, cause error) {
+	c.l.Warn("更新用户点赞集合失败，删除缓存",
+		logger.String("biz", biz),
+		logger.Int64("uid", uid),
+		logger.Error(cause))
+	err := c.cache.DelUserLikes(ctx, biz, uid)
+	if err != nil {
+		c.l.Error("删除用户点赞集合失败",
+			logger.String("biz", biz),
+			logger.Int64("uid", uid),
+			logger.Error(err))
 	}
-	return c.cache.DecrLikeCntIfPresent(ctx, biz, id)
 }
 
 func (c *CachedInteractiveRepository) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
@@ -142,19 +294,16 @@ func (c *CachedInteractiveRepository) IncrReadCnt(ctx context.Context, biz strin
 	return c.cache.IncrReadCntIfPresent(ctx, biz, bizId)
 }
 
-func (c *CachedInteractiveRepository) BatchIncrReadCnt(ctx context.Context, biz []string, bizId []int64) error {
-	err := c.dao.BatchIncrReadCnt(ctx, biz, bizId)
+func (c *CachedInteractiveRepository) BatchIncrReadCnt(ctx context.Context, biz []string, bizId []int64, cnts []int64) error {
+	err := c.dao.BatchIncrReadCnt(ctx, biz, bizId, cnts)
 	if err != nil {
 		return err
 	}
-	go func() {
-		for i := 0; i < len(biz); i++ {
-			er := c.cache.IncrReadCntIfPresent(ctx, biz[i], bizId[i])
-			if er != nil {
-				// 记录日志
-			}
-		}
-	}()
+	// 数据库已经成功了，缓存失败了等过期
+	err = c.cache.BatchIncrReadCntIfPresent(ctx, biz, bizId, cnts)
+	if err != nil {
+		c.l.Error("批量更新缓存阅读数失败", logger.Error(err))
+	}
 	return nil
 }
 
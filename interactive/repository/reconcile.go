@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"geektime/webook/interactive/domain"
+	"geektime/webook/interactive/repository/cache"
+	"geektime/webook/interactive/repository/dao"
+	"geektime/webook/pkg/logger"
+)
+
+type ReconcileRepository interface {
+	// Reconcile 核对 id 在 startId 之后的 limit 条计数，数据库有偏差就重新计数，缓存有偏差就删掉
+	// 返回这一批最后一条的 id，已经扫完的时候返回 0
+	Reconcile(ctx context.Context, startId int64, limit int) (int64, []domain.CntDrift, error)
+}
+
+type reconcileRepository struct {
+	dao   dao.ReconcileDAO
+	cache cache.InteractiveCache
+	l     logger.LoggerV1
+}
+
+func NewReconcileRepository(dao dao.ReconcileDAO,
+	cache cache.InteractiveCache, l logger.LoggerV1) ReconcileRepository {
+	return &reconcileRepository{dao: dao, cache: cache, l: l}
+}
+
+func (r *reconcileRepository) Reconcile(ctx context.Context,
+	startId int64, limit int) (int64, []domain.CntDrift, error) {
+	intrs, err := r.dao.ListInteractives(ctx, startId, limit)
+	if err != nil || len(intrs) == 0 {
+		return 0, nil, err
+	}
+	bizIds := make(map[string][]int64)
+	for _, intr := range intrs {
+		bizIds[intr.Biz] = append(bizIds[intr.Biz], intr.BizId)
+	}
+	likes := make(map[string]map[int64]int64, len(bizIds))
+	collects := make(map[string]map[int64]int64, len(bizIds))
+	for biz, ids := range bizIds {
+		likes[biz], err = r.dao.CountLikes(ctx, biz, ids)
+		if err != nil {
+			return 0, nil, err
+		}
+		collects[biz], err = r.dao.CountCollects(ctx, biz, ids)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	var drifts []domain.CntDrift
+	for _, intr := range intrs {
+		want := domain.Interactive{
+			Biz:        intr.Biz,
+			BizId:      intr.BizId,
+			LikeCnt:    likes[intr.Biz][intr.BizId],
+			CollectCnt: collects[intr.Biz][intr.BizId],
+		}
+		dbDrifts := r.diff(domain.ReconcileStoreDB, intr.LikeCnt, intr.CollectCnt, want)
+		if len(dbDrifts) > 0 {
+			// 统计和修正之间可能有并发的点赞，Recount 自己会重新数，结果不受影响
+			err = r.dao.Recount(ctx, intr.Biz, intr.BizId)
+			if err != nil {
+				return 0, nil, err
+			}
+			drifts = append(drifts, dbDrifts...)
+			r.delCache(ctx, intr.Biz, intr.BizId)
+			continue
+		}
+		cached, err := r.cache.Get(ctx, intr.Biz, intr.BizId)
+		if err != nil {
+			if err != cache.ErrKeyNotExist {
+				r.l.Error("对账查询缓存失败",
+					logger.String("biz", intr.Biz),
+					logger.Int64("bizId", intr.BizId),
+					logger.Error(err))
+			}
+			continue
+		}
+		cacheDrifts := r.diff(domain.ReconcileStoreCache, cached.LikeCnt, cached.CollectCnt, want)
+		if len(cacheDrifts) > 0 {
+			drifts = append(drifts, cacheDrifts...)
+			r.delCache(ctx, intr.Biz, intr.BizId)
+		}
+	}
+	return intrs[len(intrs)-1].Id, drifts, nil
+}
+
+func (r *reconcileRepository) diff(store string, likeCnt, collectCnt int64,
+	want domain.Interactive) []domain.CntDrift {
+	var res []domain.CntDrift
+	if likeCnt != want.LikeCnt {
+		res = append(res, domain.CntDrift{
+			Biz: want.Biz, BizId: want.BizId, Field: "like_cnt", Store: store,
+			Got: likeCnt, Want: want.LikeCnt,
+		})
+	}
+	if collectCnt != want.CollectCnt {
+		res = append(res, domain.CntDrift{
+			Biz: want.Biz, BizId: want.BizId, Field: "collect_cnt", Store: store,
+			Got: collectCnt, Want: want.CollectCnt,
+		})
+	}
+	return res
+}
+
+// delCache 删不掉也只是等缓存过期
+func (r *reconcileRepository) delCache(ctx context.Context, biz string, bizId int64) {
+	err := r.cache.Del(ctx, biz, bizId)
+	if err != nil {
+		r.l.Error("对账删除缓存失败",
+			logger.String("biz", biz),
+			logger.Int64("bizId", bizId),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrUnknownBiz  = errors.New("不支持的资源类型")
+	ErrBizNotFound = errors.New("资源不存在")
+)
+
+// BizValidator 每种资源自己的校验，资源不存在的时候返回 ErrBizNotFound
+type BizValidator interface {
+	// Owner 返回资源的作者，不关心作者的资源返回 0
+	Owner(ctx context.Context, bizId int64) (int64, error)
+}
+
+type BizValidatorFunc func(ctx context.Context, bizId int64) (int64, error)
+
+func (f BizValidatorFunc) Owner(ctx context.Context, bizId int64) (int64, error) {
+	return f(ctx, bizId)
+}
+
+// IdValidator 只检查 id 是不是合法，数据不在交互服务里面的资源默认用这个
+var IdValidator BizValidator = BizValidatorFunc(func(ctx context.Context, bizId int64) (int64, error) {
+	if bizId <= 0 {
+		return 0, ErrBizNotFound
+	}
+	return 0, nil
+})
+
+// BizRegistry 没有注册过的 biz 一律拒绝，免得随便什么字符串都能写进计数表
+type BizRegistry struct {
+	mu         sync.RWMutex
+	validators map[string]BizValidator
+}
+
+func NewBizRegistry() *BizRegistry {
+	return &BizRegistry{validators: make(map[string]BizValidator)}
+}
+
+// Register 重复注册会覆盖之前的校验
+func (r *BizRegistry) Register(biz string, v BizValidator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validators[biz] = v
+}
+
+func (r *BizRegistry) Supported(biz string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.validators[biz]
+	return ok
+}
+
+// Validate 写操作之前调用，确认资源类型注册过并且资源存在
+func (r *BizRegistry) Validate(ctx context.Context, biz string, bizId int64) error {
+	_, err := r.Owner(ctx, biz, bizId)
+	return err
+}
+
+func (r *BizRegistry) Owner(ctx context.Context, biz string, bizId int64) (int64, error) {
+	r.mu.RLock()
+	v, ok := r.validators[biz]
+	r.mu.RUnlock()
+	if !ok {
+		return 0, ErrUnknownBiz
+	}
+	return v.Owner(ctx, bizId)
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"geektime/webook/interactive/domain"
+)
+
+// ChangeProducer 把计数的变化通知给下游，比如热榜的增量计算
+// 计数已经写成功了，发送失败只记日志，下游靠定时的全量计算校正
+type ChangeProducer interface {
+	ProduceChanges(ctx context.Context, changes []domain.CntChange) error
+}
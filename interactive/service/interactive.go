@@ -2,9 +2,18 @@ package service
 
 import (
 	"context"
+	"errors"
 	"geektime/webook/interactive/domain"
 	"geektime/webook/interactive/repository"
+	"geektime/webook/pkg/logger"
 	"golang.org/x/sync/errgroup"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrCollectionNotFound = repository.ErrCollectionNotFound
+	ErrIllegalCollection  = errors.New("收藏夹名字不合法")
 )
 
 type InteractiveService interface {
@@ -14,19 +23,71 @@ type InteractiveService interface {
 	Collect(ctx context.Context, biz string, bizId, cid, uid int64) error
 	Get(ctx context.Context, biz string, bizId int64, uid int64) (domain.Interactive, error)
 	GetByIds(ctx context.Context, biz string, bizIds []int64) (map[int64]domain.Interactive, error)
+	// GetByIdsWithUser 列表页用，每个 bizId 都有结果，带上 uid 有没有点赞和收藏
+	GetByIdsWithUser(ctx context.Context, biz string, bizIds []int64, uid int64) (map[int64]domain.Interactive, error)
+
+	// ListHistory 阅读历史，按照最后一次阅读的时间倒序
+	ListHistory(ctx context.Context, uid int64, cur domain.HistoryCursor, limit int) ([]domain.HistoryRecord, error)
+	DeleteHistory(ctx context.Context, uid int64, biz string, bizId int64) error
+	ClearHistory(ctx context.Context, uid int64) error
+
+	// CancelCollect 取消收藏，没有收藏过也不会报错
+	CancelCollect(ctx context.Context, biz string, bizId int64, uid int64) error
+	CreateCollection(ctx context.Context, c domain.Collection) (int64, error)
+	RenameCollection(ctx context.Context, id int64, uid int64, name string) error
+	DeleteCollection(ctx context.Context, id int64, uid int64) error
+	ListCollections(ctx context.Context, uid int64) ([]domain.Collection, error)
+	// ListCollection 收藏夹里面的收藏，cid 为 0 是默认收藏夹
+	ListCollection(ctx context.Context, uid int64, cid int64, offset int, limit int) ([]domain.CollectionItem, error)
 }
 
 type interactiveService struct {
-	repo repository.InteractiveRepository
+	repo           repository.InteractiveRepository
+	historyRepo    repository.HistoryRecordRepository
+	collectionRepo repository.CollectionRepository
+	bizs           *BizRegistry
+	producer       ChangeProducer
+	l              logger.LoggerV1
 }
 
-func NewInteractiveService(repo repository.InteractiveRepository) InteractiveService {
-	return &interactiveService{repo: repo}
+func NewInteractiveService(repo repository.InteractiveRepository,
+	historyRepo repository.HistoryRecordRepository,
+	collectionRepo repository.CollectionRepository,
+	bizs *BizRegistry,
+	producer ChangeProducer,
+	l logger.LoggerV1) InteractiveService {
+	return &interactiveService{
+		repo:           repo,
+		historyRepo:    historyRepo,
+		collectionRepo: collectionRepo,
+		bizs:           bizs,
+		producer:       producer,
+		l:              l,
+	}
+}
+
+func (i *interactiveService) GetByIdsWithUser(ctx context.Context,
+	biz string, ids []int64, uid int64) (map[int64]domain.Interactive, error) {
+	if !i.bizs.Supported(biz) {
+		return nil, ErrUnknownBiz
+	}
+	intrs, err := i.repo.GetByIdsWithUser(ctx, biz, ids, uid)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]domain.Interactive, len(intrs))
+	for _, intr := range intrs {
+		res[intr.BizId] = intr
+	}
+	return res, nil
 }
 
 // GetByIds 根据bizId集合获取文章统计数据
 func (i *interactiveService) GetByIds(ctx context.Context,
 	biz string, ids []int64) (map[int64]domain.Interactive, error) {
+	if !i.bizs.Supported(biz) {
+		return nil, ErrUnknownBiz
+	}
 	intrs, err := i.repo.GetByIds(ctx, biz, ids)
 	if err != nil {
 		return nil, err
@@ -40,6 +101,9 @@ func (i *interactiveService) GetByIds(ctx context.Context,
 
 // Get 根据 bizId/aid 获取文章统计数据
 func (i *interactiveService) Get(ctx context.Context, biz string, id int64, uid int64) (domain.Interactive, error) {
+	if !i.bizs.Supported(biz) {
+		return domain.Interactive{}, ErrUnknownBiz
+	}
 	intr, err := i.repo.Get(ctx, biz, id)
 	if err != nil {
 		return domain.Interactive{}, err
@@ -61,18 +125,142 @@ func (i *interactiveService) Get(ctx context.Context, biz string, id int64, uid
 	return intr, eg.Wait()
 }
 
+// Collect cid 为 0 的时候放进默认收藏夹，否则只能放进自己的收藏夹
 func (i *interactiveService) Collect(ctx context.Context, biz string, bizId, cid, uid int64) error {
-	return i.repo.AddCollectionItem(ctx, biz, bizId, cid, uid)
+	if err := i.bizs.Validate(ctx, biz, bizId); err != nil {
+		return err
+	}
+	if cid > 0 {
+		if err := i.ownCollection(ctx, cid, uid); err != nil {
+			return err
+		}
+	}
+	err := i.repo.AddCollectionItem(ctx, biz, bizId, cid, uid)
+	if err != nil {
+		return err
+	}
+	i.notify(ctx, biz, bizId, domain.CntFieldCollect, 1)
+	return nil
+}
+
+func (i *interactiveService) CancelCollect(ctx context.Context, biz string, bizId int64, uid int64) error {
+	if !i.bizs.Supported(biz) {
+		return ErrUnknownBiz
+	}
+	changed, err := i.repo.DeleteCollectionItem(ctx, biz, bizId, uid)
+	if changed {
+		i.notify(ctx, biz, bizId, domain.CntFieldCollect, -1)
+	}
+	return err
+}
+
+func (i *interactiveService) CreateCollection(ctx context.Context, c domain.Collection) (int64, error) {
+	c.Name = strings.TrimSpace(c.Name)
+	if !i.legalCollectionName(c.Name) {
+		return 0, ErrIllegalCollection
+	}
+	return i.collectionRepo.Create(ctx, c)
+}
+
+func (i *interactiveService) RenameCollection(ctx context.Context, id int64, uid int64, name string) error {
+	name = strings.TrimSpace(name)
+	if !i.legalCollectionName(name) {
+		return ErrIllegalCollection
+	}
+	return i.collectionRepo.Rename(ctx, id, uid, name)
+}
+
+func (i *interactiveService) DeleteCollection(ctx context.Context, id int64, uid int64) error {
+	return i.collectionRepo.Delete(ctx, id, uid)
+}
+
+func (i *interactiveService) ListCollections(ctx context.Context, uid int64) ([]domain.Collection, error) {
+	return i.collectionRepo.ListByUid(ctx, uid)
+}
+
+func (i *interactiveService) ListCollection(ctx context.Context, uid int64, cid int64,
+	offset int, limit int) ([]domain.CollectionItem, error) {
+	if cid > 0 {
+		if err := i.ownCollection(ctx, cid, uid); err != nil {
+			return nil, err
+		}
+	}
+	return i.collectionRepo.ListItems(ctx, uid, cid, offset, limit)
+}
+
+// ownCollection 别人的收藏夹也当作不存在
+func (i *interactiveService) ownCollection(ctx context.Context, cid int64, uid int64) error {
+	c, err := i.collectionRepo.GetById(ctx, cid)
+	if err != nil {
+		return err
+	}
+	if c.Uid != uid {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+func (i *interactiveService) legalCollectionName(name string) bool {
+	cnt := utf8.RuneCountInString(name)
+	return cnt > 0 && cnt <= domain.MaxCollectionNameLen
 }
 
 func (i *interactiveService) Like(c context.Context, biz string, id int64, uid int64) error {
-	return i.repo.IncrLike(c, biz, id, uid)
+	if err := i.bizs.Validate(c, biz, id); err != nil {
+		return err
+	}
+	changed, err := i.repo.IncrLike(c, biz, id, uid)
+	if changed {
+		i.notify(c, biz, id, domain.CntFieldLike, 1)
+	}
+	return err
 }
 
+// CancelLike 取消点赞不用确认资源还在，资源删了也要能取消
 func (i *interactiveService) CancelLike(c context.Context, biz string, id int64, uid int64) error {
-	return i.repo.DecrLike(c, biz, id, uid)
+	if !i.bizs.Supported(biz) {
+		return ErrUnknownBiz
+	}
+	changed, err := i.repo.DecrLike(c, biz, id, uid)
+	if changed {
+		i.notify(c, biz, id, domain.CntFieldLike, -1)
+	}
+	return err
+}
+
+// notify 计数已经变了，通知失败不影响这次操作的结果
+func (i *interactiveService) notify(ctx context.Context, biz string, bizId int64, field string, delta int64) {
+	err := i.producer.ProduceChanges(ctx, []domain.CntChange{{
+		Biz:   biz,
+		BizId: bizId,
+		Field: field,
+		Delta: delta,
+	}})
+	if err != nil {
+		i.l.Error("发送计数变化失败",
+			logger.String("biz", biz),
+			logger.Int64("bizId", bizId),
+			logger.String("field", field),
+			logger.Error(err))
+	}
 }
 
 func (i *interactiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
+	if err := i.bizs.Validate(ctx, biz, bizId); err != nil {
+		return err
+	}
 	return i.repo.IncrReadCnt(ctx, biz, bizId)
 }
+
+func (i *interactiveService) ListHistory(ctx context.Context, uid int64,
+	cur domain.HistoryCursor, limit int) ([]domain.HistoryRecord, error) {
+	return i.historyRepo.List(ctx, uid, cur, limit)
+}
+
+func (i *interactiveService) DeleteHistory(ctx context.Context, uid int64, biz string, bizId int64) error {
+	return i.historyRepo.Delete(ctx, uid, biz, bizId)
+}
+
+func (i *interactiveService) ClearHistory(ctx context.Context, uid int64) error {
+	return i.historyRepo.Clear(ctx, uid)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/interactive/repository"
+	"geektime/webook/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ReconcileService 点赞数、收藏数是增量维护的，定期用明细表核对一遍
+type ReconcileService interface {
+	// Reconcile 扫一遍全表，返回修正了多少个计数
+	Reconcile(ctx context.Context) (int, error)
+}
+
+type reconcileService struct {
+	repo      repository.ReconcileRepository
+	l         logger.LoggerV1
+	batchSize int
+	drift     *prometheus.CounterVec
+}
+
+func NewReconcileService(repo repository.ReconcileRepository, l logger.LoggerV1) ReconcileService {
+	drift := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "lll",
+		Subsystem: "interactive",
+		Name:      "reconcile_drift_total",
+		Help:      "对账发现的计数偏差",
+	}, []string{"biz", "field", "store"})
+	if err := prometheus.Register(drift); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			panic(err)
+		}
+		drift = are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return &reconcileService{
+		repo:      repo,
+		l:         l,
+		batchSize: 100,
+		drift:     drift,
+	}
+}
+
+func (r *reconcileService) Reconcile(ctx context.Context) (int, error) {
+	var startId int64
+	cnt := 0
+	for {
+		nextId, drifts, err := r.repo.Reconcile(ctx, startId, r.batchSize)
+		if err != nil {
+			return cnt, err
+		}
+		for _, d := range drifts {
+			r.drift.WithLabelValues(d.Biz, d.Field, d.Store).Inc()
+			r.l.Warn("计数有偏差",
+				logger.String("biz", d.Biz),
+				logger.Int64("bizId", d.BizId),
+				logger.String("field", d.Field),
+				logger.String("store", d.Store),
+				logger.Int64("got", d.Got),
+				logger.Int64("want", d.Want))
+		}
+		cnt += len(drifts)
+		if nextId == 0 {
+			return cnt, nil
+		}
+		startId = nextId
+	}
+}
@@ -24,11 +24,17 @@ var thirdPartySet = wire.NewSet( // 第三方依赖
 	ioc.InitKafkaClient,
 	ioc.InitSyncProducer,
 	ioc.InitLoggerV1,
+	ioc.InitBizRegistry,
 )
 
 var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO,
 	cache.NewInteractiveRedisCache,
 	repository.NewCachedInteractiveRepository,
+	dao.NewGORMHistoryRecordDAO,
+	repository.NewHistoryRecordRepository,
+	dao.NewGORMCollectionDAO,
+	repository.NewCollectionRepository,
+	events.NewSaramaChangeProducer,
 	service.NewInteractiveService,
 )
 
@@ -40,6 +46,15 @@ var migratorProvider = wire.NewSet(
 	ioc.InitFixerConsumer,
 )
 
+// 定时对账
+var reconcileProvider = wire.NewSet(
+	dao.NewGORMReconcileDAO,
+	repository.NewReconcileRepository,
+	service.NewReconcileService,
+	ioc.InitReconcileJob,
+	ioc.InitJobs,
+)
+
 func InitApp() *App {
 	wire.Build(
 		thirdPartySet, interactiveSvcSet,
@@ -48,7 +63,10 @@ func InitApp() *App {
 		grpc.NewInteractiveServiceServer,
 		ioc.InitGRPCxServer,
 		events.NewInteractiveReadEventConsumer,
+		events.NewInteractiveDeletedEventConsumer,
+		events.NewHistoryRecordConsumer,
 		migratorProvider,
+		reconcileProvider,
 		ioc.InitConsumers,
 		//组装App结构体的所有字段
 		wire.Struct(new(App), "*"),
@@ -29,31 +29,48 @@ func InitApp() *App {
 	cmdable := ioc.InitRedis()
 	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
-	interactiveService := service.NewInteractiveService(interactiveRepository)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewHistoryRecordRepository(historyRecordDAO)
+	collectionDAO := dao.NewGORMCollectionDAO(db)
+	collectionRepository := repository.NewCollectionRepository(collectionDAO, interactiveCache, loggerV1)
+	bizRegistry := ioc.InitBizRegistry()
+	saramaClient := ioc.InitKafkaClient()
+	syncProducer := ioc.InitSyncProducer(saramaClient)
+	changeProducer := events.NewSaramaChangeProducer(syncProducer)
+	interactiveService := service.NewInteractiveService(interactiveRepository, historyRecordRepository, collectionRepository, bizRegistry, changeProducer, loggerV1)
 	interactiveServiceServer := grpc.NewInteractiveServiceServer(interactiveService)
 	client := ioc.InitEtcdClient()
 	server := ioc.InitGRPCxServer(interactiveServiceServer, client, loggerV1)
-	saramaClient := ioc.InitKafkaClient()
-	interactiveReadEventConsumer := events.NewInteractiveReadEventConsumer(interactiveRepository, saramaClient, loggerV1)
+	interactiveReadEventConsumer := events.NewInteractiveReadEventConsumer(interactiveRepository, bizRegistry, changeProducer, saramaClient, loggerV1)
+	interactiveDeletedEventConsumer := events.NewInteractiveDeletedEventConsumer(interactiveRepository, saramaClient, loggerV1)
+	historyRecordConsumer := events.NewHistoryRecordConsumer(historyRecordRepository, bizRegistry, saramaClient, loggerV1)
 	consumer := ioc.InitFixerConsumer(saramaClient, loggerV1, srcDB, dstDB)
-	v := ioc.InitConsumers(interactiveReadEventConsumer, consumer)
-	syncProducer := ioc.InitSyncProducer(saramaClient)
+	v := ioc.InitConsumers(interactiveReadEventConsumer, interactiveDeletedEventConsumer, historyRecordConsumer, consumer)
 	producer := ioc.InitInteractiveProducer(syncProducer)
 	ginxServer := ioc.InitMigratorWebServer(loggerV1, srcDB, dstDB, doubleWritePool, producer)
+	reconcileDAO := dao.NewGORMReconcileDAO(db)
+	reconcileRepository := repository.NewReconcileRepository(reconcileDAO, interactiveCache, loggerV1)
+	reconcileService := service.NewReconcileService(reconcileRepository, loggerV1)
+	reconcileJob := ioc.InitReconcileJob(reconcileService, loggerV1)
+	cron := ioc.InitJobs(reconcileJob)
 	app := &App{
 		server:    server,
 		consumers: v,
 		webAdmin:  ginxServer,
+		cron:      cron,
 	}
 	return app
 }
 
 // wire.go:
 
-var thirdPartySet = wire.NewSet(ioc.InitRedis, ioc.InitDstDB, ioc.InitSrcDB, ioc.InitDoubleWritePool, ioc.InitBizDB, ioc.InitKafkaClient, ioc.InitSyncProducer, ioc.InitLoggerV1)
+var thirdPartySet = wire.NewSet(ioc.InitRedis, ioc.InitDstDB, ioc.InitSrcDB, ioc.InitDoubleWritePool, ioc.InitBizDB, ioc.InitKafkaClient, ioc.InitSyncProducer, ioc.InitLoggerV1, ioc.InitBizRegistry)
 
-var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO, cache.NewInteractiveRedisCache, repository.NewCachedInteractiveRepository, service.NewInteractiveService)
+var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO, cache.NewInteractiveRedisCache, repository.NewCachedInteractiveRepository, dao.NewGORMHistoryRecordDAO, repository.NewHistoryRecordRepository, dao.NewGORMCollectionDAO, repository.NewCollectionRepository, events.NewSaramaChangeProducer, service.NewInteractiveService)
 
 // 不停机数据迁移后台管理服务端
 // 源目数据库进行校验，pool进行双写
 var migratorProvider = wire.NewSet(ioc.InitInteractiveProducer, ioc.InitMigratorWebServer, ioc.InitFixerConsumer)
+
+// 定时对账
+var reconcileProvider = wire.NewSet(dao.NewGORMReconcileDAO, repository.NewReconcileRepository, service.NewReconcileService, ioc.InitReconcileJob, ioc.InitJobs)
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	commentv1 "geektime/webook/api/proto/gen/comment/v1"
+	"google.golang.org/grpc"
+)
+
+// CommentServiceAdapter
+// 将本地的 CommentServiceServer 适配给 CommentServiceClient，测试的时候不需要启动评论服务
+type CommentServiceAdapter struct {
+	svr commentv1.CommentServiceServer
+}
+
+func NewCommentServiceAdapter(svr commentv1.CommentServiceServer) *CommentServiceAdapter {
+	return &CommentServiceAdapter{svr: svr}
+}
+
+func (c *CommentServiceAdapter) GetCommentList(ctx context.Context, in *commentv1.CommentListRequest, opts ...grpc.CallOption) (*commentv1.CommentListResponse, error) {
+	return c.svr.GetCommentList(ctx, in)
+}
+
+func (c *CommentServiceAdapter) DeleteComment(ctx context.Context, in *commentv1.DeleteCommentRequest, opts ...grpc.CallOption) (*commentv1.DeleteCommentResponse, error) {
+	return c.svr.DeleteComment(ctx, in)
+}
+
+func (c *CommentServiceAdapter) CreateComment(ctx context.Context, in *commentv1.CreateCommentRequest, opts ...grpc.CallOption) (*commentv1.CreateCommentResponse, error) {
+	return c.svr.CreateComment(ctx, in)
+}
+
+func (c *CommentServiceAdapter) GetMoreReplies(ctx context.Context, in *commentv1.GetMoreRepliesRequest, opts ...grpc.CallOption) (*commentv1.GetMoreRepliesResponse, error) {
+	return c.svr.GetMoreReplies(ctx, in)
+}
+
+func (c *CommentServiceAdapter) CountByBiz(ctx context.Context, in *commentv1.CountByBizRequest, opts ...grpc.CallOption) (*commentv1.CountByBizResponse, error) {
+	return c.svr.CountByBiz(ctx, in)
+}
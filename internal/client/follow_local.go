@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	followv1 "geektime/webook/api/proto/gen/follow/v1"
+	"google.golang.org/grpc"
+)
+
+// FollowServiceAdapter
+// 将本地的 FollowServiceServer 适配给 FollowServiceClient，测试的时候不需要启动关注服务
+type FollowServiceAdapter struct {
+	svr followv1.FollowServiceServer
+}
+
+func NewFollowServiceAdapter(svr followv1.FollowServiceServer) *FollowServiceAdapter {
+	return &FollowServiceAdapter{svr: svr}
+}
+
+func (f *FollowServiceAdapter) Follow(ctx context.Context, in *followv1.FollowRequest, opts ...grpc.CallOption) (*followv1.FollowResponse, error) {
+	return f.svr.Follow(ctx, in)
+}
+
+func (f *FollowServiceAdapter) CancelFollow(ctx context.Context, in *followv1.CancelFollowRequest, opts ...grpc.CallOption) (*followv1.CancelFollowResponse, error) {
+	return f.svr.CancelFollow(ctx, in)
+}
+
+func (f *FollowServiceAdapter) GetFollowee(ctx context.Context, in *followv1.GetFolloweeRequest, opts ...grpc.CallOption) (*followv1.GetFolloweeResponse, error) {
+	return f.svr.GetFollowee(ctx, in)
+}
+
+func (f *FollowServiceAdapter) FollowInfo(ctx context.Context, in *followv1.FollowInfoRequest, opts ...grpc.CallOption) (*followv1.FollowInfoResponse, error) {
+	return f.svr.FollowInfo(ctx, in)
+}
+
+func (f *FollowServiceAdapter) GetFollowStatic(ctx context.Context, in *followv1.GetFollowStaticRequest, opts ...grpc.CallOption) (*followv1.GetFollowStaticResponse, error) {
+	return f.svr.GetFollowStatic(ctx, in)
+}
@@ -50,6 +50,46 @@ func (i *GrayScaleInteractiveClient) GetByIds(ctx context.Context, in *intrv1.Ge
 	return i.selectClient().GetByIds(ctx, in, opts...)
 }
 
+func (i *GrayScaleInteractiveClient) GetByIdsWithUser(ctx context.Context, in *intrv1.GetByIdsWithUserRequest, opts ...grpc.CallOption) (*intrv1.GetByIdsWithUserResponse, error) {
+	return i.selectClient().GetByIdsWithUser(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) ListHistory(ctx context.Context, in *intrv1.ListHistoryRequest, opts ...grpc.CallOption) (*intrv1.ListHistoryResponse, error) {
+	return i.selectClient().ListHistory(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) DeleteHistory(ctx context.Context, in *intrv1.DeleteHistoryRequest, opts ...grpc.CallOption) (*intrv1.DeleteHistoryResponse, error) {
+	return i.selectClient().DeleteHistory(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) ClearHistory(ctx context.Context, in *intrv1.ClearHistoryRequest, opts ...grpc.CallOption) (*intrv1.ClearHistoryResponse, error) {
+	return i.selectClient().ClearHistory(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) CancelCollect(ctx context.Context, in *intrv1.CancelCollectRequest, opts ...grpc.CallOption) (*intrv1.CancelCollectResponse, error) {
+	return i.selectClient().CancelCollect(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) CreateCollection(ctx context.Context, in *intrv1.CreateCollectionRequest, opts ...grpc.CallOption) (*intrv1.CreateCollectionResponse, error) {
+	return i.selectClient().CreateCollection(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) RenameCollection(ctx context.Context, in *intrv1.RenameCollectionRequest, opts ...grpc.CallOption) (*intrv1.RenameCollectionResponse, error) {
+	return i.selectClient().RenameCollection(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) DeleteCollection(ctx context.Context, in *intrv1.DeleteCollectionRequest, opts ...grpc.CallOption) (*intrv1.DeleteCollectionResponse, error) {
+	return i.selectClient().DeleteCollection(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) ListCollections(ctx context.Context, in *intrv1.ListCollectionsRequest, opts ...grpc.CallOption) (*intrv1.ListCollectionsResponse, error) {
+	return i.selectClient().ListCollections(ctx, in, opts...)
+}
+
+func (i *GrayScaleInteractiveClient) ListCollection(ctx context.Context, in *intrv1.ListCollectionRequest, opts ...grpc.CallOption) (*intrv1.ListCollectionResponse, error) {
+	return i.selectClient().ListCollection(ctx, in, opts...)
+}
+
 func (i *GrayScaleInteractiveClient) selectClient() intrv1.InteractiveServiceClient {
 	// [0, 100) 的随机数
 	num := rand.Int31n(100)
@@ -2,10 +2,14 @@ package client
 
 import (
 	"context"
+	"errors"
 	intrv1 "geektime/webook/api/proto/gen/intr/v1"
 	"geektime/webook/interactive/domain"
 	"geektime/webook/interactive/service"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
 )
 
 // InteractiveServiceAdapter
@@ -20,35 +24,128 @@ func NewInteractiveServiceAdapter(svc service.InteractiveService) *InteractiveSe
 
 func (i InteractiveServiceAdapter) IncrReadCnt(ctx context.Context, in *intrv1.IncrReadCntRequest, opts ...grpc.CallOption) (*intrv1.IncrReadCntResponse, error) {
 	err := i.svc.IncrReadCnt(ctx, in.Biz, in.BizId)
-	return &intrv1.IncrReadCntResponse{}, err
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.IncrReadCntResponse{}, nil
 }
 
 func (i InteractiveServiceAdapter) Like(ctx context.Context, in *intrv1.LikeRequest, opts ...grpc.CallOption) (*intrv1.LikeResponse, error) {
 	err := i.svc.Like(ctx, in.Biz, in.BizId, in.Uid)
-	return &intrv1.LikeResponse{}, err
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.LikeResponse{}, nil
 }
 
 func (i InteractiveServiceAdapter) CancelLike(ctx context.Context, in *intrv1.CancelLikeRequest, opts ...grpc.CallOption) (*intrv1.CancelLikeResponse, error) {
 	err := i.svc.CancelLike(ctx, in.Biz, in.BizId, in.Uid)
-	return &intrv1.CancelLikeResponse{}, err
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.CancelLikeResponse{}, nil
 }
 
 func (i InteractiveServiceAdapter) Collect(ctx context.Context, in *intrv1.CollectRequest, opts ...grpc.CallOption) (*intrv1.CollectResponse, error) {
 	err := i.svc.Collect(ctx, in.Biz, in.BizId, in.Cid, in.Uid)
-	return &intrv1.CollectResponse{}, err
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.CollectResponse{}, nil
+}
+
+func (i InteractiveServiceAdapter) CancelCollect(ctx context.Context, in *intrv1.CancelCollectRequest, opts ...grpc.CallOption) (*intrv1.CancelCollectResponse, error) {
+	err := i.svc.CancelCollect(ctx, in.Biz, in.BizId, in.Uid)
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.CancelCollectResponse{}, nil
+}
+
+func (i InteractiveServiceAdapter) CreateCollection(ctx context.Context, in *intrv1.CreateCollectionRequest, opts ...grpc.CallOption) (*intrv1.CreateCollectionResponse, error) {
+	id, err := i.svc.CreateCollection(ctx, domain.Collection{Uid: in.Uid, Name: in.Name})
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.CreateCollectionResponse{Id: id}, nil
+}
+
+func (i InteractiveServiceAdapter) RenameCollection(ctx context.Context, in *intrv1.RenameCollectionRequest, opts ...grpc.CallOption) (*intrv1.RenameCollectionResponse, error) {
+	err := i.svc.RenameCollection(ctx, in.Id, in.Uid, in.Name)
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.RenameCollectionResponse{}, nil
+}
+
+func (i InteractiveServiceAdapter) DeleteCollection(ctx context.Context, in *intrv1.DeleteCollectionRequest, opts ...grpc.CallOption) (*intrv1.DeleteCollectionResponse, error) {
+	err := i.svc.DeleteCollection(ctx, in.Id, in.Uid)
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	return &intrv1.DeleteCollectionResponse{}, nil
+}
+
+func (i InteractiveServiceAdapter) ListCollections(ctx context.Context, in *intrv1.ListCollectionsRequest, opts ...grpc.CallOption) (*intrv1.ListCollectionsResponse, error) {
+	cols, err := i.svc.ListCollections(ctx, in.Uid)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*intrv1.Collection, 0, len(cols))
+	for _, c := range cols {
+		res = append(res, &intrv1.Collection{
+			Id:    c.Id,
+			Uid:   c.Uid,
+			Name:  c.Name,
+			Ctime: c.Ctime.UnixMilli(),
+			Utime: c.Utime.UnixMilli(),
+		})
+	}
+	return &intrv1.ListCollectionsResponse{Collections: res}, nil
+}
+
+func (i InteractiveServiceAdapter) ListCollection(ctx context.Context, in *intrv1.ListCollectionRequest, opts ...grpc.CallOption) (*intrv1.ListCollectionResponse, error) {
+	items, err := i.svc.ListCollection(ctx, in.Uid, in.Cid, int(in.Offset), int(in.Limit))
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	res := make([]*intrv1.CollectionItem, 0, len(items))
+	for _, item := range items {
+		res = append(res, &intrv1.CollectionItem{
+			Cid:   item.Cid,
+			Biz:   item.Biz,
+			BizId: item.BizId,
+			Ctime: item.Ctime.UnixMilli(),
+		})
+	}
+	return &intrv1.ListCollectionResponse{Items: res}, nil
+}
+
+// toStatusErr 和远程调用一样返回 grpc 的错误码
+func (i InteractiveServiceAdapter) toStatusErr(err error) error {
+	switch {
+	case errors.Is(err, service.ErrCollectionNotFound),
+		errors.Is(err, service.ErrBizNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrIllegalCollection),
+		errors.Is(err, service.ErrUnknownBiz):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
 }
 
 func (i InteractiveServiceAdapter) Get(ctx context.Context, in *intrv1.GetRequest, opts ...grpc.CallOption) (*intrv1.GetResponse, error) {
 	intr, err := i.svc.Get(ctx, in.Biz, in.BizId, in.Uid)
 	return &intrv1.GetResponse{
 		Intr: i.toDTO(intr),
-	}, err
+	}, i.toStatusErr(err)
 }
 
 func (i InteractiveServiceAdapter) GetByIds(ctx context.Context, in *intrv1.GetByIdsRequest, opts ...grpc.CallOption) (*intrv1.GetByIdsResponse, error) {
 	res, err := i.svc.GetByIds(ctx, in.Biz, in.Ids)
 	if err != nil {
-		return nil, err
+		return nil, i.toStatusErr(err)
 	}
 	m := make(map[int64]*intrv1.Interactive, len(res))
 	for bizId, intr := range res {
@@ -59,6 +156,57 @@ func (i InteractiveServiceAdapter) GetByIds(ctx context.Context, in *intrv1.GetB
 	}, nil
 }
 
+func (i InteractiveServiceAdapter) ListHistory(ctx context.Context, in *intrv1.ListHistoryRequest, opts ...grpc.CallOption) (*intrv1.ListHistoryResponse, error) {
+	var cur domain.HistoryCursor
+	if in.CursorReadTime > 0 {
+		cur = domain.HistoryCursor{
+			ReadTime: time.UnixMilli(in.CursorReadTime),
+			Id:       in.CursorId,
+		}
+	}
+	records, err := i.svc.ListHistory(ctx, in.Uid, cur, int(in.Limit))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*intrv1.HistoryRecord, 0, len(records))
+	for _, r := range records {
+		res = append(res, &intrv1.HistoryRecord{
+			Id:       r.Id,
+			Biz:      r.Biz,
+			BizId:    r.BizId,
+			Uid:      r.Uid,
+			ReadTime: r.ReadTime.UnixMilli(),
+		})
+	}
+	return &intrv1.ListHistoryResponse{
+		Records: res,
+	}, nil
+}
+
+func (i InteractiveServiceAdapter) DeleteHistory(ctx context.Context, in *intrv1.DeleteHistoryRequest, opts ...grpc.CallOption) (*intrv1.DeleteHistoryResponse, error) {
+	err := i.svc.DeleteHistory(ctx, in.Uid, in.Biz, in.BizId)
+	return &intrv1.DeleteHistoryResponse{}, err
+}
+
+func (i InteractiveServiceAdapter) ClearHistory(ctx context.Context, in *intrv1.ClearHistoryRequest, opts ...grpc.CallOption) (*intrv1.ClearHistoryResponse, error) {
+	err := i.svc.ClearHistory(ctx, in.Uid)
+	return &intrv1.ClearHistoryResponse{}, err
+}
+
+func (i InteractiveServiceAdapter) GetByIdsWithUser(ctx context.Context, in *intrv1.GetByIdsWithUserRequest, opts ...grpc.CallOption) (*intrv1.GetByIdsWithUserResponse, error) {
+	res, err := i.svc.GetByIdsWithUser(ctx, in.Biz, in.Ids, in.Uid)
+	if err != nil {
+		return nil, i.toStatusErr(err)
+	}
+	m := make(map[int64]*intrv1.Interactive, len(res))
+	for bizId, intr := range res {
+		m[bizId] = i.toDTO(intr)
+	}
+	return &intrv1.GetByIdsWithUserResponse{
+		Intrs: m,
+	}, nil
+}
+
 // DTO data transfer obje0ct
 func (i *InteractiveServiceAdapter) toDTO(intr domain.Interactive) *intrv1.Interactive {
 	return &intrv1.Interactive{
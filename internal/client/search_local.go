@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	searchv1 "geektime/webook/api/proto/gen/search/v1"
+	"geektime/webook/search/domain"
+	"geektime/webook/search/service"
+	"google.golang.org/grpc"
+	"time"
+)
+
+// SearchServiceAdapter
+// 将本地调用适配给SearchServiceClient客户端，测试的时候不需要启动搜索服务
+type SearchServiceAdapter struct {
+	svc service.SearchService
+}
+
+func NewSearchServiceAdapter(svc service.SearchService) *SearchServiceAdapter {
+	return &SearchServiceAdapter{svc: svc}
+}
+
+func (s *SearchServiceAdapter) SearchArticle(ctx context.Context, in *searchv1.SearchArticleRequest, opts ...grpc.CallOption) (*searchv1.SearchArticleResponse, error) {
+	q := domain.ArticleSearchQuery{
+		Keywords: in.GetExpression(),
+		AuthorId: in.GetAuthorId(),
+		Offset:   int(in.GetOffset()),
+		Limit:    int(in.GetLimit()),
+	}
+	if in.GetStartTime() > 0 {
+		q.Start = time.UnixMilli(in.GetStartTime())
+	}
+	if in.GetEndTime() > 0 {
+		q.End = time.UnixMilli(in.GetEndTime())
+	}
+	res, err := s.svc.SearchArticle(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]*searchv1.ArticleHit, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		hits = append(hits, &searchv1.ArticleHit{
+			Article:          s.toDTO(hit.Article),
+			TitleHighlight:   hit.TitleHighlight,
+			ContentHighlight: hit.ContentHighlight,
+			Score:            hit.Score,
+		})
+	}
+	return &searchv1.SearchArticleResponse{Hits: hits, Total: res.Total}, nil
+}
+
+func (s *SearchServiceAdapter) InputArticle(ctx context.Context, in *searchv1.InputArticleRequest, opts ...grpc.CallOption) (*searchv1.InputArticleResponse, error) {
+	art := in.GetArticle()
+	err := s.svc.InputArticle(ctx, domain.Article{
+		Id:       art.GetId(),
+		Title:    art.GetTitle(),
+		Content:  art.GetContent(),
+		AuthorId: art.GetAuthorId(),
+		Status:   art.GetStatus(),
+		Ctime:    time.UnixMilli(art.GetCtime()),
+		Utime:    time.UnixMilli(art.GetUtime()),
+	})
+	return &searchv1.InputArticleResponse{}, err
+}
+
+func (s *SearchServiceAdapter) DeleteArticle(ctx context.Context, in *searchv1.DeleteArticleRequest, opts ...grpc.CallOption) (*searchv1.DeleteArticleResponse, error) {
+	err := s.svc.DeleteArticle(ctx, in.GetId())
+	return &searchv1.DeleteArticleResponse{}, err
+}
+
+func (s *SearchServiceAdapter) toDTO(art domain.Article) *searchv1.Article {
+	return &searchv1.Article{
+		Id:       art.Id,
+		Title:    art.Title,
+		Content:  art.Content,
+		AuthorId: art.AuthorId,
+		Status:   art.Status,
+		Ctime:    art.Ctime.UnixMilli(),
+		Utime:    art.Utime.UnixMilli(),
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+const (
+	AbuseActionRead = "read"
+	AbuseActionLike = "like"
+)
+
+// AbuseAudit 被限流拦下来的可疑操作，留给运维排查
+type AbuseAudit struct {
+	Id     int64
+	Uid    int64
+	Action string
+	Biz    string
+	BizId  int64
+	Ctime  time.Time
+}
@@ -1,15 +1,38 @@
 package domain
 
-import "time"
+import (
+	"geektime/webook/pkg/markdownx"
+	"time"
+)
+
+// AbstractLen 摘要最多的字符数
+const AbstractLen = 128
 
 type Article struct {
-	Id      int64
-	Title   string
+	Id    int64
+	Title string
+	// Content 作者写的 Markdown 原文
 	Content string
-	Author  Author
-	Status  ArticleStatus
-	Ctime   time.Time
-	Utime   time.Time
+	// ContentURL 内容放在对象存储的时候，读者可以通过这个有时效的链接读取内容，这时候 Content 是空的
+	ContentURL string
+	Author     Author
+	// CoAuthors 接受了邀请的合作者，只有线上库的帖子会查出来
+	CoAuthors []Author
+	Status    ArticleStatus
+	// Category 帖子的分类，一篇帖子只属于一个分类
+	Category string
+	// Tags 帖子的标签，一篇帖子可以有多个标签
+	Tags []string
+	// PublishAt 定时发表的时间，只有 ArticleStatusScheduled 状态下才有意义
+	PublishAt time.Time
+	// Rendered 发表的时候由 Content 计算出来，只有线上库的帖子有
+	Rendered RenderedContent
+	// Version 制作库的版本号，修改的时候要带上读出来的版本号
+	Version int64
+	// DeletedAt 放进回收站的时间，零值表示没有删除
+	DeletedAt time.Time
+	Ctime     time.Time
+	Utime     time.Time
 }
 
 type Author struct {
@@ -17,14 +40,40 @@ type Author struct {
 	Name string
 }
 
-// Abstract 帖子内容摘要
+// RenderedContent Markdown 渲染之后的结果
+type RenderedContent struct {
+	// HTML 过滤过的 HTML，可以直接展示
+	HTML string
+	// Abstract 纯文本摘要
+	Abstract string
+	// WordCnt 字数
+	WordCnt int64
+	// ReadingTime 预计阅读时间
+	ReadingTime time.Duration
+}
+
+// Render 渲染 Markdown，并算出摘要、字数和阅读时间
+func (a *Article) Render() error {
+	c, err := markdownx.Process(a.Content, AbstractLen)
+	if err != nil {
+		return err
+	}
+	a.Rendered = RenderedContent{
+		HTML:        c.HTML,
+		Abstract:    c.Abstract,
+		WordCnt:     int64(c.WordCnt),
+		ReadingTime: c.ReadingTime,
+	}
+	return nil
+}
+
+// Abstract 帖子内容的纯文本摘要
+// 发表过的帖子直接用发表时算好的，草稿就现算
 func (a Article) Abstract() string {
-	str := []rune(a.Content)
-	// 只取部分作为摘要
-	if len(str) > 128 {
-		str = str[:128]
+	if a.Rendered.Abstract != "" {
+		return a.Rendered.Abstract
 	}
-	return string(str)
+	return markdownx.Abstract(a.Content, AbstractLen)
 }
 
 type ArticleStatus uint8
@@ -42,4 +91,7 @@ const (
 	ArticleStatusPublished
 	// ArticleStatusPrivate 仅自己可见
 	ArticleStatusPrivate
+	// ArticleStatusScheduled 定时发表，介于未发表和已发表之间，到了发表时间才会同步到线上库
+	// 为了兼容已有的数据，值放在最后
+	ArticleStatusScheduled
 )
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// CoAuthor 帖子的合作者，帖子的作者本人就是 owner，不需要邀请
+type CoAuthor struct {
+	ArticleId int64
+	Uid       int64
+	Name      string
+	Role      CoAuthorRole
+	Status    CoAuthorStatus
+	// Inviter 发出邀请的人，目前只能是 owner
+	Inviter int64
+	Ctime   time.Time
+	Utime   time.Time
+}
+
+type CoAuthorRole uint8
+
+func (r CoAuthorRole) ToUint8() uint8 {
+	return uint8(r)
+}
+
+const (
+	// CoAuthorRoleUnknown 和帖子没有关系
+	CoAuthorRoleUnknown CoAuthorRole = iota
+	// CoAuthorRoleOwner 帖子的作者，什么都可以做
+	CoAuthorRoleOwner
+	// CoAuthorRoleEditor 可以修改和发表
+	CoAuthorRoleEditor
+	// CoAuthorRoleViewer 只能看草稿
+	CoAuthorRoleViewer
+)
+
+// Invitable 只能邀请别人做 editor 或者 viewer
+func (r CoAuthorRole) Invitable() bool {
+	return r == CoAuthorRoleEditor || r == CoAuthorRoleViewer
+}
+
+// CanView 查看制作库的帖子
+func (r CoAuthorRole) CanView() bool {
+	return r == CoAuthorRoleOwner || r == CoAuthorRoleEditor || r == CoAuthorRoleViewer
+}
+
+// CanEdit 修改、发表和定时发表
+func (r CoAuthorRole) CanEdit() bool {
+	return r == CoAuthorRoleOwner || r == CoAuthorRoleEditor
+}
+
+// CanManage 撤回帖子和管理合作者
+func (r CoAuthorRole) CanManage() bool {
+	return r == CoAuthorRoleOwner
+}
+
+type CoAuthorStatus uint8
+
+func (s CoAuthorStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	CoAuthorStatusUnknown CoAuthorStatus = iota
+	// CoAuthorStatusPending 已经邀请，还没有接受
+	CoAuthorStatusPending
+	// CoAuthorStatusAccepted 接受了邀请，只有这个状态下角色才生效
+	CoAuthorStatusAccepted
+	// CoAuthorStatusRevoked 被 owner 撤销，或者自己退出了
+	CoAuthorStatusRevoked
+)
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("非法的翻页游标")
+
+// ArticleCursor 按照更新时间、id 倒序翻页的位置，零值表示第一页
+type ArticleCursor struct {
+	Utime time.Time
+	Id    int64
+}
+
+func (c ArticleCursor) IsZero() bool {
+	return c.Utime.IsZero() && c.Id == 0
+}
+
+// Encode 前端拿到的游标，不需要知道里面是什么，第一页是空字符串
+func (c ArticleCursor) Encode() string {
+	if c.IsZero() {
+		return ""
+	}
+	raw := strconv.FormatInt(c.Utime.UnixMilli(), 10) + "," + strconv.FormatInt(c.Id, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseArticleCursor 解析 Encode 的结果
+func ParseArticleCursor(s string) (ArticleCursor, error) {
+	if s == "" {
+		return ArticleCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+	utimeStr, idStr, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+	utime, err := strconv.ParseInt(utimeStr, 10, 64)
+	if err != nil || utime <= 0 {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+	return ArticleCursor{Utime: time.UnixMilli(utime), Id: id}, nil
+}
+
+// NextArticleCursor 下一页的游标，不够一页说明没有下一页了，返回零值
+func NextArticleCursor(arts []Article, limit int) ArticleCursor {
+	if len(arts) == 0 || len(arts) < limit {
+		return ArticleCursor{}
+	}
+	last := arts[len(arts)-1]
+	return ArticleCursor{Utime: last.Utime, Id: last.Id}
+}
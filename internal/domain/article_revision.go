@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// ArticleRevision 制作库帖子的一个历史版本
+// 每次保存或者发表都会生成一个，生成之后不会再修改
+type ArticleRevision struct {
+	Id int64
+	// Article 保存时帖子的快照，Article.Id 就是帖子的 id
+	Article Article
+	Ctime   time.Time
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// MaxSeriesTitleLen 专栏标题最多多少个字
+const MaxSeriesTitleLen = 64
+
+// ArticleSeries 作者把多篇帖子按照顺序组织成专栏，一篇帖子只能在一个专栏里面
+type ArticleSeries struct {
+	Id          int64
+	Title       string
+	Description string
+	Author      Author
+	// Articles 按照专栏里面的顺序排列，作者看到的包括还没发表的帖子
+	Articles []Article
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+// SeriesNav 读者看帖子的时候专栏里面的上一篇和下一篇
+// 撤回了和仅自己可见的帖子会被跳过，Id 为 0 表示没有
+type SeriesNav struct {
+	// Series 只有 Id 和 Title，帖子不在专栏里面的时候 Id 为 0
+	Series ArticleSeries
+	Prev   Article
+	Next   Article
+}
@@ -0,0 +1,14 @@
+package domain
+
+// AuthorProfilePageSize 作者主页每一页的帖子数量，第一页和主页的其它信息一起缓存
+const AuthorProfilePageSize = 20
+
+// AuthorProfile 作者的公开主页，不包括关注数据，关注数据在 follow 服务里面
+type AuthorProfile struct {
+	Author  Author
+	AboutMe string
+	// ArticleCnt 已发表的帖子数量，仅自己可见的不算
+	ArticleCnt int64
+	// Articles 第一页已发表的帖子，只有摘要
+	Articles []Article
+}
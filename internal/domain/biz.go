@@ -0,0 +1,8 @@
+package domain
+
+// 点赞、收藏、阅读这些交互数据挂在哪种资源上面，和 interactive 里面注册的一致
+const (
+	BizArticle = "article"
+	BizSeries  = "series"
+	BizComment = "comment"
+)
@@ -1,7 +0,0 @@
-package domain
-
-type HistoryRecord struct {
-	BizId int64
-	Biz   string
-	Uid   int64
-}
@@ -1,10 +1,30 @@
 package domain
 
 import (
+	"context"
 	"github.com/robfig/cron/v3"
 	"time"
 )
 
+// jobParser 和调度器一致，支持秒级的表达式和 @every 这种描述符
+var jobParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour |
+	cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+type JobStatus uint8
+
+const (
+	// JobStatusWaiting 等待调度
+	JobStatusWaiting JobStatus = iota
+	// JobStatusRunning 正在某个节点上执行
+	JobStatusRunning
+	// JobStatusPaused 暂停了，不会被调度
+	JobStatusPaused
+)
+
+func (s JobStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
 type Job struct {
 	Id   int64
 	Name string
@@ -12,12 +32,54 @@ type Job struct {
 	Expression string
 	Executor   string
 	Cfg        string
+	Status     JobStatus
+	// Version 抢占成功之后的版本号，续约、释放和重置下次执行时间都要带上，
+	// 租约过期被别的节点抢走之后，旧的版本号就不能再修改这个 job 了
+	Version int64
+	// NextExecTime 下一次被调度的时间
+	NextExecTime time.Time
+	Ctime        time.Time
+	Utime        time.Time
+	// Ctx 抢占成功之后才有，续约发现已经被别的节点抢走的时候会被取消，
+	// 执行 job 要用它，不然新旧两个节点会同时执行
+	Ctx        context.Context
 	CancelFunc func()
 }
 
+// ValidExpression 表达式解析不了的 job 永远调度不到，写入之前要先校验
+func (j Job) ValidExpression() bool {
+	_, err := jobParser.Parse(j.Expression)
+	return err == nil
+}
+
 func (j Job) NextTime() time.Time {
-	c := cron.NewParser(cron.Second | cron.Minute | cron.Hour |
-		cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
-	s, _ := c.Parse(j.Expression)
+	s, _ := jobParser.Parse(j.Expression)
 	return s.Next(time.Now())
 }
+
+type JobExecutionStatus uint8
+
+const (
+	JobExecutionUnknown JobExecutionStatus = iota
+	// JobExecutionRunning 还没执行完，节点中途崩溃的话会一直停留在这个状态
+	JobExecutionRunning
+	JobExecutionSucceeded
+	JobExecutionFailed
+)
+
+func (s JobExecutionStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+// JobExecution job 的一次执行记录
+type JobExecution struct {
+	Id  int64
+	Jid int64
+	// Node 执行的节点
+	Node   string
+	Status JobExecutionStatus
+	// Err 失败的原因
+	Err       string
+	StartTime time.Time
+	EndTime   time.Time
+}
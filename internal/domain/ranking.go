@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// RankingBoard 一个榜单，比如日榜、周榜、总榜、某个标签的榜，在配置里面定义
+type RankingBoard struct {
+	Name string
+	// Period 只统计这段时间内更新过的帖子，0 表示不限制
+	Period time.Duration
+	// Tag 只统计带这个标签的帖子，空表示不限制
+	Tag string
+	// N 榜单上最多多少个帖子
+	N       int
+	Formula RankingFormula
+}
+
+// Match 帖子能不能上这个榜
+func (b RankingBoard) Match(art Article, now time.Time) bool {
+	if b.Period > 0 && now.Sub(art.Utime) > b.Period {
+		return false
+	}
+	return b.Tag == "" || slices.Contains(art.Tags, b.Tag)
+}
+
+// Incremental 按照半衰期衰减的榜单可以根据计数变化增量更新
+func (b RankingBoard) Incremental() bool {
+	return b.Formula.HalfLife > 0
+}
+
+// RankingFormula 热度 = 各项计数的加权和 / (小时数 + 2) ^ Gravity
+// Gravity 越大旧帖子掉得越快，0 就是不考虑时间
+// 配置了 HalfLife 的时候改成 加权和 * 2 ^ (-年龄 / HalfLife)，忽略 Gravity
+type RankingFormula struct {
+	Like     float64
+	Read     float64
+	Collect  float64
+	Comment  float64
+	Gravity  float64
+	HalfLife time.Duration
+}
+
+func (f RankingFormula) Score(cnt RankingCnt, age time.Duration) float64 {
+	weighted := f.Weighted(cnt)
+	if f.HalfLife > 0 {
+		return weighted * f.Decay(age)
+	}
+	if f.Gravity == 0 {
+		return weighted
+	}
+	return weighted / math.Pow(max(age.Hours(), 0)+2, f.Gravity)
+}
+
+// Weighted 各项计数的加权和，cnt 也可以是计数的变化
+func (f RankingFormula) Weighted(cnt RankingCnt) float64 {
+	return f.Like*float64(cnt.Like) +
+		f.Read*float64(cnt.Read) +
+		f.Collect*float64(cnt.Collect) +
+		f.Comment*float64(cnt.Comment)
+}
+
+// Decay 半衰期衰减的系数，age 是负数的时候大于 1
+func (f RankingFormula) Decay(age time.Duration) float64 {
+	return math.Exp2(-float64(age) / float64(f.HalfLife))
+}
+
+// RankingCnt 计算热度用到的计数
+type RankingCnt struct {
+	Like    int64
+	Read    int64
+	Collect int64
+	Comment int64
+}
+
+// RankingItem 榜单上的一项，按照 Score 从高到低排
+type RankingItem struct {
+	BizId int64
+	Score float64
+}
+
+// RankingDelta 增量更新的时候一个帖子的分数变化，还没有乘上衰减系数
+type RankingDelta struct {
+	BizId int64
+	Utime time.Time
+	// Weighted 计数变化的加权和
+	Weighted float64
+}
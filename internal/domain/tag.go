@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTagCnt 一篇帖子最多的标签数量
+	MaxTagCnt = 5
+	// MaxTagLen 标签最长的字数
+	MaxTagLen = 32
+	// MaxCategoryLen 分类最长的字数
+	MaxCategoryLen = 32
+)
+
+// TagCount 某个标签下已发表的帖子数量
+type TagCount struct {
+	Tag   string
+	Count int64
+}
+
+// NormalizeTags 去掉标签首尾的空白，过滤掉空的和重复的标签，保持原本的顺序
+func NormalizeTags(tags []string) []string {
+	res := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		res = append(res, tag)
+	}
+	return res
+}
+
+// ValidTags 标签的数量和长度都不能超过限制
+func ValidTags(tags []string) bool {
+	if len(tags) > MaxTagCnt {
+		return false
+	}
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > MaxTagLen {
+			return false
+		}
+	}
+	return true
+}
@@ -14,6 +14,8 @@ const (
 
 const (
 	// ArticleInvalidInput 文章模块的统一的错误码
-	ArticleInvalidInput        = 402001
+	ArticleInvalidInput = 402001
+	// ArticleVersionConflict 帖子已经被别人（或者另外一个页面）修改过了，data 里面是当前的版本号
+	ArticleVersionConflict     = 402002
 	ArticleInternalServerError = 502001
 )
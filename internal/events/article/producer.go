@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/IBM/sarama"
+	"strconv"
 )
 
 type Producer interface {
 	ProduceReadEvent(ctx context.Context, evt ReadEvent) error
+	ProducePublishedEvent(ctx context.Context, evt PublishedEvent) error
+	ProduceDeletedEvent(ctx context.Context, evt DeletedEvent) error
 }
 
 type KafkaProducer struct {
@@ -31,8 +34,62 @@ func (k *KafkaProducer) ProduceReadEvent(ctx context.Context, evt ReadEvent) err
 	return err
 }
 
-// ReadEvent 某个用户读了某篇文章
+// ProducePublishedEvent 发送线上库帖子变化的消息，搜索等下游据此更新
+func (k *KafkaProducer) ProducePublishedEvent(ctx context.Context, evt PublishedEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = k.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: "article_published",
+		// 同一篇帖子的消息进同一个分区，保证发表和撤回的顺序
+		Key:   sarama.StringEncoder(strconv.FormatInt(evt.Id, 10)),
+		Value: sarama.ByteEncoder(data),
+	})
+	return err
+}
+
+// ProduceDeletedEvent 发送帖子被彻底删除的消息，点赞收藏、评论等下游据此清理数据
+func (k *KafkaProducer) ProduceDeletedEvent(ctx context.Context, evt DeletedEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = k.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: "article_deleted",
+		Key:   sarama.StringEncoder(strconv.FormatInt(evt.BizId, 10)),
+		Value: sarama.ByteEncoder(data),
+	})
+	return err
+}
+
+// ReadEvent 某个用户读了某个资源
 type ReadEvent struct {
-	Uid int64
+	Uid   int64
+	Biz   string
+	BizId int64
+	// Aid 旧的消费者只认这个字段，Biz 是 article 的时候和 BizId 一样
 	Aid int64
+	// Counted 这次阅读要不要计入阅读数，窗口期内重复阅读和刷量的是 false
+	// 阅读历史不管这个字段，重复阅读也要更新最近阅读时间
+	Counted bool
+}
+
+// PublishedEvent 帖子发表或者撤回了
+// 撤回的时候只有 Id 和 Status 有意义
+type PublishedEvent struct {
+	Id       int64
+	Title    string
+	Content  string
+	AuthorId int64
+	Status   uint8
+	// 毫秒数
+	Ctime int64
+	Utime int64
+}
+
+// DeletedEvent 帖子从回收站里面彻底删除了
+type DeletedEvent struct {
+	Biz   string
+	BizId int64
 }
@@ -0,0 +1,99 @@
+package ranking
+
+import (
+	"context"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	"geektime/webook/pkg/logger"
+	"geektime/webook/pkg/saramax"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+var _ saramax.Consumer = &CntChangeConsumer{}
+
+const (
+	// flushSize 攒够这么多条计数变化就更新一次榜单
+	flushSize = 500
+	// flushInterval 攒不够的时候最多等这么久
+	flushInterval = time.Second * 3
+)
+
+// CntChangeConsumer 消费 interactive 发出来的计数变化，增量更新半衰期榜单
+// 同一个帖子的变化先在内存里面合并，一批只更新一次
+type CntChangeConsumer struct {
+	svc    service.IncrRankingService
+	client sarama.Client
+	l      logger.LoggerV1
+	m      *saramax.FlushMetrics
+}
+
+func NewCntChangeConsumer(svc service.IncrRankingService,
+	client sarama.Client, l logger.LoggerV1) *CntChangeConsumer {
+	return &CntChangeConsumer{
+		svc:    svc,
+		client: client,
+		l:      l,
+		m:      saramax.NewFlushMetrics("lll", "webook", "ranking"),
+	}
+}
+
+func (c *CntChangeConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("ranking", c.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(), []string{"interactive_changes"},
+			saramax.NewFlushHandler[CntChangeEvent](c.l, c.m,
+				flushSize, flushInterval, c.BatchConsume))
+		if er != nil {
+			c.l.Error("退出消费", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+// BatchConsume 失败重试的时候，已经更新过的榜单会重复加，等全量校正
+func (c *CntChangeConsumer) BatchConsume(msgs []*sarama.ConsumerMessage,
+	events []CntChangeEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	cnts := make(map[int64]domain.RankingCnt, len(events))
+	for _, evt := range events {
+		// 榜单上目前只有帖子
+		if evt.Biz != domain.BizArticle {
+			continue
+		}
+		cnt := cnts[evt.BizId]
+		switch evt.Field {
+		case fieldLike:
+			cnt.Like += evt.Delta
+		case fieldCollect:
+			cnt.Collect += evt.Delta
+		case fieldRead:
+			cnt.Read += evt.Delta
+		default:
+			continue
+		}
+		cnts[evt.BizId] = cnt
+	}
+	return c.svc.Incr(ctx, cnts)
+}
+
+const (
+	fieldLike    = "like"
+	fieldCollect = "collect"
+	fieldRead    = "read"
+)
+
+// CntChangeEvent interactive 发出来的计数变化
+type CntChangeEvent struct {
+	Biz   string
+	BizId int64
+	// Field like、collect 或者 read
+	Field string
+	Delta int64
+	// Ctime 毫秒数
+	Ctime int64
+}
@@ -0,0 +1,472 @@
+package integration
+
+import (
+	"context"
+	"geektime/webook/internal/repository/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// articleDAOContract 所有 dao.ArticleDAO 的实现都要通过的测试，
+// GORM 和 MongoDB 的测试套件用同一份用例，保证两者可以互相替换
+type articleDAOContract struct {
+	dao dao.ArticleDAO
+	// reset 清空数据，每个用例执行完都会调用
+	reset func()
+}
+
+func (c articleDAOContract) run(t *testing.T) {
+	testCases := []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, d dao.ArticleDAO)
+	}{
+		{
+			name: "新建和查询",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				id, err := d.Insert(ctx, dao.Article{
+					Title:    "我的标题",
+					Content:  "我的内容",
+					AuthorId: 123,
+					Status:   1,
+					Category: "Go",
+					Tags:     []string{"go", "grpc"},
+				})
+				require.NoError(t, err)
+				assert.True(t, id > 0)
+				for _, find := range []func(ctx context.Context, id int64) (dao.Article, error){d.GetById, d.FindById} {
+					art, err := find(ctx, id)
+					require.NoError(t, err)
+					assert.Equal(t, id, art.Id)
+					assert.Equal(t, "我的标题", art.Title)
+					assert.Equal(t, "我的内容", art.Content)
+					assert.Equal(t, int64(123), art.AuthorId)
+					assert.Equal(t, uint8(1), art.Status)
+					assert.Equal(t, "Go", art.Category)
+					assert.ElementsMatch(t, []string{"go", "grpc"}, art.Tags)
+					assert.True(t, art.Ctime > 0)
+					assert.Equal(t, art.Ctime, art.Utime)
+					assert.Equal(t, int64(1), art.Version)
+				}
+				_, err = d.GetById(ctx, id+1)
+				assert.Error(t, err)
+				revs, err := d.ListRevisions(ctx, id, 0, 10)
+				require.NoError(t, err)
+				assert.Len(t, revs, 1)
+			},
+		},
+		{
+			name: "修改",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				id, err := d.Insert(ctx, dao.Article{Title: "我的标题", Content: "我的内容", AuthorId: 123, Status: 1})
+				require.NoError(t, err)
+				// 别人的帖子
+				err = d.Update(ctx, dao.Article{Id: id, Title: "新的标题", AuthorId: 456, Status: 1, Version: 1})
+				assert.Error(t, err)
+				err = d.Update(ctx, dao.Article{Id: id, Title: "新的标题", Content: "新的内容",
+					AuthorId: 123, Status: 1, Tags: []string{"go"}, Version: 1})
+				require.NoError(t, err)
+				art, err := d.GetById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, "新的标题", art.Title)
+				assert.Equal(t, "新的内容", art.Content)
+				assert.Equal(t, []string{"go"}, art.Tags)
+				assert.Equal(t, int64(2), art.Version)
+				assert.True(t, art.Utime >= art.Ctime)
+				// 另外一个页面还拿着旧的版本号
+				err = d.Update(ctx, dao.Article{Id: id, Title: "旧页面的标题", AuthorId: 123, Status: 1, Version: 1})
+				assert.Equal(t, dao.ArticleVersionConflictError{Current: 2}, err)
+				art, err = d.GetById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, "新的标题", art.Title)
+				revs, err := d.ListRevisions(ctx, id, 0, 10)
+				require.NoError(t, err)
+				assert.Len(t, revs, 2)
+			},
+		},
+		{
+			name: "发表新帖子",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				id, err := d.Sync(ctx, dao.Article{Title: "我的标题", Content: "我的内容",
+					AuthorId: 123, Status: 2, Tags: []string{"go"}, HTML: "<p>我的内容</p>"})
+				require.NoError(t, err)
+				assert.True(t, id > 0)
+				art, err := d.GetById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, uint8(2), art.Status)
+				// 渲染结果只放线上库
+				assert.Equal(t, "", art.HTML)
+				pub, err := d.GetPubById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, id, pub.Id)
+				assert.Equal(t, "我的标题", pub.Title)
+				assert.Equal(t, "我的内容", pub.Content)
+				assert.Equal(t, int64(123), pub.AuthorId)
+				assert.Equal(t, uint8(2), pub.Status)
+				assert.Equal(t, []string{"go"}, pub.Tags)
+				assert.Equal(t, "<p>我的内容</p>", pub.HTML)
+				assert.True(t, pub.Ctime > 0)
+			},
+		},
+		{
+			name: "修改之后重新发表",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				id, err := d.Sync(ctx, dao.Article{Title: "我的标题", Content: "我的内容", AuthorId: 123, Status: 2})
+				require.NoError(t, err)
+				first, err := d.GetPubById(ctx, id)
+				require.NoError(t, err)
+				newId, err := d.Sync(ctx, dao.Article{Id: id, Title: "新的标题", Content: "新的内容",
+					AuthorId: 123, Status: 2, Version: 1})
+				require.NoError(t, err)
+				assert.Equal(t, id, newId)
+				pub, err := d.GetPubById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, "新的标题", pub.Title)
+				assert.Equal(t, "新的内容", pub.Content)
+				// 创建时间不变
+				assert.Equal(t, first.Ctime, pub.Ctime)
+				// 别人的帖子
+				_, err = d.Sync(ctx, dao.Article{Id: id, Title: "别人的标题", AuthorId: 456, Status: 2, Version: 2})
+				assert.Error(t, err)
+				pub, err = d.GetPubById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, "新的标题", pub.Title)
+			},
+		},
+		{
+			name: "同步状态",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				id, err := d.Sync(ctx, dao.Article{Title: "我的标题", Content: "我的内容", AuthorId: 123, Status: 2})
+				require.NoError(t, err)
+				err = d.SyncStatus(ctx, id, 456, 3)
+				assert.Error(t, err)
+				err = d.SyncStatus(ctx, id, 123, 3)
+				require.NoError(t, err)
+				art, err := d.GetById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, uint8(3), art.Status)
+				pub, err := d.GetPubById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, uint8(3), pub.Status)
+			},
+		},
+		{
+			name: "作者的帖子分页",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				for i := 0; i < 5; i++ {
+					_, err := d.Insert(ctx, dao.Article{Title: strconv.Itoa(i), AuthorId: 123, Status: 1})
+					require.NoError(t, err)
+					// utime 是毫秒，避免排序的时候相等
+					time.Sleep(time.Millisecond * 2)
+				}
+				_, err := d.Insert(ctx, dao.Article{Title: "别人的", AuthorId: 456, Status: 1})
+				require.NoError(t, err)
+
+				arts, err := d.GetByAuthor(ctx, 123, 0, 2)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"4", "3"}, articleTitles(arts))
+				arts, err = d.GetByAuthor(ctx, 123, 2, 2)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"2", "1"}, articleTitles(arts))
+				arts, err = d.GetByAuthor(ctx, 123, 4, 2)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"0"}, articleTitles(arts))
+				arts, err = d.GetByAuthor(ctx, 789, 0, 2)
+				require.NoError(t, err)
+				assert.Len(t, arts, 0)
+			},
+		},
+		{
+			name: "游标翻页",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				for i := 0; i < 5; i++ {
+					_, err := d.Sync(ctx, dao.Article{Title: strconv.Itoa(i), AuthorId: 123, Status: 2})
+					require.NoError(t, err)
+					time.Sleep(time.Millisecond * 2)
+				}
+				arts, err := d.GetByAuthorAfter(ctx, 123, dao.ArticleCursor{}, 2)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"4", "3"}, articleTitles(arts))
+				// 翻页的过程中插入了新帖子，不会影响后面的页
+				_, err = d.Sync(ctx, dao.Article{Title: "5", AuthorId: 123, Status: 2})
+				require.NoError(t, err)
+				last := arts[len(arts)-1]
+				arts, err = d.GetByAuthorAfter(ctx, 123, dao.ArticleCursor{Utime: last.Utime, Id: last.Id}, 2)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"2", "1"}, articleTitles(arts))
+				last = arts[len(arts)-1]
+				arts, err = d.GetByAuthorAfter(ctx, 123, dao.ArticleCursor{Utime: last.Utime, Id: last.Id}, 2)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"0"}, articleTitles(arts))
+
+				pubs, err := d.ListPubAfter(ctx, dao.ArticleCursor{}, 3)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"5", "4", "3"}, pubTitles(pubs))
+				lastPub := pubs[len(pubs)-1]
+				pubs, err = d.ListPubAfter(ctx, dao.ArticleCursor{Utime: lastPub.Utime, Id: lastPub.Id}, 3)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"2", "1", "0"}, pubTitles(pubs))
+			},
+		},
+		{
+			name: "查询已发表的帖子",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				before := time.Now().Add(-time.Second)
+				ids := make([]int64, 0, 3)
+				for i := 0; i < 3; i++ {
+					id, err := d.Sync(ctx, dao.Article{Title: strconv.Itoa(i), AuthorId: 123, Status: 2})
+					require.NoError(t, err)
+					ids = append(ids, id)
+					time.Sleep(time.Millisecond * 2)
+				}
+				// 仅自己可见的不会出现
+				err := d.SyncStatus(ctx, ids[1], 123, 3)
+				require.NoError(t, err)
+
+				start := time.Now().Add(time.Second)
+				pubs, err := d.ListPub(ctx, start, 0, 10)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"2", "0"}, pubTitles(pubs))
+				pubs, err = d.ListPub(ctx, start, 1, 10)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"0"}, pubTitles(pubs))
+				// start 之前没有发表过帖子
+				pubs, err = d.ListPub(ctx, before, 0, 10)
+				require.NoError(t, err)
+				assert.Len(t, pubs, 0)
+			},
+		},
+		{
+			name: "作者已发表的帖子",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				ids := make([]int64, 0, 4)
+				for i := 0; i < 4; i++ {
+					id, err := d.Sync(ctx, dao.Article{Title: strconv.Itoa(i), AuthorId: 123, Status: 2})
+					require.NoError(t, err)
+					ids = append(ids, id)
+					time.Sleep(time.Millisecond * 2)
+				}
+				// 别人的帖子和仅自己可见的都不算
+				_, err := d.Sync(ctx, dao.Article{Title: "别人的", AuthorId: 456, Status: 2})
+				require.NoError(t, err)
+				err = d.SyncStatus(ctx, ids[2], 123, 3)
+				require.NoError(t, err)
+
+				cnt, err := d.CountPubByAuthor(ctx, 123)
+				require.NoError(t, err)
+				assert.Equal(t, int64(3), cnt)
+				pubs, err := d.ListPubByAuthor(ctx, 123, dao.ArticleCursor{}, 2)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"3", "1"}, pubTitles(pubs))
+				last := pubs[len(pubs)-1]
+				pubs, err = d.ListPubByAuthor(ctx, 123, dao.ArticleCursor{Utime: last.Utime, Id: last.Id}, 2)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"0"}, pubTitles(pubs))
+			},
+		},
+		{
+			name: "按照 id 查询已发表的帖子",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				ids := make([]int64, 0, 3)
+				for i := 0; i < 3; i++ {
+					id, err := d.Sync(ctx, dao.Article{Title: strconv.Itoa(i), Content: "内容", AuthorId: 123, Status: 2})
+					require.NoError(t, err)
+					ids = append(ids, id)
+				}
+				err := d.SyncStatus(ctx, ids[1], 123, 3)
+				require.NoError(t, err)
+				pubs, err := d.ListPubByIds(ctx, append(ids, 10086))
+				require.NoError(t, err)
+				sort.Slice(pubs, func(i, j int) bool {
+					return pubs[i].Id < pubs[j].Id
+				})
+				assert.Equal(t, []string{"0", "2"}, pubTitles(pubs))
+				// 不需要内容
+				for _, pub := range pubs {
+					assert.Empty(t, pub.Content)
+				}
+				pubs, err = d.ListPubByIds(ctx, nil)
+				require.NoError(t, err)
+				assert.Len(t, pubs, 0)
+			},
+		},
+		{
+			name: "直接写线上库",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				err := d.Upsert(ctx, dao.PublishArticle{Id: 100, Title: "我的标题", AuthorId: 123, Status: 2})
+				require.NoError(t, err)
+				first, err := d.GetPubById(ctx, 100)
+				require.NoError(t, err)
+				assert.Equal(t, "我的标题", first.Title)
+				assert.True(t, first.Ctime > 0)
+				err = d.Upsert(ctx, dao.PublishArticle{Id: 100, Title: "新的标题", AuthorId: 123, Status: 2})
+				require.NoError(t, err)
+				pub, err := d.GetPubById(ctx, 100)
+				require.NoError(t, err)
+				assert.Equal(t, "新的标题", pub.Title)
+				assert.Equal(t, first.Ctime, pub.Ctime)
+			},
+		},
+		{
+			name: "定时发表",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				publishAt := time.Now().Add(-time.Second).UnixMilli()
+				id, err := d.Insert(ctx, dao.Article{Title: "我的标题", Content: "我的内容",
+					AuthorId: 123, Status: 4, PublishAt: publishAt})
+				require.NoError(t, err)
+				// 还没到时间的
+				_, err = d.Insert(ctx, dao.Article{Title: "明天", AuthorId: 123, Status: 4,
+					PublishAt: time.Now().Add(time.Hour * 24).UnixMilli()})
+				require.NoError(t, err)
+
+				arts, err := d.ListScheduled(ctx, time.Now(), 10)
+				require.NoError(t, err)
+				require.Len(t, arts, 1)
+				art := arts[0]
+				assert.Equal(t, id, art.Id)
+
+				// 查出来之后又被修改过
+				_, err = d.PublishScheduled(ctx, dao.Article{Id: id, Utime: art.Utime - 1})
+				assert.Equal(t, dao.ErrArticleNotScheduled, err)
+				res, err := d.PublishScheduled(ctx, dao.Article{Id: id, Utime: art.Utime, HTML: "<p>我的内容</p>"})
+				require.NoError(t, err)
+				assert.Equal(t, uint8(2), res.Status)
+				pub, err := d.GetPubById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, "我的标题", pub.Title)
+				assert.Equal(t, uint8(2), pub.Status)
+				assert.Equal(t, "<p>我的内容</p>", pub.HTML)
+				art, err = d.GetById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, uint8(2), art.Status)
+				assert.Equal(t, int64(0), art.PublishAt)
+				// 不能重复发表
+				_, err = d.PublishScheduled(ctx, dao.Article{Id: id, Utime: art.Utime})
+				assert.Equal(t, dao.ErrArticleNotScheduled, err)
+			},
+		},
+		{
+			name: "取消定时发表",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				id, err := d.Insert(ctx, dao.Article{Title: "我的标题", AuthorId: 123, Status: 4,
+					PublishAt: time.Now().Add(time.Hour).UnixMilli()})
+				require.NoError(t, err)
+				err = d.CancelSchedule(ctx, id, 456)
+				assert.Equal(t, dao.ErrArticleNotScheduled, err)
+				err = d.CancelSchedule(ctx, id, 123)
+				require.NoError(t, err)
+				art, err := d.GetById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, uint8(1), art.Status)
+				assert.Equal(t, int64(0), art.PublishAt)
+				err = d.CancelSchedule(ctx, id, 123)
+				assert.Equal(t, dao.ErrArticleNotScheduled, err)
+			},
+		},
+		{
+			name: "标签",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				_, err := d.Sync(ctx, dao.Article{Title: "0", AuthorId: 123, Status: 2, Tags: []string{"go", "grpc"}})
+				require.NoError(t, err)
+				time.Sleep(time.Millisecond * 2)
+				_, err = d.Sync(ctx, dao.Article{Title: "1", AuthorId: 123, Status: 2, Tags: []string{"go"}})
+				require.NoError(t, err)
+
+				pubs, err := d.ListPubByTag(ctx, "go", 0, 10)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"1", "0"}, pubTitles(pubs))
+				pubs, err = d.ListPubByTag(ctx, "grpc", 0, 10)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"0"}, pubTitles(pubs))
+				cnts, err := d.CountPubTags(ctx, 10)
+				require.NoError(t, err)
+				assert.Equal(t, []dao.TagCount{{Tag: "go", Cnt: 2}, {Tag: "grpc", Cnt: 1}}, cnts)
+			},
+		},
+		{
+			name: "回收站",
+			test: func(t *testing.T, ctx context.Context, d dao.ArticleDAO) {
+				id, err := d.Sync(ctx, dao.Article{Title: "我的标题", Content: "我的内容", AuthorId: 123, Status: 2})
+				require.NoError(t, err)
+				// 别人的帖子
+				err = d.Delete(ctx, id, 456)
+				assert.Error(t, err)
+				err = d.Delete(ctx, id, 123)
+				require.NoError(t, err)
+				// 不能重复删除
+				err = d.Delete(ctx, id, 123)
+				assert.Error(t, err)
+				art, err := d.GetById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, uint8(3), art.Status)
+				assert.True(t, art.DeletedAt > 0)
+				pub, err := d.GetPubById(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, uint8(3), pub.Status)
+				arts, err := d.GetByAuthor(ctx, 123, 0, 10)
+				require.NoError(t, err)
+				assert.Len(t, arts, 0)
+				arts, err = d.ListTrash(ctx, 123, 0, 10)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"我的标题"}, articleTitles(arts))
+
+				arts, err = d.ListExpiredTrash(ctx, time.Now().Add(-time.Hour), 10)
+				require.NoError(t, err)
+				assert.Len(t, arts, 0)
+				arts, err = d.ListExpiredTrash(ctx, time.Now().Add(time.Second), 10)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"我的标题"}, articleTitles(arts))
+
+				// 恢复之后不能彻底删除
+				err = d.Restore(ctx, id, 123)
+				require.NoError(t, err)
+				err = d.Restore(ctx, id, 123)
+				assert.Equal(t, dao.ErrArticleNotInTrash, err)
+				err = d.Purge(ctx, id)
+				assert.Equal(t, dao.ErrArticleNotInTrash, err)
+				arts, err = d.GetByAuthor(ctx, 123, 0, 10)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"我的标题"}, articleTitles(arts))
+
+				err = d.Delete(ctx, id, 123)
+				require.NoError(t, err)
+				err = d.Purge(ctx, id)
+				require.NoError(t, err)
+				_, err = d.GetById(ctx, id)
+				assert.Error(t, err)
+				_, err = d.GetPubById(ctx, id)
+				assert.Error(t, err)
+				arts, err = d.ListTrash(ctx, 123, 0, 10)
+				require.NoError(t, err)
+				assert.Len(t, arts, 0)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer c.reset()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+			tc.test(t, ctx, c.dao)
+		})
+	}
+}
+
+func articleTitles(arts []dao.Article) []string {
+	res := make([]string, 0, len(arts))
+	for _, art := range arts {
+		res = append(res, art.Title)
+	}
+	return res
+}
+
+func pubTitles(arts []dao.PublishArticle) []string {
+	res := make([]string, 0, len(arts))
+	for _, art := range arts {
+		res = append(res, art.Title)
+	}
+	return res
+}
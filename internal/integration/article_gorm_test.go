@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"geektime/webook/internal/domain"
+	"geektime/webook/internal/errs"
 	"geektime/webook/internal/integration/startup"
 	"geektime/webook/internal/repository/dao"
 	jwt2 "geektime/webook/internal/web/jwt"
@@ -42,6 +43,9 @@ func (s *ArticleTestSuite) TearDownTest() {
 	//清空所有数据，并将自增主键恢复为1
 	s.db.Exec("truncate table articles")
 	s.db.Exec("truncate table publish_articles")
+	s.db.Exec("truncate table article_revisions")
+	s.db.Exec("truncate table article_tags")
+	s.db.Exec("truncate table publish_article_tags")
 }
 
 func (s *ArticleTestSuite) TestEdit() {
@@ -77,6 +81,7 @@ func (s *ArticleTestSuite) TestEdit() {
 					Content:  "我的内容",
 					Status:   domain.ArticleStatusUnpublished,
 					AuthorId: 123,
+					Version:  1,
 				}, art)
 			},
 			art: Article{
@@ -117,7 +122,22 @@ func (s *ArticleTestSuite) TestEdit() {
 					Status:   domain.ArticleStatusUnpublished,
 					Ctime:    123,
 					AuthorId: 123,
+					Version:  1,
 				}, art)
+				//每次保存都要留下历史版本
+				var rev dao.ArticleRevision
+				err = s.db.Where("article_id=?", 2).First(&rev).Error
+				assert.NoError(t, err)
+				assert.True(t, rev.Ctime > 0)
+				rev.Id = 0
+				rev.Ctime = 0
+				assert.Equal(t, dao.ArticleRevision{
+					ArticleId: 2,
+					Title:     "我的标题1233",
+					Content:   "我的内容1234",
+					Status:    domain.ArticleStatusUnpublished,
+					AuthorId:  123,
+				}, rev)
 			},
 			art: Article{
 				Id:      2,
@@ -170,8 +190,51 @@ func (s *ArticleTestSuite) TestEdit() {
 			},
 			wantCode: http.StatusOK,
 			wantRes: Result[int64]{
-				Code: 5,
-				Msg:  "系统错误",
+				Code: 4,
+				Msg:  "没有权限",
+			},
+		},
+		{
+			name: "另外一个页面已经保存过了",
+			before: func(t *testing.T) {
+				err := s.db.Create(dao.Article{
+					Id:       5,
+					Title:    "另外一个页面的标题",
+					Content:  "另外一个页面的内容",
+					AuthorId: 123,
+					Status:   domain.ArticleStatusUnpublished,
+					Version:  2,
+					Ctime:    123,
+					Utime:    234,
+				}).Error
+				assert.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				var art dao.Article
+				err := s.db.Where("id=?", 5).First(&art).Error
+				assert.NoError(t, err)
+				assert.Equal(t, dao.Article{
+					Id:       5,
+					Title:    "另外一个页面的标题",
+					Content:  "另外一个页面的内容",
+					AuthorId: 123,
+					Status:   domain.ArticleStatusUnpublished,
+					Version:  2,
+					Ctime:    123,
+					Utime:    234,
+				}, art)
+			},
+			art: Article{
+				Id:      5,
+				Title:   "我的标题",
+				Content: "我的内容",
+				Version: 1,
+			},
+			wantCode: http.StatusOK,
+			wantRes: Result[int64]{
+				Code: errs.ArticleVersionConflict,
+				Msg:  "帖子已经被修改过了",
+				Data: 2,
 			},
 		},
 	}
@@ -242,6 +305,7 @@ func (s *ArticleTestSuite) TestPublish() {
 					Content:  "随便试试",
 					Status:   domain.ArticleStatusPublished,
 					AuthorId: 123,
+					Version:  1,
 				}, art)
 				var publishedArt dao.PublishArticle
 				err = s.db.Where("author_id = ?", 123).First(&publishedArt).Error
@@ -254,10 +318,14 @@ func (s *ArticleTestSuite) TestPublish() {
 				publishedArt.Utime = 0
 				assert.Equal(t, dao.PublishArticle(
 					dao.Article{
-						Title:    "hello,你好",
-						Content:  "随便试试",
-						Status:   domain.ArticleStatusPublished,
-						AuthorId: 123,
+						Title:       "hello,你好",
+						Content:     "随便试试",
+						Status:      domain.ArticleStatusPublished,
+						AuthorId:    123,
+						HTML:        "<p>随便试试</p>\n",
+						Abstract:    "随便试试",
+						WordCnt:     4,
+						ReadingTime: 60,
 					},
 				), publishedArt)
 			},
@@ -302,6 +370,7 @@ func (s *ArticleTestSuite) TestPublish() {
 					Content:  "新的内容",
 					Status:   domain.ArticleStatusPublished,
 					AuthorId: 123,
+					Version:  1,
 				}, art)
 				var publishedArt dao.PublishArticle
 				err = s.db.Where("id = ?", 2).First(&publishedArt).Error
@@ -314,10 +383,14 @@ func (s *ArticleTestSuite) TestPublish() {
 				publishedArt.Utime = 0
 				assert.Equal(t, dao.PublishArticle(
 					dao.Article{
-						Title:    "新的标题",
-						Content:  "新的内容",
-						Status:   domain.ArticleStatusPublished,
-						AuthorId: 123,
+						Title:       "新的标题",
+						Content:     "新的内容",
+						Status:      domain.ArticleStatusPublished,
+						AuthorId:    123,
+						HTML:        "<p>新的内容</p>\n",
+						Abstract:    "新的内容",
+						WordCnt:     4,
+						ReadingTime: 60,
 					}), publishedArt)
 			},
 			req: Article{
@@ -327,7 +400,8 @@ func (s *ArticleTestSuite) TestPublish() {
 			},
 			wantCode: 200,
 			wantResult: Result[int64]{
-				Msg: "Ok",
+				Msg:  "Ok",
+				Data: 2,
 			},
 		},
 		{
@@ -364,6 +438,7 @@ func (s *ArticleTestSuite) TestPublish() {
 					Content:  "新的内容",
 					Status:   domain.ArticleStatusPublished,
 					AuthorId: 123,
+					Version:  1,
 				}, art)
 				var publishedArt dao.PublishArticle
 				err = s.db.Where("id = ?", 3).First(&publishedArt).Error
@@ -376,10 +451,14 @@ func (s *ArticleTestSuite) TestPublish() {
 				publishedArt.Utime = 0
 				assert.Equal(t, dao.PublishArticle(
 					dao.Article{
-						Title:    "新的标题",
-						Content:  "新的内容",
-						Status:   domain.ArticleStatusPublished,
-						AuthorId: 123,
+						Title:       "新的标题",
+						Content:     "新的内容",
+						Status:      domain.ArticleStatusPublished,
+						AuthorId:    123,
+						HTML:        "<p>新的内容</p>\n",
+						Abstract:    "新的内容",
+						WordCnt:     4,
+						ReadingTime: 60,
 					}), publishedArt)
 			},
 			req: Article{
@@ -389,7 +468,8 @@ func (s *ArticleTestSuite) TestPublish() {
 			},
 			wantCode: 200,
 			wantResult: Result[int64]{
-				Msg: "Ok",
+				Msg:  "Ok",
+				Data: 3,
 			},
 		},
 		{
@@ -459,8 +539,79 @@ func (s *ArticleTestSuite) TestPublish() {
 			},
 			wantCode: 200,
 			wantResult: Result[int64]{
-				Code: 5,
-				Msg:  "系统错误",
+				Code: 4,
+				Msg:  "没有权限",
+			},
+		},
+		{
+			name: "发表帖子，修改分类和标签",
+			before: func(t *testing.T) {
+				art := dao.Article{
+					Id:       5,
+					Title:    "我的标题",
+					Content:  "我的内容",
+					Category: "后端",
+					Ctime:    456,
+					Status:   domain.ArticleStatusPublished,
+					Utime:    234,
+					AuthorId: 123,
+				}
+				err := s.db.Create(&art).Error
+				assert.NoError(t, err)
+				part := dao.PublishArticle(art)
+				err = s.db.Create(&part).Error
+				assert.NoError(t, err)
+				err = s.db.Create(&dao.PublishArticleTag{ArticleId: 5, Tag: "旧标签", Ctime: 456}).Error
+				assert.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				var publishedArt dao.PublishArticle
+				err := s.db.Where("id = ?", 5).First(&publishedArt).Error
+				assert.NoError(t, err)
+				assert.Equal(t, "Go", publishedArt.Category)
+				// 标签去掉了空白和重复的，旧的标签被覆盖
+				var tags []string
+				err = s.db.Model(&dao.ArticleTag{}).Where("article_id = ?", 5).
+					Order("id ASC").Pluck("tag", &tags).Error
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"微服务", "gRPC"}, tags)
+				err = s.db.Model(&dao.PublishArticleTag{}).Where("article_id = ?", 5).
+					Order("id ASC").Pluck("tag", &tags).Error
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"微服务", "gRPC"}, tags)
+			},
+			req: Article{
+				Id:       5,
+				Title:    "新的标题",
+				Content:  "新的内容",
+				Category: " Go ",
+				Tags:     []string{"微服务", " gRPC", "微服务", ""},
+			},
+			wantCode: 200,
+			wantResult: Result[int64]{
+				Msg:  "Ok",
+				Data: 5,
+			},
+		},
+		{
+			name: "标签太多",
+			before: func(t *testing.T) {
+			},
+			after: func(t *testing.T) {
+				var cnt int64
+				err := s.db.Model(&dao.Article{}).Where("title = ?", "标签太多").Count(&cnt).Error
+				assert.NoError(t, err)
+				assert.Equal(t, int64(0), cnt)
+			},
+			req: Article{
+				Title:   "标签太多",
+				Content: "新的内容",
+				Tags:    []string{"a", "b", "c", "d", "e", "f"},
+			},
+			wantCode: 200,
+			wantResult: Result[int64]{
+				Code: 4,
+				Msg:  "标签或者分类不合法",
 			},
 		},
 	}
@@ -495,6 +646,13 @@ func (s *ArticleTestSuite) TestPublish() {
 	}
 }
 
+func (s *ArticleTestSuite) TestDAOContract() {
+	articleDAOContract{
+		dao:   dao.NewGROMArticleDAO(s.db),
+		reset: s.TearDownTest,
+	}.run(s.T())
+}
+
 func (s *ArticleTestSuite) TestABC() {
 	s.T().Log("hello, 这是测试套件")
 }
@@ -504,9 +662,12 @@ func TestArticle(t *testing.T) {
 }
 
 type Article struct {
-	Id      int64  `json:"id"`
-	Title   string `json:"title,omitempty"`
-	Content string `json:"content,omitempty"`
+	Id       int64    `json:"id"`
+	Title    string   `json:"title,omitempty"`
+	Content  string   `json:"content,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Version  int64    `json:"version"`
 }
 
 type Result[T any] struct {
@@ -25,6 +25,7 @@ type ArticleMongoDBTestSuite struct {
 	mdb     *mongo.Database
 	col     *mongo.Collection
 	liveCol *mongo.Collection
+	revCol  *mongo.Collection
 	server  *gin.Engine
 }
 
@@ -33,6 +34,7 @@ func (s *ArticleMongoDBTestSuite) SetupSuite() {
 	s.mdb = startup.InitMongoDB()
 	s.col = s.mdb.Collection("articles")
 	s.liveCol = s.mdb.Collection("published_articles")
+	s.revCol = s.mdb.Collection("article_revisions")
 	node, err := snowflake.NewNode(1)
 	assert.NoError(s.T(), err)
 	hdl := startup.InitArticleHandler(dao2.NewMongoDBArticleDAO(s.mdb, node))
@@ -56,12 +58,23 @@ func (s *ArticleMongoDBTestSuite) TearDownTest() {
 	assert.NoError(s.T(), err)
 	_, err = s.liveCol.DeleteMany(ctx, bson.D{})
 	assert.NoError(s.T(), err)
+	_, err = s.revCol.DeleteMany(ctx, bson.D{})
+	assert.NoError(s.T(), err)
 }
 
 func TestMongoArticle(t *testing.T) {
 	suite.Run(t, &ArticleMongoDBTestSuite{})
 }
 
+func (s *ArticleMongoDBTestSuite) TestDAOContract() {
+	node, err := snowflake.NewNode(2)
+	assert.NoError(s.T(), err)
+	articleDAOContract{
+		dao:   dao2.NewMongoDBArticleDAO(s.mdb, node),
+		reset: s.TearDownTest,
+	}.run(s.T())
+}
+
 func (s *ArticleMongoDBTestSuite) TestEdit() {
 	t := s.T()
 	testCases := []struct {
@@ -98,6 +111,7 @@ func (s *ArticleMongoDBTestSuite) TestEdit() {
 					Content:  "我的内容",
 					AuthorId: 123,
 					Status:   1,
+					Version:  1,
 				}, art)
 			},
 			art: Article{
@@ -145,8 +159,9 @@ func (s *ArticleMongoDBTestSuite) TestEdit() {
 					Content:  "新的内容",
 					AuthorId: 123,
 					// 更新之后，是未发表状态
-					Status: 1,
-					Ctime:  456,
+					Status:  1,
+					Version: 1,
+					Ctime:   456,
 				}, art)
 			},
 			art: Article{
@@ -203,7 +218,8 @@ func (s *ArticleMongoDBTestSuite) TestEdit() {
 			},
 			wantCode: http.StatusOK,
 			wantRes: Result[int64]{
-				Msg: "系统错误",
+				Code: 4,
+				Msg:  "没有权限",
 			},
 		},
 	}
@@ -283,6 +299,8 @@ func (s *ArticleMongoDBTestSuite) TestArticle_Publish() {
 				assert.NoError(t, err)
 				assert.Equal(t, "hello，你好", publishedArt.Title)
 				assert.Equal(t, "随便试试", publishedArt.Content)
+				assert.Equal(t, "<p>随便试试</p>\n", publishedArt.HTML)
+				assert.Equal(t, "随便试试", publishedArt.Abstract)
 				assert.Equal(t, int64(123), publishedArt.AuthorId)
 				assert.Equal(t, uint8(2), publishedArt.Status)
 				assert.True(t, publishedArt.Ctime > 0)
@@ -470,8 +488,8 @@ func (s *ArticleMongoDBTestSuite) TestArticle_Publish() {
 			},
 			wantCode: 200,
 			wantResult: Result[int64]{
-				Code: 5,
-				Msg:  "系统错误",
+				Code: 4,
+				Msg:  "没有权限",
 			},
 		},
 	}
@@ -0,0 +1,130 @@
+package integration
+
+import (
+	"context"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/integration/startup"
+	"geektime/webook/internal/repository/dao"
+	"geektime/webook/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+// JobTestSuite 用两个 CronJobService 模拟两个调度节点
+type JobTestSuite struct {
+	suite.Suite
+	db *gorm.DB
+}
+
+func (s *JobTestSuite) SetupSuite() {
+	s.db = startup.InitDB()
+}
+
+func (s *JobTestSuite) TearDownTest() {
+	s.db.Exec("truncate table jobs")
+	s.db.Exec("truncate table job_executions")
+}
+
+// TestPreemptDeadNode 节点 A 抢到之后挂了，节点 B 在租约过期之后接手
+func (s *JobTestSuite) TestPreemptDeadNode() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	nodeA := startup.InitJobService()
+	nodeB := startup.InitJobService()
+	s.insertWaitingJob(t, "dead_node")
+
+	jobA, err := nodeA.Preempt(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), jobA.Version)
+
+	// 租约还没过期，B 抢不到
+	_, err = nodeB.Preempt(ctx)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	// A 挂了，很久没有续约
+	s.expireLease(t, jobA.Id)
+	jobB, err := nodeB.Preempt(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, jobA.Id, jobB.Id)
+	assert.Equal(t, int64(2), jobB.Version)
+
+	// A 又活过来了，不能覆盖 B 的结果
+	err = nodeA.ResetNextTime(ctx, jobA)
+	assert.Equal(t, service.ErrJobPreempted, err)
+	jobA.CancelFunc()
+	j := s.getJob(t, jobA.Id)
+	assert.Equal(t, int(domain.JobStatusRunning), j.Status)
+	assert.Equal(t, 2, j.Version)
+	assert.True(t, j.NextTime < time.Now().UnixMilli())
+
+	// B 正常执行完
+	err = nodeB.ResetNextTime(ctx, jobB)
+	require.NoError(t, err)
+	jobB.CancelFunc()
+	j = s.getJob(t, jobA.Id)
+	assert.Equal(t, int(domain.JobStatusWaiting), j.Status)
+	assert.True(t, j.NextTime > time.Now().Add(time.Minute*59).UnixMilli())
+}
+
+// TestPreemptPausedJob 暂停了的 job 就算续约过期了也不会被抢
+func (s *JobTestSuite) TestPreemptPausedJob() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	nodeA := startup.InitJobService()
+	nodeB := startup.InitJobService()
+	s.insertWaitingJob(t, "paused")
+
+	jobA, err := nodeA.Preempt(ctx)
+	require.NoError(t, err)
+	err = nodeA.Pause(ctx, jobA.Id)
+	require.NoError(t, err)
+	s.expireLease(t, jobA.Id)
+
+	_, err = nodeB.Preempt(ctx)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	// 暂停不改版本号，A 执行完还能更新下一次的时间，但是不会把 job 放回去
+	err = nodeA.ResetNextTime(ctx, jobA)
+	require.NoError(t, err)
+	jobA.CancelFunc()
+	j := s.getJob(t, jobA.Id)
+	assert.Equal(t, int(domain.JobStatusPaused), j.Status)
+}
+
+func (s *JobTestSuite) insertWaitingJob(t *testing.T, name string) {
+	now := time.Now()
+	err := s.db.Create(&dao.Job{
+		Name:       name,
+		Executor:   "local",
+		Expression: "@every 1h",
+		Status:     int(domain.JobStatusWaiting),
+		NextTime:   now.Add(-time.Second).UnixMilli(),
+		Ctime:      now.UnixMilli(),
+		Utime:      now.UnixMilli(),
+	}).Error
+	require.NoError(t, err)
+}
+
+func (s *JobTestSuite) expireLease(t *testing.T, jid int64) {
+	utime := time.Now().Add(-startup.JobLease * 2).UnixMilli()
+	err := s.db.Model(&dao.Job{}).Where("id = ?", jid).
+		Update("utime", utime).Error
+	require.NoError(t, err)
+}
+
+func (s *JobTestSuite) getJob(t *testing.T, jid int64) dao.Job {
+	var j dao.Job
+	err := s.db.Where("id = ?", jid).First(&j).Error
+	require.NoError(t, err)
+	return j
+}
+
+func TestJob(t *testing.T) {
+	suite.Run(t, new(JobTestSuite))
+}
@@ -0,0 +1,23 @@
+package startup
+
+import (
+	commentv1 "geektime/webook/api/proto/gen/comment/v1"
+	"geektime/webook/comment/grpc"
+	"geektime/webook/comment/repository"
+	"geektime/webook/comment/repository/dao"
+	"geektime/webook/comment/service"
+	"geektime/webook/internal/client"
+	"geektime/webook/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// InitCommentClient 测试的时候评论服务和 webook 用同一个库
+func InitCommentClient(db *gorm.DB, l logger.LoggerV1) commentv1.CommentServiceClient {
+	err := dao.InitTables(db)
+	if err != nil {
+		panic(err)
+	}
+	repo := repository.NewCommentRepo(dao.NewCommentDAO(db), l)
+	svr := grpc.NewGrpcServer(service.NewCommentSvc(repo))
+	return client.NewCommentServiceAdapter(svr)
+}
@@ -0,0 +1,26 @@
+package startup
+
+import (
+	followv1 "geektime/webook/api/proto/gen/follow/v1"
+	"geektime/webook/follow/grpc"
+	"geektime/webook/follow/repository"
+	"geektime/webook/follow/repository/cache"
+	"geektime/webook/follow/repository/dao"
+	"geektime/webook/follow/service"
+	"geektime/webook/internal/client"
+	"geektime/webook/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// InitFollowClient 测试的时候直接调用本地的关注服务，用同一个数据库
+func InitFollowClient(db *gorm.DB, rdb redis.Cmdable, l logger.LoggerV1) followv1.FollowServiceClient {
+	err := dao.InitTables(db)
+	if err != nil {
+		panic(err)
+	}
+	repo := repository.NewFollowRelationRepository(dao.NewGORMFollowRelationDAO(db),
+		cache.NewRedisFollowCache(rdb), l)
+	svr := grpc.NewFollowRelationServiceServer(service.NewFollowRelationService(repo))
+	return client.NewFollowServiceAdapter(svr)
+}
@@ -0,0 +1,15 @@
+package startup
+
+import (
+	"geektime/webook/internal/repository"
+	"geektime/webook/internal/service"
+	"geektime/webook/pkg/logger"
+	"time"
+)
+
+// JobLease 测试期间不会自动续约，要模拟节点挂了直接把 utime 改到 JobLease 之前
+const JobLease = time.Minute
+
+func InitCronJobService(repo repository.CronJobRepository, l logger.LoggerV1) service.CronJobService {
+	return service.NewCronJobService(repo, l, JobLease)
+}
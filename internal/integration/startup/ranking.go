@@ -0,0 +1,23 @@
+package startup
+
+import (
+	"geektime/webook/internal/domain"
+	"time"
+)
+
+// InitRankingBoards 测试用固定的榜单，不依赖配置文件
+func InitRankingBoards() []domain.RankingBoard {
+	return []domain.RankingBoard{
+		{
+			Name:    "daily",
+			Period:  time.Hour * 24,
+			N:       100,
+			Formula: domain.RankingFormula{Like: 1, Read: 0.1, Collect: 2, Comment: 1.5, Gravity: 1.5},
+		},
+		{
+			Name:    "all_time",
+			N:       100,
+			Formula: domain.RankingFormula{Like: 1, Read: 0.05, Collect: 3, Comment: 1},
+		},
+	}
+}
@@ -0,0 +1,15 @@
+package startup
+
+import (
+	searchv1 "geektime/webook/api/proto/gen/search/v1"
+	"geektime/webook/internal/client"
+	"geektime/webook/search/repository"
+	"geektime/webook/search/repository/dao"
+	"geektime/webook/search/service"
+)
+
+// InitSearchClient 测试用内嵌的倒排索引，不需要启动搜索服务
+func InitSearchClient() searchv1.SearchServiceClient {
+	svc := service.NewSearchService(repository.NewArticleRepository(dao.NewInvertedIndexArticleDAO()))
+	return client.NewSearchServiceAdapter(svc)
+}
@@ -21,7 +21,7 @@ var thirdPartySet = wire.NewSet( // 第三方依赖
 	InitLogger)
 
 var jobProviderSet = wire.NewSet(
-	service.NewCronJobService,
+	InitCronJobService,
 	repository.NewPreemptJobRepository,
 	dao.NewGORMJobDAO)
 
@@ -35,8 +35,24 @@ var articlSvcProvider = wire.NewSet(
 	repository.NewArticleRepository,
 	cache.NewArticleRedisCache,
 	dao.NewGROMArticleDAO,
+	dao.NewGORMArticleCoAuthorDAO,
+	dao.NewGORMArticleSeriesDAO,
+	antiAbuseSvcProvider,
 	service.NewArticleService)
 
+var rankingSvcProvider = wire.NewSet(
+	cache.NewRankingRedisCache,
+	repository.NewCachedRankingRepository,
+	InitRankingBoards,
+	InitCommentClient,
+	service.NewBatchRankingService)
+
+var antiAbuseSvcProvider = wire.NewSet(
+	dao.NewGORMAbuseAuditDAO,
+	cache.NewAntiAbuseRedisCache,
+	repository.NewAntiAbuseRepository,
+	ioc.InitAntiAbuseService)
+
 func InitArticleHandler(dao dao.ArticleDAO) *web.ArticleHandler {
 	wire.Build(
 		thirdPartySet,
@@ -44,11 +60,20 @@ func InitArticleHandler(dao dao.ArticleDAO) *web.ArticleHandler {
 		article.NewKafkaProducer,
 		repository.NewArticleRepository,
 		cache.NewArticleRedisCache,
+		dao.NewGORMArticleCoAuthorDAO,
+		dao.NewGORMArticleSeriesDAO,
+		antiAbuseSvcProvider,
 		service.NewArticleService,
 		web.NewArticleHandler)
 	return &web.ArticleHandler{}
 }
 
+// InitJobService 每调用一次就相当于一个调度节点
+func InitJobService() service.CronJobService {
+	wire.Build(InitDB, InitLogger, jobProviderSet)
+	return nil
+}
+
 func InitWebServer() *gin.Engine {
 	wire.Build(
 		thirdPartySet,
@@ -70,6 +95,14 @@ func InitWebServer() *gin.Engine {
 		web.NewUserHandler,
 		web.NewArticleHandler,
 		web.NewOAuth2WechatHandler,
+		InitSearchClient,
+		web.NewSearchHandler,
+		InitFollowClient,
+		web.NewAuthorHandler,
+		web.NewHistoryHandler,
+		web.NewCollectionHandler,
+		rankingSvcProvider,
+		web.NewRankingHandler,
 		jwt.NewRedisJWTHandler,
 		ioc.InitMiddlewares,
 		ioc.InitWebServer,
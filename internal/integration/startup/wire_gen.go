@@ -7,10 +7,13 @@
 package startup
 
 import (
+	"geektime/webook/interactive/events"
+	ioc2 "geektime/webook/interactive/ioc"
 	repository2 "geektime/webook/interactive/repository"
 	cache2 "geektime/webook/interactive/repository/cache"
 	dao3 "geektime/webook/interactive/repository/dao"
 	service2 "geektime/webook/interactive/service"
+	client2 "geektime/webook/internal/client"
 	"geektime/webook/internal/events/article"
 	"geektime/webook/internal/repository"
 	"geektime/webook/internal/repository/cache"
@@ -31,16 +34,28 @@ func InitArticleHandler(dao2 dao.ArticleDAO) *web.ArticleHandler {
 	loggerV1 := InitLogger()
 	db := InitDB()
 	userDAO := dao.NewUserDao(db)
-	articleRepository := repository.NewArticleRepository(dao2, articleCache, loggerV1, userDAO)
+	articleCoAuthorDAO := dao.NewGORMArticleCoAuthorDAO(db)
+	articleSeriesDAO := dao.NewGORMArticleSeriesDAO(db)
+	articleRepository := repository.NewArticleRepository(dao2, articleCache, loggerV1, userDAO, articleCoAuthorDAO, articleSeriesDAO)
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewKafkaProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, loggerV1, producer)
+	abuseAuditDAO := dao.NewGORMAbuseAuditDAO(db)
+	antiAbuseCache := cache.NewAntiAbuseRedisCache(cmdable)
+	antiAbuseRepository := repository.NewAntiAbuseRepository(abuseAuditDAO, antiAbuseCache)
+	antiAbuseService := ioc.InitAntiAbuseService(antiAbuseRepository, cmdable, loggerV1)
+	articleService := service.NewArticleService(articleRepository, loggerV1, producer, antiAbuseService)
 	interactiveDAO := dao3.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
-	interactiveService := service2.NewInteractiveService(interactiveRepository)
-	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService)
+	historyRecordDAO := dao3.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository2.NewHistoryRecordRepository(historyRecordDAO)
+	collectionDAO := dao3.NewGORMCollectionDAO(db)
+	collectionRepository := repository2.NewCollectionRepository(collectionDAO, interactiveCache, loggerV1)
+	bizRegistry := ioc2.InitBizRegistry()
+	changeProducer := events.NewSaramaChangeProducer(syncProducer)
+	interactiveService := service2.NewInteractiveService(interactiveRepository, historyRecordRepository, collectionRepository, bizRegistry, changeProducer, loggerV1)
+	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService, antiAbuseService)
 	return articleHandler
 }
 
@@ -51,10 +66,28 @@ func InitInteractiveService() service2.InteractiveService {
 	cmdable := InitRedis()
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
-	interactiveService := service2.NewInteractiveService(interactiveRepository)
+	historyRecordDAO := dao3.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository2.NewHistoryRecordRepository(historyRecordDAO)
+	collectionDAO := dao3.NewGORMCollectionDAO(db)
+	collectionRepository := repository2.NewCollectionRepository(collectionDAO, interactiveCache, loggerV1)
+	bizRegistry := ioc2.InitBizRegistry()
+	client := InitSaramaClient()
+	syncProducer := InitSyncProducer(client)
+	changeProducer := events.NewSaramaChangeProducer(syncProducer)
+	interactiveService := service2.NewInteractiveService(interactiveRepository, historyRecordRepository, collectionRepository, bizRegistry, changeProducer, loggerV1)
 	return interactiveService
 }
 
+// InitJobService 每调用一次就相当于一个调度节点
+func InitJobService() service.CronJobService {
+	db := InitDB()
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	loggerV1 := InitLogger()
+	cronJobService := InitCronJobService(cronJobRepository, loggerV1)
+	return cronJobService
+}
+
 func InitWebServer() *gin.Engine {
 	cmdable := InitRedis()
 	jwtHandler := jwt.NewRedisJWTHandler(cmdable)
@@ -74,17 +107,42 @@ func InitWebServer() *gin.Engine {
 	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, userService, jwtHandler)
 	articleDAO := dao.NewGROMArticleDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewArticleRepository(articleDAO, articleCache, loggerV1, userDAO)
+	articleCoAuthorDAO := dao.NewGORMArticleCoAuthorDAO(db)
+	articleSeriesDAO := dao.NewGORMArticleSeriesDAO(db)
+	articleRepository := repository.NewArticleRepository(articleDAO, articleCache, loggerV1, userDAO, articleCoAuthorDAO, articleSeriesDAO)
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewKafkaProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, loggerV1, producer)
+	abuseAuditDAO := dao.NewGORMAbuseAuditDAO(db)
+	antiAbuseCache := cache.NewAntiAbuseRedisCache(cmdable)
+	antiAbuseRepository := repository.NewAntiAbuseRepository(abuseAuditDAO, antiAbuseCache)
+	antiAbuseService := ioc.InitAntiAbuseService(antiAbuseRepository, cmdable, loggerV1)
+	articleService := service.NewArticleService(articleRepository, loggerV1, producer, antiAbuseService)
 	interactiveDAO := dao3.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
-	interactiveService := service2.NewInteractiveService(interactiveRepository)
-	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService)
-	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler)
+	historyRecordDAO := dao3.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository2.NewHistoryRecordRepository(historyRecordDAO)
+	collectionDAO := dao3.NewGORMCollectionDAO(db)
+	collectionRepository := repository2.NewCollectionRepository(collectionDAO, interactiveCache, loggerV1)
+	bizRegistry := ioc2.InitBizRegistry()
+	changeProducer := events.NewSaramaChangeProducer(syncProducer)
+	interactiveService := service2.NewInteractiveService(interactiveRepository, historyRecordRepository, collectionRepository, bizRegistry, changeProducer, loggerV1)
+	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService, antiAbuseService)
+	searchServiceClient := InitSearchClient()
+	searchHandler := web.NewSearchHandler(searchServiceClient, loggerV1)
+	followServiceClient := InitFollowClient(db, cmdable, loggerV1)
+	authorHandler := web.NewAuthorHandler(articleService, followServiceClient, loggerV1)
+	interactiveServiceAdapter := client2.NewInteractiveServiceAdapter(interactiveService)
+	historyHandler := web.NewHistoryHandler(interactiveServiceAdapter, articleService, loggerV1)
+	collectionHandler := web.NewCollectionHandler(interactiveServiceAdapter, articleService, loggerV1)
+	rankingCache := cache.NewRankingRedisCache(cmdable)
+	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
+	commentServiceClient := InitCommentClient(db, loggerV1)
+	v2 := InitRankingBoards()
+	rankingService := service.NewBatchRankingService(rankingRepository, articleService, interactiveServiceAdapter, commentServiceClient, v2)
+	rankingHandler := web.NewRankingHandler(rankingService, loggerV1)
+	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler, searchHandler, authorHandler, historyHandler, collectionHandler, rankingHandler)
 	return engine
 }
 
@@ -97,10 +155,14 @@ var thirdPartySet = wire.NewSet(
 	InitSyncProducer,
 	InitLogger)
 
-var jobProviderSet = wire.NewSet(service.NewCronJobService, repository.NewPreemptJobRepository, dao.NewGORMJobDAO)
+var jobProviderSet = wire.NewSet(InitCronJobService, repository.NewPreemptJobRepository, dao.NewGORMJobDAO)
 
 var userSvcProvider = wire.NewSet(dao.NewUserDao, cache.NewUserCache, repository.NewUserRepository, service.NewUserService)
 
-var articlSvcProvider = wire.NewSet(repository.NewArticleRepository, cache.NewArticleRedisCache, dao.NewGROMArticleDAO, service.NewArticleService)
+var articlSvcProvider = wire.NewSet(repository.NewArticleRepository, cache.NewArticleRedisCache, dao.NewGROMArticleDAO, dao.NewGORMArticleCoAuthorDAO, dao.NewGORMArticleSeriesDAO, antiAbuseSvcProvider, service.NewArticleService)
+
+var rankingSvcProvider = wire.NewSet(cache.NewRankingRedisCache, repository.NewCachedRankingRepository, InitRankingBoards, InitCommentClient, service.NewBatchRankingService)
+
+var antiAbuseSvcProvider = wire.NewSet(dao.NewGORMAbuseAuditDAO, cache.NewAntiAbuseRedisCache, repository.NewAntiAbuseRepository, ioc.InitAntiAbuseService)
 
-var interactiveSvcSet = wire.NewSet(dao3.NewGORMInteractiveDAO, cache2.NewInteractiveRedisCache, repository2.NewCachedInteractiveRepository, service2.NewInteractiveService)
+var interactiveSvcSet = wire.NewSet(dao3.NewGORMInteractiveDAO, cache2.NewInteractiveRedisCache, repository2.NewCachedInteractiveRepository, dao3.NewGORMHistoryRecordDAO, repository2.NewHistoryRecordRepository, dao3.NewGORMCollectionDAO, repository2.NewCollectionRepository, ioc2.InitBizRegistry, events.NewSaramaChangeProducer, service2.NewInteractiveService)
@@ -0,0 +1,57 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	"geektime/webook/pkg/logger"
+	"time"
+)
+
+// ScheduledPublishExecutor 定时发表帖子的执行器
+// 对应的 job 由 Scheduler 抢占之后执行，所以同一时刻只会有一个节点在发表
+// 定时发表的状态都在制作库里面，重启之后不会丢
+type ScheduledPublishExecutor struct {
+	svc       service.ArticleService
+	l         logger.LoggerV1
+	batchSize int
+}
+
+func NewScheduledPublishExecutor(svc service.ArticleService, l logger.LoggerV1) *ScheduledPublishExecutor {
+	return &ScheduledPublishExecutor{
+		svc:       svc,
+		l:         l,
+		batchSize: 100,
+	}
+}
+
+func (e *ScheduledPublishExecutor) Name() string {
+	return "scheduled_publish"
+}
+
+func (e *ScheduledPublishExecutor) Exec(ctx context.Context, j domain.Job) error {
+	for {
+		arts, err := e.svc.ListDueScheduled(ctx, time.Now(), e.batchSize)
+		if err != nil {
+			return err
+		}
+		failed := 0
+		for _, art := range arts {
+			err = e.svc.PublishScheduled(ctx, art)
+			// 作者刚好取消了定时发表，或者又修改了内容，跳过就可以
+			if err == nil || errors.Is(err, service.ErrArticleNotScheduled) {
+				continue
+			}
+			failed++
+			e.l.Error("定时发表帖子失败",
+				logger.Int64("jid", j.Id),
+				logger.Int64("aid", art.Id),
+				logger.Error(err))
+		}
+		// 没有下一批了，或者有失败的，失败的留到下一次调度再发表
+		if len(arts) < e.batchSize || failed > 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	svcmocks "geektime/webook/internal/service/mocks"
+	"geektime/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestScheduledPublishExecutor_Exec(t *testing.T) {
+	arts := func(ids ...int64) []domain.Article {
+		res := make([]domain.Article, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, domain.Article{Id: id})
+		}
+		return res
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.ArticleService
+
+		wantErr error
+	}{
+		{
+			name: "一批不满就结束",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().ListDueScheduled(gomock.Any(), gomock.Any(), 2).
+					Return(arts(1), nil)
+				svc.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 1}).Return(nil)
+				return svc
+			},
+		},
+		{
+			name: "一批满了继续取下一批",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				gomock.InOrder(
+					svc.EXPECT().ListDueScheduled(gomock.Any(), gomock.Any(), 2).
+						Return(arts(1, 2), nil),
+					svc.EXPECT().ListDueScheduled(gomock.Any(), gomock.Any(), 2).
+						Return(arts(3, 4), nil),
+					svc.EXPECT().ListDueScheduled(gomock.Any(), gomock.Any(), 2).
+						Return(nil, nil),
+				)
+				svc.EXPECT().PublishScheduled(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+				return svc
+			},
+		},
+		{
+			name: "跳过已经取消定时发表的",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				gomock.InOrder(
+					svc.EXPECT().ListDueScheduled(gomock.Any(), gomock.Any(), 2).
+						Return(arts(1, 2), nil),
+					svc.EXPECT().ListDueScheduled(gomock.Any(), gomock.Any(), 2).
+						Return(arts(3), nil),
+				)
+				svc.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 1}).
+					Return(service.ErrArticleNotScheduled)
+				svc.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 2}).Return(nil)
+				svc.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 3}).Return(nil)
+				return svc
+			},
+		},
+		{
+			// 失败的帖子还会被查出来，继续取下一批会一直循环
+			name: "有失败的，这一批发完就停下",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().ListDueScheduled(gomock.Any(), gomock.Any(), 2).
+					Return(arts(1, 2), nil)
+				svc.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 1}).
+					Return(errors.New("mock db error"))
+				svc.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 2}).Return(nil)
+				return svc
+			},
+		},
+		{
+			name: "查询失败",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().ListDueScheduled(gomock.Any(), gomock.Any(), 2).
+					Return(nil, errors.New("mock db error"))
+				return svc
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			exec := NewScheduledPublishExecutor(tc.mock(ctrl), logger.NewNopLogger())
+			exec.batchSize = 2
+			err := exec.Exec(context.Background(), domain.Job{Id: 1})
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	"geektime/webook/pkg/logger"
+	"time"
+)
+
+// TrashPurgeExecutor 彻底删除回收站里面放了太久的帖子
+// 保留多少天放在 job 的 Cfg 里面，例如 {"retentionDays":30}
+type TrashPurgeExecutor struct {
+	svc       service.ArticleService
+	l         logger.LoggerV1
+	batchSize int
+	// retention 没有配置的时候的保留时间
+	retention time.Duration
+}
+
+func NewTrashPurgeExecutor(svc service.ArticleService, l logger.LoggerV1) *TrashPurgeExecutor {
+	return &TrashPurgeExecutor{
+		svc:       svc,
+		l:         l,
+		batchSize: 100,
+		retention: time.Hour * 24 * 30,
+	}
+}
+
+func (e *TrashPurgeExecutor) Name() string {
+	return "trash_purge"
+}
+
+type trashPurgeCfg struct {
+	RetentionDays int `json:"retentionDays"`
+}
+
+func (e *TrashPurgeExecutor) Exec(ctx context.Context, j domain.Job) error {
+	retention := e.retention
+	if j.Cfg != "" {
+		var cfg trashPurgeCfg
+		err := json.Unmarshal([]byte(j.Cfg), &cfg)
+		if err != nil {
+			return err
+		}
+		if cfg.RetentionDays > 0 {
+			retention = time.Hour * 24 * time.Duration(cfg.RetentionDays)
+		}
+	}
+	before := time.Now().Add(-retention)
+	for {
+		arts, err := e.svc.ListExpiredTrash(ctx, before, e.batchSize)
+		if err != nil {
+			return err
+		}
+		failed := 0
+		for _, art := range arts {
+			err = e.svc.Purge(ctx, art)
+			// 作者刚好恢复了，跳过就可以
+			if err == nil || errors.Is(err, service.ErrArticleNotInTrash) {
+				continue
+			}
+			failed++
+			e.l.Error("彻底删除帖子失败",
+				logger.Int64("jid", j.Id),
+				logger.Int64("aid", art.Id),
+				logger.Error(err))
+		}
+		// 失败的留到下一次调度，避免一直查出同一批
+		if len(arts) < e.batchSize || failed > 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	jobv1 "geektime/webook/api/proto/gen/job/v1"
+	"geektime/webook/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"sync"
+	"time"
+)
+
+// GRPCExecutor 调用业务方实现的 JobExecutorService，业务方通过 etcd 注册
+// job 的 Cfg 里面指定服务名，例如 {"service":"payment","timeout":5000}，
+// Cfg 会原样传给业务方
+type GRPCExecutor struct {
+	// resolver 从 etcd 里面找到业务方的节点
+	resolver resolver.Builder
+	// timeout Cfg 里面没有配置的时候的默认值
+	timeout time.Duration
+
+	mu sync.RWMutex
+	// clients 每个服务一个连接，第一次调度到的时候才建立
+	clients map[string]jobv1.JobExecutorServiceClient
+}
+
+func NewGRPCExecutor(resolver resolver.Builder, timeout time.Duration) *GRPCExecutor {
+	return &GRPCExecutor{
+		resolver: resolver,
+		timeout:  timeout,
+		clients:  map[string]jobv1.JobExecutorServiceClient{},
+	}
+}
+
+func (e *GRPCExecutor) Name() string {
+	return "grpc"
+}
+
+type grpcJobCfg struct {
+	Service string `json:"service"`
+	// Timeout 单位毫秒
+	Timeout int64 `json:"timeout"`
+}
+
+func (e *GRPCExecutor) Exec(ctx context.Context, j domain.Job) error {
+	var cfg grpcJobCfg
+	err := json.Unmarshal([]byte(j.Cfg), &cfg)
+	if err != nil {
+		return fmt.Errorf("解析 gRPC job 的配置失败 %w", err)
+	}
+	if cfg.Service == "" {
+		return errors.New("gRPC job 没有配置 service")
+	}
+	client, err := e.client(cfg.Service)
+	if err != nil {
+		return err
+	}
+	timeout := e.timeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err = client.Execute(ctx, &jobv1.ExecuteRequest{
+		Jid:  j.Id,
+		Name: j.Name,
+		Cfg:  j.Cfg,
+	})
+	return err
+}
+
+func (e *GRPCExecutor) client(service string) (jobv1.JobExecutorServiceClient, error) {
+	e.mu.RLock()
+	client, ok := e.clients[service]
+	e.mu.RUnlock()
+	if ok {
+		return client, nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	client, ok = e.clients[service]
+	if ok {
+		return client, nil
+	}
+	// 和 grpcx.Server 注册的 key 保持一致
+	cc, err := grpc.Dial("etcd:///service/"+service,
+		grpc.WithResolvers(e.resolver),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	client = jobv1.NewJobExecutorServiceClient(cc)
+	e.clients[service] = client
+	return client, nil
+}
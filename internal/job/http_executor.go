@@ -0,0 +1,178 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"geektime/webook/internal/domain"
+	"geektime/webook/pkg/logger"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPExecutor 把 job POST 给业务方的 HTTP 接口执行，返回 2xx 就算成功
+// job 的 Cfg 里面指定地址，例如 {"url":"http://payment:8070/jobs/sync","timeout":5000,"retries":2}
+// 请求体是 {"jid":1,"name":"xxx","cfg":"<job 的 Cfg>"}，配置了 secret 的话会带上签名：
+// X-Job-Signature = hex(HMAC-SHA256(secret, X-Job-Timestamp + "." + 请求体))
+// 地址只能是 hosts 里面的，避免有人通过管理后台让调度节点去访问内网的任意地址
+type HTTPExecutor struct {
+	client *http.Client
+	secret []byte
+	// hosts 允许访问的主机，可以只写主机名，也可以带上端口，例如 payment 或者 payment:8070
+	hosts map[string]struct{}
+	// timeout 和 retries 是 Cfg 里面没有配置的时候的默认值
+	timeout       time.Duration
+	retries       int
+	retryInterval time.Duration
+	l             logger.LoggerV1
+}
+
+func NewHTTPExecutor(client *http.Client, secret string, hosts []string,
+	timeout time.Duration, retries int, l logger.LoggerV1) *HTTPExecutor {
+	allowed := make(map[string]struct{}, len(hosts))
+	for _, h := range hosts {
+		allowed[strings.ToLower(h)] = struct{}{}
+	}
+	return &HTTPExecutor{
+		client:        client,
+		secret:        []byte(secret),
+		hosts:         allowed,
+		timeout:       timeout,
+		retries:       retries,
+		retryInterval: time.Second,
+		l:             l,
+	}
+}
+
+func (e *HTTPExecutor) Name() string {
+	return "http"
+}
+
+type httpJobCfg struct {
+	URL string `json:"url"`
+	// Timeout 单次请求的超时，单位毫秒
+	Timeout int64 `json:"timeout"`
+	// Retries 失败之后最多重试几次，不填用默认值
+	Retries *int `json:"retries"`
+}
+
+type httpJobReq struct {
+	Jid  int64  `json:"jid"`
+	Name string `json:"name"`
+	Cfg  string `json:"cfg"`
+}
+
+var (
+	// errHTTPJobRejected 业务方明确拒绝了，重试也没有用
+	errHTTPJobRejected = errors.New("业务方拒绝执行 job")
+	// ErrHTTPJobHostNotAllowed job 配置的地址不在白名单里面
+	ErrHTTPJobHostNotAllowed = errors.New("HTTP job 的地址不在白名单里面")
+)
+
+func (e *HTTPExecutor) Exec(ctx context.Context, j domain.Job) error {
+	var cfg httpJobCfg
+	err := json.Unmarshal([]byte(j.Cfg), &cfg)
+	if err != nil {
+		return fmt.Errorf("解析 HTTP job 的配置失败 %w", err)
+	}
+	if cfg.URL == "" {
+		return errors.New("HTTP job 没有配置 url")
+	}
+	err = e.checkURL(cfg.URL)
+	if err != nil {
+		return err
+	}
+	timeout := e.timeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Millisecond
+	}
+	retries := e.retries
+	if cfg.Retries != nil {
+		retries = *cfg.Retries
+	}
+	body, err := json.Marshal(httpJobReq{Jid: j.Id, Name: j.Name, Cfg: j.Cfg})
+	if err != nil {
+		return err
+	}
+	for i := 0; ; i++ {
+		err = e.post(ctx, cfg.URL, body, timeout)
+		if err == nil || errors.Is(err, errHTTPJobRejected) || i >= retries {
+			return err
+		}
+		e.l.Warn("HTTP job 执行失败，准备重试",
+			logger.Int64("jid", j.Id),
+			logger.Int("retry", i+1),
+			logger.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(e.retryInterval * time.Duration(i+1)):
+		}
+	}
+}
+
+func (e *HTTPExecutor) post(ctx context.Context, addr string, body []byte, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(e.secret) > 0 {
+		ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		req.Header.Set("X-Job-Timestamp", ts)
+		req.Header.Set("X-Job-Signature", e.sign(ts, body))
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	// 错误信息只保留前面一段，执行记录里面够排查就可以
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("HTTP 状态码 %d %s", resp.StatusCode, msg)
+	// 5xx 和限流可以重试，别的 4xx 是请求本身有问题
+	if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w %w", errHTTPJobRejected, err)
+	}
+	return err
+}
+
+// checkURL 只允许 http 和 https，主机要在白名单里面
+func (e *HTTPExecutor) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("HTTP job 的地址不合法 %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("HTTP job 不支持的协议 %s", u.Scheme)
+	}
+	if _, ok := e.hosts[strings.ToLower(u.Host)]; ok {
+		return nil
+	}
+	if _, ok := e.hosts[strings.ToLower(u.Hostname())]; ok {
+		return nil
+	}
+	return fmt.Errorf("%w %s", ErrHTTPJobHostNotAllowed, u.Host)
+}
+
+func (e *HTTPExecutor) sign(ts string, body []byte) string {
+	h := hmac.New(sha256.New, e.secret)
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,180 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"geektime/webook/internal/domain"
+	"geektime/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestHTTPExecutor_Exec(t *testing.T) {
+	testCases := []struct {
+		name string
+		// 每次请求返回的状态码，超出的部分返回最后一个
+		codes []int
+		cfg   string
+
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "成功",
+			codes:     []int{http.StatusOK},
+			wantCalls: 1,
+		},
+		{
+			name:      "5xx 重试之后成功",
+			codes:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantCalls: 3,
+		},
+		{
+			name:      "重试次数用完",
+			codes:     []int{http.StatusInternalServerError},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "Cfg 里面关掉重试",
+			codes:     []int{http.StatusInternalServerError},
+			cfg:       `,"retries":0`,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "4xx 不重试",
+			codes:     []int{http.StatusBadRequest},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				var req httpJobReq
+				require.NoError(t, json.Unmarshal(body, &req))
+				assert.Equal(t, int64(1), req.Jid)
+				assert.Equal(t, "sync", req.Name)
+				code := tc.codes[len(tc.codes)-1]
+				if calls < len(tc.codes) {
+					code = tc.codes[calls]
+				}
+				calls++
+				w.WriteHeader(code)
+			}))
+			defer server.Close()
+
+			exec := NewHTTPExecutor(server.Client(), "", []string{"127.0.0.1"}, time.Second, 2, logger.NewNopLogger())
+			exec.retryInterval = time.Millisecond
+			err := exec.Exec(context.Background(), domain.Job{
+				Id:   1,
+				Name: "sync",
+				Cfg:  `{"url":"` + server.URL + `"` + tc.cfg + `}`,
+			})
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func TestHTTPExecutor_Sign(t *testing.T) {
+	var ts, sig string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts = r.Header.Get("X-Job-Timestamp")
+		sig = r.Header.Get("X-Job-Signature")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	exec := NewHTTPExecutor(server.Client(), "secret", []string{"127.0.0.1"}, time.Second, 0, logger.NewNopLogger())
+	err := exec.Exec(context.Background(), domain.Job{
+		Id:  1,
+		Cfg: `{"url":"` + server.URL + `"}`,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, ts)
+	assert.Equal(t, exec.sign(ts, body), sig)
+	// 换一个密钥签出来不一样
+	other := NewHTTPExecutor(server.Client(), "other", []string{"127.0.0.1"}, time.Second, 0, logger.NewNopLogger())
+	assert.NotEqual(t, other.sign(ts, body), sig)
+}
+
+func TestHTTPExecutor_Hosts(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		hosts []string
+		url   string
+
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "只配置主机名",
+			hosts:     []string{"127.0.0.1"},
+			url:       server.URL,
+			wantCalls: 1,
+		},
+		{
+			name:      "带端口",
+			hosts:     []string{u.Host},
+			url:       server.URL,
+			wantCalls: 1,
+		},
+		{
+			name:    "端口不一样",
+			hosts:   []string{"127.0.0.1:1"},
+			url:     server.URL,
+			wantErr: ErrHTTPJobHostNotAllowed,
+		},
+		{
+			name:    "不在白名单里面",
+			hosts:   []string{"payment"},
+			url:     server.URL,
+			wantErr: ErrHTTPJobHostNotAllowed,
+		},
+		{
+			name:    "没有配置白名单",
+			url:     server.URL,
+			wantErr: ErrHTTPJobHostNotAllowed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = 0
+			exec := NewHTTPExecutor(server.Client(), "", tc.hosts, time.Second, 0, logger.NewNopLogger())
+			err := exec.Exec(context.Background(), domain.Job{
+				Id:  1,
+				Cfg: `{"url":"` + tc.url + `"}`,
+			})
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+
+	// 不是 http 的地址直接拒绝
+	exec := NewHTTPExecutor(server.Client(), "", []string{"127.0.0.1"}, time.Second, 0, logger.NewNopLogger())
+	err = exec.Exec(context.Background(), domain.Job{
+		Id:  1,
+		Cfg: `{"url":"file:///etc/passwd"}`,
+	})
+	assert.Error(t, err)
+}
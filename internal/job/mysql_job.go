@@ -7,6 +7,7 @@ import (
 	"geektime/webook/internal/service"
 	"geektime/webook/pkg/logger"
 	"golang.org/x/sync/semaphore"
+	"os"
 	"time"
 )
 
@@ -45,6 +46,8 @@ func (l *LocalFuncExecutor) Exec(ctx context.Context, j domain.Job) error {
 // Scheduler 调度器
 type Scheduler struct {
 	dbTimeout time.Duration
+	// 没有抢占到 job 的时候，等待多久再抢占
+	interval time.Duration
 
 	svc service.CronJobService
 
@@ -52,12 +55,20 @@ type Scheduler struct {
 	l         logger.LoggerV1
 
 	limiter *semaphore.Weighted
+	// node 记录在执行记录里面，用来排查是哪个节点执行的
+	node string
 }
 
 func NewScheduler(svc service.CronJobService, l logger.LoggerV1) *Scheduler {
+	node, err := os.Hostname()
+	if err != nil {
+		node = "unknown"
+	}
 	return &Scheduler{
+		node:      node,
 		svc:       svc,
 		dbTimeout: time.Second,
+		interval:  time.Second,
 		limiter:   semaphore.NewWeighted(100),
 		l:         l,
 		executors: map[string]Executor{},
@@ -68,6 +79,12 @@ func (s *Scheduler) RegisterExecutor(exec Executor) {
 	s.executors[exec.Name()] = exec
 }
 
+// HasExecutor 执行器都是启动的时候注册的，之后只读，不用加锁
+func (s *Scheduler) HasExecutor(name string) bool {
+	_, ok := s.executors[name]
+	return ok
+}
+
 func (s *Scheduler) Schedule(ctx context.Context) error {
 	for {
 		// 放弃调度了
@@ -82,8 +99,10 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		j, err := s.svc.Preempt(dbCtx)
 		cancel()
 		if err != nil {
-			// 有 Error
-			// 最简单的做法就是直接下一轮，也可以睡一段时间
+			// 有 Error，一般是没有可以抢占的 job
+			// 睡一段时间再进入下一轮，避免空转
+			s.limiter.Release(1)
+			time.Sleep(s.interval)
 			continue
 		}
 
@@ -94,6 +113,20 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 			s.l.Error("找不到执行器",
 				logger.Int64("jid", j.Id),
 				logger.String("executor", j.Executor))
+			// 推迟到下一次调度的时间再释放，不然释放之后马上又会被抢占，所有节点都会空转
+			dbCtx, cancel = context.WithTimeout(ctx, s.dbTimeout)
+			err = s.svc.ResetNextTime(dbCtx, j)
+			cancel()
+			if err != nil {
+				s.l.Error("重置下次执行时间失败",
+					logger.Int64("jid", j.Id),
+					logger.Error(err))
+			}
+			s.limiter.Release(1)
+			j.CancelFunc()
+			if err != nil {
+				time.Sleep(s.interval)
+			}
 			continue
 		}
 
@@ -103,12 +136,26 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 				// 这边要释放掉
 				j.CancelFunc()
 			}()
-			err1 := exec.Exec(ctx, j)
+			// 放弃调度，或者续约发现 job 已经被别的节点抢走了，都要停止执行
+			execCtx, cancelExec := context.WithCancel(ctx)
+			defer cancelExec()
+			stop := context.AfterFunc(j.Ctx, cancelExec)
+			defer stop()
+			eid := s.startExecution(ctx, j)
+			err1 := exec.Exec(execCtx, j)
+			s.finishExecution(j, eid, err1)
+			if j.Ctx.Err() != nil {
+				// 下一次调度的时间由新的持有者来更新
+				s.l.Warn("任务已经被别的节点抢占",
+					logger.Int64("jid", j.Id))
+				return
+			}
 			if err1 != nil {
+				// 失败了也等下一次调度，不然释放之后马上又会被抢占
+				// 远程执行器失败的时候尤其明显，失败原因看执行记录
 				s.l.Error("执行任务失败",
 					logger.Int64("jid", j.Id),
 					logger.Error(err1))
-				return
 			}
 			err1 = s.svc.ResetNextTime(ctx, j)
 			if err1 != nil {
@@ -119,3 +166,47 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		}()
 	}
 }
+
+// startExecution 执行记录写失败了也照样执行，返回 0 表示没有记录
+func (s *Scheduler) startExecution(ctx context.Context, j domain.Job) int64 {
+	dbCtx, cancel := context.WithTimeout(ctx, s.dbTimeout)
+	defer cancel()
+	eid, err := s.svc.StartExecution(dbCtx, domain.JobExecution{
+		Jid:       j.Id,
+		Node:      s.node,
+		StartTime: time.Now(),
+	})
+	if err != nil {
+		s.l.Error("记录任务开始执行失败",
+			logger.Int64("jid", j.Id),
+			logger.Error(err))
+		return 0
+	}
+	return eid
+}
+
+func (s *Scheduler) finishExecution(j domain.Job, eid int64, execErr error) {
+	if eid == 0 {
+		return
+	}
+	e := domain.JobExecution{
+		Id:      eid,
+		Jid:     j.Id,
+		Status:  domain.JobExecutionSucceeded,
+		EndTime: time.Now(),
+	}
+	if execErr != nil {
+		e.Status = domain.JobExecutionFailed
+		e.Err = execErr.Error()
+	}
+	// 调度被取消的时候也要把结果记下来
+	dbCtx, cancel := context.WithTimeout(context.Background(), s.dbTimeout)
+	defer cancel()
+	err := s.svc.FinishExecution(dbCtx, e)
+	if err != nil {
+		s.l.Error("记录任务执行结果失败",
+			logger.Int64("jid", j.Id),
+			logger.Int64("eid", eid),
+			logger.Error(err))
+	}
+}
@@ -13,6 +13,9 @@ type RankingJob struct {
 	svc     service.RankingService
 	l       logger.LoggerV1
 	timeout time.Duration
+	name    string
+	// run 拿到锁之后要做的计算
+	run func(ctx context.Context) error
 
 	key       string
 	client    *rlock.Client
@@ -26,14 +29,38 @@ func NewRankingJob(svc service.RankingService, l logger.LoggerV1,
 		svc:       svc,
 		l:         l,
 		timeout:   timeout,
+		name:      "ranking",
+		run:       svc.TopN,
 		key:       "job:ranking",
 		client:    client,
 		localLock: &sync.Mutex{},
 	}
 }
 
+// UnboundedRankingJob 总榜这种不限时间段的榜单要扫全部帖子，
+// 用单独的锁和更长的超时，慢一点调度，不拖累别的榜单
+type UnboundedRankingJob struct {
+	*RankingJob
+}
+
+func NewUnboundedRankingJob(svc service.RankingService, l logger.LoggerV1,
+	timeout time.Duration, client *rlock.Client) *UnboundedRankingJob {
+	return &UnboundedRankingJob{
+		RankingJob: &RankingJob{
+			svc:       svc,
+			l:         l,
+			timeout:   timeout,
+			name:      "ranking_unbounded",
+			run:       svc.TopNUnbounded,
+			key:       "job:ranking:unbounded",
+			client:    client,
+			localLock: &sync.Mutex{},
+		},
+	}
+}
+
 func (r *RankingJob) Name() string {
-	return "ranking"
+	return r.name
 }
 
 func (r *RankingJob) Run() error {
@@ -70,7 +97,7 @@ func (r *RankingJob) Run() error {
 	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
 	defer cancel()
 
-	return r.svc.TopN(ctx)
+	return r.run(ctx)
 }
 
 // Close 释放分布式锁
@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"geektime/webook/internal/service"
+	"time"
+)
+
+// RankingRebaseJob 定期把半衰期榜单的分数衰减到现在
+// 衰减是按照基准时间算的，几个节点同时跑也没有关系，所以不用分布式锁
+type RankingRebaseJob struct {
+	svc     service.IncrRankingService
+	timeout time.Duration
+}
+
+func NewRankingRebaseJob(svc service.IncrRankingService, timeout time.Duration) *RankingRebaseJob {
+	return &RankingRebaseJob{
+		svc:     svc,
+		timeout: timeout,
+	}
+}
+
+func (r *RankingRebaseJob) Name() string {
+	return "ranking_rebase"
+}
+
+func (r *RankingRebaseJob) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	return r.svc.Rebase(ctx)
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/repository/cache"
+	"geektime/webook/internal/repository/dao"
+	"time"
+)
+
+type AntiAbuseRepository interface {
+	// FirstRead uid 在窗口期内是不是第一次读这个资源
+	FirstRead(ctx context.Context, uid int64, biz string, bizId int64, window time.Duration) (bool, error)
+	// AddAudit 同一个用户同一种操作在窗口期内只记一条
+	AddAudit(ctx context.Context, a domain.AbuseAudit, window time.Duration) error
+}
+
+type CachedAntiAbuseRepository struct {
+	dao   dao.AbuseAuditDAO
+	cache cache.AntiAbuseCache
+}
+
+func NewAntiAbuseRepository(dao dao.AbuseAuditDAO, cache cache.AntiAbuseCache) AntiAbuseRepository {
+	return &CachedAntiAbuseRepository{dao: dao, cache: cache}
+}
+
+func (r *CachedAntiAbuseRepository) FirstRead(ctx context.Context, uid int64,
+	biz string, bizId int64, window time.Duration) (bool, error) {
+	return r.cache.MarkRead(ctx, uid, biz, bizId, window)
+}
+
+func (r *CachedAntiAbuseRepository) AddAudit(ctx context.Context,
+	a domain.AbuseAudit, window time.Duration) error {
+	first, err := r.cache.MarkAudited(ctx, a.Uid, a.Action, window)
+	if err != nil || !first {
+		return err
+	}
+	return r.dao.Insert(ctx, dao.AbuseAudit{
+		Uid:    a.Uid,
+		Action: a.Action,
+		Biz:    a.Biz,
+		BizId:  a.BizId,
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"geektime/webook/internal/domain"
 	"geektime/webook/internal/repository/cache"
 	"geektime/webook/internal/repository/dao"
@@ -16,31 +17,107 @@ type ArticleRepository interface {
 	Sync(ctx context.Context, art domain.Article) (int64, error)
 	// SyncV1 存储并同步数据
 	SyncV1(ctx context.Context, art domain.Article) (int64, error)
-	SyncStatus(ctx context.Context, artId int64, authorId int64, status int) error
+	// SyncStatus uid 是操作的人，只有 owner 可以修改线上库的状态
+	SyncStatus(ctx context.Context, artId int64, uid int64, status int) error
 
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
 	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]domain.Article, error)
+	// GetByAuthorAfter 游标翻页，第一页和 GetByAuthor 共用缓存
+	GetByAuthorAfter(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error)
+	ListPubAfter(ctx context.Context, cur domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// GetAuthorProfile 作者主页，带上第一页已发表的帖子，整个主页一起缓存
+	GetAuthorProfile(ctx context.Context, uid int64) (domain.AuthorProfile, error)
+	ListPubByAuthor(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// ListPubByIds 已发表的帖子，只有标题这些元数据，没有内容
+	ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
 	GetById(ctx context.Context, id int64) (domain.Article, error)
 	GetPubById(ctx context.Context, id int64) (domain.Article, error)
-	preCache(ctx context.Context, arts []domain.Article)
+
+	ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleRevision, error)
+	GetRevision(ctx context.Context, id int64) (domain.ArticleRevision, error)
+
+	ListScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error)
+	CancelSchedule(ctx context.Context, artId int64, authorId int64) error
+	// PublishScheduled art 需要带上制作库的 Utime 和渲染结果
+	PublishScheduled(ctx context.Context, art domain.Article) (domain.Article, error)
+
+	ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]domain.Article, error)
+	CountTags(ctx context.Context, limit int) ([]domain.TagCount, error)
+
+	// Role uid 在帖子上的角色，art 是制作库的帖子
+	Role(ctx context.Context, art domain.Article, uid int64) (domain.CoAuthorRole, error)
+	InviteCoAuthor(ctx context.Context, c domain.CoAuthor) error
+	AcceptCoAuthor(ctx context.Context, artId int64, uid int64) error
+	RevokeCoAuthor(ctx context.Context, artId int64, uid int64) error
+	// ListCoAuthors 带上合作者的昵称
+	ListCoAuthors(ctx context.Context, artId int64) ([]domain.CoAuthor, error)
+
+	// Delete 把帖子放进回收站，art 是制作库的帖子
+	Delete(ctx context.Context, art domain.Article) error
+	Restore(ctx context.Context, art domain.Article) error
+	ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]domain.Article, error)
+	// Purge 彻底删除回收站里面的帖子，连同合作者、专栏和所有缓存
+	Purge(ctx context.Context, art domain.Article) error
+
+	CreateSeries(ctx context.Context, s domain.ArticleSeries) (int64, error)
+	// UpdateSeries 只能修改自己的专栏
+	UpdateSeries(ctx context.Context, s domain.ArticleSeries) error
+	DeleteSeries(ctx context.Context, id int64, authorId int64) error
+	// GetSeries 不带专栏里面的帖子
+	GetSeries(ctx context.Context, id int64) (domain.ArticleSeries, error)
+	// ListSeriesArticles 按照顺序返回制作库的帖子，包括还没发表的
+	ListSeriesArticles(ctx context.Context, seriesId int64) ([]domain.Article, error)
+	ListSeries(ctx context.Context, authorId int64, offset int, limit int) ([]domain.ArticleSeries, error)
+	AddSeriesArticle(ctx context.Context, seriesId int64, artId int64) error
+	RemoveSeriesArticle(ctx context.Context, seriesId int64, artId int64) error
+	ReorderSeries(ctx context.Context, seriesId int64, artIds []int64) error
+	// SeriesNav 线上库里面帖子的上一篇和下一篇
+	SeriesNav(ctx context.Context, artId int64) (domain.SeriesNav, error)
 }
 
+// tagFirstPageSize 标签列表第一页的大小，只有第一页走缓存
+const tagFirstPageSize = 20
+
+var (
+	ErrArticleNotScheduled  = dao.ErrArticleNotScheduled
+	ErrCoAuthorNotFound     = dao.ErrCoAuthorNotFound
+	ErrArticleNotInTrash    = dao.ErrArticleNotInTrash
+	ErrArticleInTrash       = dao.ErrArticleInTrash
+	ErrPubArticleNotFound   = dao.ErrPubArticleNotFound
+	ErrAuthorNotFound       = dao.ErrUserNotFound
+	ErrSeriesNotFound       = dao.ErrSeriesNotFound
+	ErrArticleInOtherSeries = dao.ErrArticleInOtherSeries
+	ErrIllegalSeriesOrder   = dao.ErrIllegalSeriesOrder
+	ErrPermissionDenied     = errors.New("没有权限操作这篇帖子")
+)
+
+// ArticleVersionConflictError 修改的时候带的版本号不是最新的
+type ArticleVersionConflictError = dao.ArticleVersionConflictError
+
 type articleRepository struct {
 	dao       dao.ArticleDAO
 	readerDao dao.ReaderDao
 	authorDao dao.AuthorDao
 	userDao   dao.UserDAO
+	// coAuthorDao 合作者只放在 MySQL，不管帖子本身存在哪里
+	coAuthorDao dao.ArticleCoAuthorDAO
+	// seriesDao 专栏也只放在 MySQL
+	seriesDao dao.ArticleSeriesDAO
 
 	cache cache.ArticleCache
 	l     logger.LoggerV1
 }
 
-func NewArticleRepository(dao dao.ArticleDAO, cache cache.ArticleCache, l logger.LoggerV1, userDao dao.UserDAO) ArticleRepository {
+func NewArticleRepository(dao dao.ArticleDAO, cache cache.ArticleCache, l logger.LoggerV1,
+	userDao dao.UserDAO, coAuthorDao dao.ArticleCoAuthorDAO, seriesDao dao.ArticleSeriesDAO) ArticleRepository {
 	return &articleRepository{
-		dao:     dao,
-		cache:   cache,
-		l:       l,
-		userDao: userDao,
+		dao:         dao,
+		cache:       cache,
+		l:           l,
+		userDao:     userDao,
+		coAuthorDao: coAuthorDao,
+		seriesDao:   seriesDao,
 	}
 }
 func (c *articleRepository) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]domain.Article, error) {
@@ -54,21 +131,112 @@ func (c *articleRepository) ListPub(ctx context.Context, start time.Time, offset
 		}), nil
 }
 
+func (c *articleRepository) ListPubAfter(ctx context.Context, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListPubAfter(ctx, c.cursorToEntity(cur), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.PublishArticle, domain.Article](arts,
+		func(idx int, src dao.PublishArticle) domain.Article {
+			return c.toDomain(dao.Article(src))
+		}), nil
+}
+
+func (c *articleRepository) GetAuthorProfile(ctx context.Context, uid int64) (domain.AuthorProfile, error) {
+	res, err := c.cache.GetAuthorProfile(ctx, uid)
+	if err == nil {
+		return res, nil
+	}
+	u, err := c.userDao.FindById(ctx, uid)
+	if err != nil {
+		return domain.AuthorProfile{}, err
+	}
+	cnt, err := c.dao.CountPubByAuthor(ctx, uid)
+	if err != nil {
+		return domain.AuthorProfile{}, err
+	}
+	arts, err := c.ListPubByAuthor(ctx, uid, domain.ArticleCursor{}, domain.AuthorProfilePageSize)
+	if err != nil {
+		return domain.AuthorProfile{}, err
+	}
+	res = domain.AuthorProfile{
+		Author:     domain.Author{Id: u.Id, Name: u.Nickname},
+		AboutMe:    u.AboutMe,
+		ArticleCnt: cnt,
+		Articles:   arts,
+	}
+	//回写缓存，缓存的是摘要，不能影响返回的数据
+	cached := res
+	cached.Articles = make([]domain.Article, len(arts))
+	copy(cached.Articles, arts)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		er := c.cache.SetAuthorProfile(ctx, cached)
+		if er != nil {
+			c.l.Error("回写作者主页缓存失败", logger.Int64("uid", uid), logger.Error(er))
+		}
+	}()
+	return res, nil
+}
+
+func (c *articleRepository) ListPubByAuthor(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListPubByAuthor(ctx, uid, c.cursorToEntity(cur), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.PublishArticle, domain.Article](arts,
+		func(idx int, src dao.PublishArticle) domain.Article {
+			return c.toDomain(dao.Article(src))
+		}), nil
+}
+
+func (c *articleRepository) ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	arts, err := c.dao.ListPubByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.PublishArticle, domain.Article](arts,
+		func(idx int, src dao.PublishArticle) domain.Article {
+			return c.toDomain(dao.Article(src))
+		}), nil
+}
+
 func (c *articleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
 	//发表可能会更新制作库，清空用户文章第一页缓存
-	err := c.cache.DelFirstPage(ctx, art.Author.Id)
+	err := c.delAuthorCache(ctx, art)
 	if err != nil {
-		c.l.Error("删除缓存失败", logger.Int64("authorId", art.Author.Id))
 		return 0, err
 	}
-	//缓存发表文章
-	err = c.cache.SetPub(ctx, art)
+	err = c.delTagFirstPage(ctx, art.Id, art.Tags)
 	if err != nil {
-		c.l.Error("缓存线上库数据失败", logger.Int64("authorId", art.Author.Id))
+		c.l.Error("删除标签缓存失败", logger.Int64("artId", art.Id))
 		return 0, err
 	}
-
-	return c.dao.Sync(ctx, c.ToEntity(art))
+	err = c.cache.DelAuthorProfile(ctx, art.Author.Id)
+	if err != nil {
+		c.l.Error("删除作者主页缓存失败", logger.Int64("authorId", art.Author.Id))
+		return 0, err
+	}
+	// 先删掉线上库的缓存，写库失败的时候读者也不会读到没有发表成功的内容
+	if art.Id > 0 {
+		err = c.cache.DelPub(ctx, art.Id)
+		if err != nil {
+			c.l.Error("删除线上库缓存失败", logger.Int64("artId", art.Id))
+			return 0, err
+		}
+	}
+	id, err := c.dao.Sync(ctx, c.ToEntity(art))
+	if err != nil {
+		return 0, err
+	}
+	// 写库成功之后再删一次，写库期间读者可能把旧内容写回了缓存
+	// 这里不直接缓存 art，它没有作者和合作者的名字，让读者回源的时候重建
+	err = c.cache.DelPub(ctx, id)
+	if err != nil {
+		c.l.Error("删除线上库缓存失败", logger.Int64("artId", id), logger.Error(err))
+	}
+	return id, nil
 }
 
 func (c *articleRepository) SyncV1(ctx context.Context, art domain.Article) (int64, error) {
@@ -91,13 +259,37 @@ func (c *articleRepository) SyncV1(ctx context.Context, art domain.Article) (int
 	return id, err
 }
 
-func (c *articleRepository) SyncStatus(ctx context.Context, artId int64, authorId int64, status int) error {
+func (c *articleRepository) SyncStatus(ctx context.Context, artId int64, uid int64, status int) error {
+	art, err := c.GetById(ctx, artId)
+	if err != nil {
+		return err
+	}
+	role, err := c.Role(ctx, art, uid)
+	if err != nil {
+		return err
+	}
+	if !role.CanManage() {
+		return ErrPermissionDenied
+	}
+	// 后面都按照 owner 来操作
+	authorId := art.Author.Id
 	//清空缓存
-	err := c.cache.DelFirstPage(ctx, authorId)
+	err = c.cache.DelFirstPage(ctx, authorId)
 	if err != nil {
 		c.l.Error("删除缓存失败", logger.Int64("authorId", authorId))
 		return err
 	}
+	//撤回之后标签列表里面也不能再出现
+	err = c.delTagFirstPage(ctx, artId, nil)
+	if err != nil {
+		c.l.Error("删除标签缓存失败", logger.Int64("artId", artId))
+		return err
+	}
+	err = c.cache.DelAuthorProfile(ctx, authorId)
+	if err != nil {
+		c.l.Error("删除作者主页缓存失败", logger.Int64("authorId", authorId))
+		return err
+	}
 	return c.dao.SyncStatus(ctx, artId, authorId, status)
 }
 
@@ -113,19 +305,40 @@ func (c *articleRepository) Create(ctx context.Context, art domain.Article) (int
 
 func (c *articleRepository) Update(ctx context.Context, art domain.Article) error {
 	//清空缓存
+	err := c.delAuthorCache(ctx, art)
+	if err != nil {
+		return err
+	}
+	return c.dao.Update(ctx, c.ToEntity(art))
+}
+
+// delAuthorCache 修改制作库之前删掉作者列表的第一页和帖子本身的缓存
+// 帖子的缓存里面有版本号，不删的话作者拿到的一直是旧的版本号
+func (c *articleRepository) delAuthorCache(ctx context.Context, art domain.Article) error {
 	err := c.cache.DelFirstPage(ctx, art.Author.Id)
 	if err != nil {
 		c.l.Error("删除缓存失败", logger.Int64("authorId", art.Author.Id))
 		return err
 	}
-	return c.dao.Update(ctx, c.ToEntity(art))
+	if art.Id > 0 {
+		err = c.cache.Del(ctx, art.Id)
+		if err != nil {
+			c.l.Error("删除缓存失败", logger.Int64("artId", art.Id))
+			return err
+		}
+	}
+	return nil
 }
 
 func (c *articleRepository) GetPubById(ctx context.Context, id int64) (domain.Article, error) {
 	//读取缓存
 	res, err := c.cache.GetPub(ctx, id)
 	if err == nil {
-		return res, err
+		// 缓存里面的也可能已经撤回了，不过撤回的时候会删缓存，这里只是兜底
+		if res.Status != domain.ArticleStatusPublished {
+			return domain.Article{}, ErrPubArticleNotFound
+		}
+		return res, nil
 	}
 	//读取线上库数据，如果Content放到了OSS中，就要让前端去读取Content
 	art, err := c.dao.GetPubById(ctx, id)
@@ -140,6 +353,15 @@ func (c *articleRepository) GetPubById(ctx context.Context, id int64) (domain.Ar
 		return domain.Article{}, err
 	}
 	res.Author.Name = author.Nickname
+	res.CoAuthors, err = c.acceptedCoAuthors(ctx, art.Id)
+	if err != nil {
+		c.l.Error("获取合作者信息失败", logger.Int64("artId", art.Id), logger.Error(err))
+		return domain.Article{}, err
+	}
+	// 预签名的下载链接会过期，缓存的时间不一定比它短，这种就不缓存了
+	if res.ContentURL != "" {
+		return res, nil
+	}
 	//回写缓存
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -196,14 +418,39 @@ func (c *articleRepository) GetByAuthor(ctx context.Context, uid int64, offset i
 	res := slice.Map[dao.Article, domain.Article](arts, func(idx int, src dao.Article) domain.Article {
 		return c.toDomain(src)
 	})
+	c.afterGetByAuthor(uid, res, offset == 0 && limit == 100)
+	return res, nil
+}
 
+// GetByAuthorAfter 游标翻页的第一页和 offset 为 0 的第一页是同样的数据
+func (c *articleRepository) GetByAuthorAfter(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	firstPage := cur.IsZero() && limit == 100
+	if firstPage {
+		res, err := c.cache.GetFirstPage(ctx, uid)
+		if err == nil {
+			return res, nil
+		}
+	}
+	arts, err := c.dao.GetByAuthorAfter(ctx, uid, c.cursorToEntity(cur), limit)
+	if err != nil {
+		return nil, err
+	}
+	res := slice.Map[dao.Article, domain.Article](arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	})
+	c.afterGetByAuthor(uid, res, firstPage)
+	return res, nil
+}
+
+// afterGetByAuthor 异步回写第一页缓存，并且预加载第一篇帖子
+func (c *articleRepository) afterGetByAuthor(uid int64, res []domain.Article, firstPage bool) {
 	//回写缓存
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
-		if offset == 0 && limit == 100 {
+		if firstPage {
 			// 缓存回写失败，不一定是大问题，但有可能是大问题
-			err = c.cache.SetFirstPage(ctx, uid, res)
+			err := c.cache.SetFirstPage(ctx, uid, res)
 			if err != nil {
 				// 记录日志
 				// 我需要监控这里
@@ -216,9 +463,316 @@ func (c *articleRepository) GetByAuthor(ctx context.Context, uid int64, offset i
 		defer cancel()
 		c.preCache(ctx, res)
 	}()
+}
+
+func (c *articleRepository) ListScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListScheduled(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Article, domain.Article](arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *articleRepository) CancelSchedule(ctx context.Context, artId int64, authorId int64) error {
+	//清空缓存
+	err := c.cache.DelFirstPage(ctx, authorId)
+	if err != nil {
+		c.l.Error("删除缓存失败", logger.Int64("authorId", authorId))
+		return err
+	}
+	return c.dao.CancelSchedule(ctx, artId, authorId)
+}
+
+func (c *articleRepository) PublishScheduled(ctx context.Context, rendered domain.Article) (domain.Article, error) {
+	// 之前发表过的话，线上库原本的标签要在发表之前查出来
+	oldTags := c.pubTags(ctx, rendered.Id)
+	entity := c.ToEntity(rendered)
+	entity.Utime = rendered.Utime.UnixMilli()
+	art, err := c.dao.PublishScheduled(ctx, entity)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	res := c.toDomain(art)
+	//发表之后作者的列表、标签列表和线上库的缓存都变了
+	err = c.cache.DelFirstPage(ctx, res.Author.Id)
+	if err != nil {
+		c.l.Error("删除缓存失败", logger.Int64("authorId", res.Author.Id))
+	}
+	err = c.cache.DelTagFirstPage(ctx, append(oldTags, res.Tags...)...)
+	if err != nil {
+		c.l.Error("删除标签缓存失败", logger.Int64("artId", res.Id))
+	}
+	err = c.cache.DelAuthorProfile(ctx, res.Author.Id)
+	if err != nil {
+		c.l.Error("删除作者主页缓存失败", logger.Int64("authorId", res.Author.Id))
+	}
+	// 和 Sync 一样只删缓存，读者回源的时候会补上作者和合作者的名字
+	err = c.cache.DelPub(ctx, res.Id)
+	if err != nil {
+		c.l.Error("删除线上库缓存失败", logger.Int64("artId", res.Id))
+	}
+	return res, nil
+}
+
+// ListPubByTag 和作者的列表一样，只有第一页走缓存
+func (c *articleRepository) ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]domain.Article, error) {
+	if offset == 0 && limit == tagFirstPageSize {
+		res, err := c.cache.GetTagFirstPage(ctx, tag)
+		if err == nil {
+			return res, nil
+		}
+	}
+	arts, err := c.dao.ListPubByTag(ctx, tag, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := slice.Map[dao.PublishArticle, domain.Article](arts,
+		func(idx int, src dao.PublishArticle) domain.Article {
+			return c.toDomain(dao.Article(src))
+		})
+	if offset == 0 && limit == tagFirstPageSize {
+		//回写缓存，缓存的是摘要，不能影响返回的数据
+		cached := make([]domain.Article, len(res))
+		copy(cached, res)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			er := c.cache.SetTagFirstPage(ctx, tag, cached)
+			if er != nil {
+				c.l.Error("回写标签缓存失败", logger.String("tag", tag), logger.Error(er))
+			}
+		}()
+	}
 	return res, nil
 }
 
+func (c *articleRepository) CountTags(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	cnts, err := c.dao.CountPubTags(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.TagCount, domain.TagCount](cnts, func(idx int, src dao.TagCount) domain.TagCount {
+		return domain.TagCount{Tag: src.Tag, Count: src.Cnt}
+	}), nil
+}
+
+func (c *articleRepository) Role(ctx context.Context, art domain.Article, uid int64) (domain.CoAuthorRole, error) {
+	if art.Author.Id == uid {
+		return domain.CoAuthorRoleOwner, nil
+	}
+	ca, err := c.coAuthorDao.FindByUid(ctx, art.Id, uid)
+	if errors.Is(err, dao.ErrCoAuthorNotFound) {
+		return domain.CoAuthorRoleUnknown, nil
+	}
+	if err != nil {
+		return domain.CoAuthorRoleUnknown, err
+	}
+	// 还没接受或者已经撤销的邀请没有任何权限
+	if domain.CoAuthorStatus(ca.Status) != domain.CoAuthorStatusAccepted {
+		return domain.CoAuthorRoleUnknown, nil
+	}
+	return domain.CoAuthorRole(ca.Role), nil
+}
+
+func (c *articleRepository) InviteCoAuthor(ctx context.Context, ca domain.CoAuthor) error {
+	return c.coAuthorDao.Invite(ctx, dao.ArticleCoAuthor{
+		ArticleId: ca.ArticleId,
+		Uid:       ca.Uid,
+		Role:      ca.Role.ToUint8(),
+		Inviter:   ca.Inviter,
+	})
+}
+
+// AcceptCoAuthor 线上库的缓存里面有合作者的名字，接受和撤销之后都要删掉
+func (c *articleRepository) AcceptCoAuthor(ctx context.Context, artId int64, uid int64) error {
+	err := c.coAuthorDao.Accept(ctx, artId, uid)
+	if err != nil {
+		return err
+	}
+	return c.cache.DelPub(ctx, artId)
+}
+
+func (c *articleRepository) RevokeCoAuthor(ctx context.Context, artId int64, uid int64) error {
+	err := c.coAuthorDao.Revoke(ctx, artId, uid)
+	if err != nil {
+		return err
+	}
+	return c.cache.DelPub(ctx, artId)
+}
+
+func (c *articleRepository) ListCoAuthors(ctx context.Context, artId int64) ([]domain.CoAuthor, error) {
+	cas, err := c.coAuthorDao.ListByArticle(ctx, artId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.CoAuthor, 0, len(cas))
+	for _, ca := range cas {
+		u, err := c.userDao.FindById(ctx, ca.Uid)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, domain.CoAuthor{
+			ArticleId: ca.ArticleId,
+			Uid:       ca.Uid,
+			Name:      u.Nickname,
+			Role:      domain.CoAuthorRole(ca.Role),
+			Status:    domain.CoAuthorStatus(ca.Status),
+			Inviter:   ca.Inviter,
+			Ctime:     time.UnixMilli(ca.Ctime),
+			Utime:     time.UnixMilli(ca.Utime),
+		})
+	}
+	return res, nil
+}
+
+func (c *articleRepository) Delete(ctx context.Context, art domain.Article) error {
+	err := c.delAuthorCache(ctx, art)
+	if err != nil {
+		return err
+	}
+	//线上库变成了仅自己可见，标签列表和读者的缓存都要删掉
+	err = c.delTagFirstPage(ctx, art.Id, nil)
+	if err != nil {
+		c.l.Error("删除标签缓存失败", logger.Int64("artId", art.Id))
+		return err
+	}
+	err = c.cache.DelAuthorProfile(ctx, art.Author.Id)
+	if err != nil {
+		c.l.Error("删除作者主页缓存失败", logger.Int64("authorId", art.Author.Id))
+		return err
+	}
+	err = c.dao.Delete(ctx, art.Id, art.Author.Id)
+	if err != nil {
+		return err
+	}
+	// 线上库改完之后再删，不然删掉之后、改之前读者又会把已发表的帖子写回缓存
+	err = c.cache.DelPub(ctx, art.Id)
+	if err != nil {
+		c.l.Error("删除线上库缓存失败", logger.Int64("artId", art.Id))
+	}
+	return err
+}
+
+func (c *articleRepository) Restore(ctx context.Context, art domain.Article) error {
+	err := c.delAuthorCache(ctx, art)
+	if err != nil {
+		return err
+	}
+	return c.dao.Restore(ctx, art.Id, art.Author.Id)
+}
+
+func (c *articleRepository) ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListTrash(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Article, domain.Article](arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *articleRepository) ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListExpiredTrash(ctx, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Article, domain.Article](arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+// Purge 帖子已经删掉了，后面清理合作者和缓存失败只记录日志
+func (c *articleRepository) Purge(ctx context.Context, art domain.Article) error {
+	err := c.dao.Purge(ctx, art.Id)
+	if err != nil {
+		return err
+	}
+	err = c.coAuthorDao.DeleteByArticle(ctx, art.Id)
+	if err != nil {
+		c.l.Error("删除合作者失败", logger.Int64("artId", art.Id), logger.Error(err))
+	}
+	err = c.seriesDao.DeleteByArticle(ctx, art.Id)
+	if err != nil {
+		c.l.Error("从专栏移除失败", logger.Int64("artId", art.Id), logger.Error(err))
+	}
+	err = c.cache.DelFirstPage(ctx, art.Author.Id)
+	if err != nil {
+		c.l.Error("删除缓存失败", logger.Int64("authorId", art.Author.Id), logger.Error(err))
+	}
+	err = c.cache.Del(ctx, art.Id)
+	if err != nil {
+		c.l.Error("删除缓存失败", logger.Int64("artId", art.Id), logger.Error(err))
+	}
+	err = c.cache.DelPub(ctx, art.Id)
+	if err != nil {
+		c.l.Error("删除线上库缓存失败", logger.Int64("artId", art.Id), logger.Error(err))
+	}
+	err = c.cache.DelAuthorProfile(ctx, art.Author.Id)
+	if err != nil {
+		c.l.Error("删除作者主页缓存失败", logger.Int64("authorId", art.Author.Id), logger.Error(err))
+	}
+	return nil
+}
+
+// acceptedCoAuthors 读者看到的合作者，只有接受了邀请的才算
+func (c *articleRepository) acceptedCoAuthors(ctx context.Context, artId int64) ([]domain.Author, error) {
+	cas, err := c.coAuthorDao.ListByArticle(ctx, artId)
+	if err != nil {
+		return nil, err
+	}
+	var res []domain.Author
+	for _, ca := range cas {
+		if domain.CoAuthorStatus(ca.Status) != domain.CoAuthorStatusAccepted {
+			continue
+		}
+		u, err := c.userDao.FindById(ctx, ca.Uid)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, domain.Author{Id: ca.Uid, Name: u.Nickname})
+	}
+	return res, nil
+}
+
+// delTagFirstPage 删除帖子新旧标签的第一页缓存
+// 修改了标签之后，旧标签的列表里面也不能再出现这篇帖子
+func (c *articleRepository) delTagFirstPage(ctx context.Context, artId int64, tags []string) error {
+	return c.cache.DelTagFirstPage(ctx, append(c.pubTags(ctx, artId), tags...)...)
+}
+
+// pubTags 线上库帖子当前的标签，还没发表过的帖子没有
+func (c *articleRepository) pubTags(ctx context.Context, artId int64) []string {
+	if artId <= 0 {
+		return nil
+	}
+	art, err := c.dao.GetPubById(ctx, artId)
+	if err != nil {
+		return nil
+	}
+	return art.Tags
+}
+
+func (c *articleRepository) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleRevision, error) {
+	revs, err := c.dao.ListRevisions(ctx, artId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.ArticleRevision, domain.ArticleRevision](revs,
+		func(idx int, src dao.ArticleRevision) domain.ArticleRevision {
+			return c.revisionToDomain(src)
+		}), nil
+}
+
+func (c *articleRepository) GetRevision(ctx context.Context, id int64) (domain.ArticleRevision, error) {
+	rev, err := c.dao.GetRevision(ctx, id)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	return c.revisionToDomain(rev), nil
+}
+
 func (c *articleRepository) preCache(ctx context.Context, arts []domain.Article) {
 	if len(arts) > 0 {
 		err := c.cache.Set(ctx, arts[0])
@@ -235,21 +789,79 @@ func (c *articleRepository) ToEntity(art domain.Article) dao.Article {
 		Content:  art.Content,
 		AuthorId: art.Author.Id,
 
-		Status: uint8(art.Status),
+		Status:    uint8(art.Status),
+		Category:  art.Category,
+		Tags:      art.Tags,
+		PublishAt: c.publishAtToEntity(art.PublishAt),
+		Version:   art.Version,
+
+		HTML:        art.Rendered.HTML,
+		Abstract:    art.Rendered.Abstract,
+		WordCnt:     art.Rendered.WordCnt,
+		ReadingTime: int64(art.Rendered.ReadingTime / time.Second),
 	}
 }
 
+func (c *articleRepository) cursorToEntity(cur domain.ArticleCursor) dao.ArticleCursor {
+	if cur.IsZero() {
+		return dao.ArticleCursor{}
+	}
+	return dao.ArticleCursor{Utime: cur.Utime.UnixMilli(), Id: cur.Id}
+}
+
+func (c *articleRepository) publishAtToEntity(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
 func (c *articleRepository) toDomain(art dao.Article) domain.Article {
-	return domain.Article{
-		Id:      art.Id,
-		Title:   art.Title,
-		Content: art.Content,
+	res := domain.Article{
+		Id:         art.Id,
+		Title:      art.Title,
+		Content:    art.Content,
+		ContentURL: art.ContentURL,
 		Author: domain.Author{
 			// 这里有一个错误
 			Id: art.AuthorId,
 		},
-		Ctime:  time.UnixMilli(art.Ctime),
-		Utime:  time.UnixMilli(art.Utime),
-		Status: domain.ArticleStatus(art.Status),
+		Category: art.Category,
+		Tags:     art.Tags,
+		Rendered: domain.RenderedContent{
+			HTML:        art.HTML,
+			Abstract:    art.Abstract,
+			WordCnt:     art.WordCnt,
+			ReadingTime: time.Duration(art.ReadingTime) * time.Second,
+		},
+		Version: art.Version,
+		Ctime:   time.UnixMilli(art.Ctime),
+		Utime:   time.UnixMilli(art.Utime),
+		Status:  domain.ArticleStatus(art.Status),
+	}
+	if art.PublishAt > 0 {
+		res.PublishAt = time.UnixMilli(art.PublishAt)
+	}
+	if art.DeletedAt > 0 {
+		res.DeletedAt = time.UnixMilli(art.DeletedAt)
+	}
+	return res
+}
+
+func (c *articleRepository) revisionToDomain(rev dao.ArticleRevision) domain.ArticleRevision {
+	ctime := time.UnixMilli(rev.Ctime)
+	return domain.ArticleRevision{
+		Id: rev.Id,
+		Article: domain.Article{
+			Id:      rev.ArticleId,
+			Title:   rev.Title,
+			Content: rev.Content,
+			Author: domain.Author{
+				Id: rev.AuthorId,
+			},
+			Status: domain.ArticleStatus(rev.Status),
+			Utime:  ctime,
+		},
+		Ctime: ctime,
 	}
 }
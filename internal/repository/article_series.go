@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/repository/dao"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+)
+
+func (c *articleRepository) CreateSeries(ctx context.Context, s domain.ArticleSeries) (int64, error) {
+	return c.seriesDao.Insert(ctx, c.seriesToEntity(s))
+}
+
+func (c *articleRepository) UpdateSeries(ctx context.Context, s domain.ArticleSeries) error {
+	return c.seriesDao.Update(ctx, c.seriesToEntity(s))
+}
+
+func (c *articleRepository) DeleteSeries(ctx context.Context, id int64, authorId int64) error {
+	return c.seriesDao.Delete(ctx, id, authorId)
+}
+
+func (c *articleRepository) GetSeries(ctx context.Context, id int64) (domain.ArticleSeries, error) {
+	s, err := c.seriesDao.FindById(ctx, id)
+	if err != nil {
+		return domain.ArticleSeries{}, err
+	}
+	return c.seriesToDomain(s), nil
+}
+
+// ListSeriesArticles 专栏一般没有几篇帖子，帖子直接走 GetById 的缓存
+func (c *articleRepository) ListSeriesArticles(ctx context.Context, seriesId int64) ([]domain.Article, error) {
+	items, err := c.seriesDao.ListItems(ctx, seriesId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, 0, len(items))
+	for _, item := range items {
+		art, err := c.GetById(ctx, item.ArticleId)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, art)
+	}
+	return res, nil
+}
+
+func (c *articleRepository) ListSeries(ctx context.Context, authorId int64, offset int, limit int) ([]domain.ArticleSeries, error) {
+	ss, err := c.seriesDao.ListByAuthor(ctx, authorId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.ArticleSeries, domain.ArticleSeries](ss, func(idx int, src dao.ArticleSeries) domain.ArticleSeries {
+		return c.seriesToDomain(src)
+	}), nil
+}
+
+func (c *articleRepository) AddSeriesArticle(ctx context.Context, seriesId int64, artId int64) error {
+	return c.seriesDao.AddItem(ctx, seriesId, artId)
+}
+
+func (c *articleRepository) RemoveSeriesArticle(ctx context.Context, seriesId int64, artId int64) error {
+	return c.seriesDao.RemoveItem(ctx, seriesId, artId)
+}
+
+func (c *articleRepository) ReorderSeries(ctx context.Context, seriesId int64, artIds []int64) error {
+	return c.seriesDao.Reorder(ctx, seriesId, artIds)
+}
+
+// SeriesNav 只看线上库已发表的帖子，撤回了和仅自己可见的帖子在专栏里面直接跳过
+func (c *articleRepository) SeriesNav(ctx context.Context, artId int64) (domain.SeriesNav, error) {
+	item, err := c.seriesDao.FindItemByArticle(ctx, artId)
+	if errors.Is(err, dao.ErrSeriesNotFound) {
+		return domain.SeriesNav{}, nil
+	}
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	s, err := c.seriesDao.FindById(ctx, item.SeriesId)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	items, err := c.seriesDao.ListItems(ctx, item.SeriesId)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	ids := make([]int64, 0, len(items))
+	for _, it := range items {
+		ids = append(ids, it.ArticleId)
+	}
+	pubs, err := c.dao.ListPubByIds(ctx, ids)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	published := make(map[int64]domain.Article, len(pubs))
+	for _, pub := range pubs {
+		published[pub.Id] = c.toDomain(dao.Article(pub))
+	}
+	res := domain.SeriesNav{
+		Series: domain.ArticleSeries{Id: s.Id, Title: s.Title},
+	}
+	for i, id := range ids {
+		if id != artId {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if art, ok := published[ids[j]]; ok {
+				res.Prev = art
+				break
+			}
+		}
+		for j := i + 1; j < len(ids); j++ {
+			if art, ok := published[ids[j]]; ok {
+				res.Next = art
+				break
+			}
+		}
+		break
+	}
+	return res, nil
+}
+
+func (c *articleRepository) seriesToEntity(s domain.ArticleSeries) dao.ArticleSeries {
+	return dao.ArticleSeries{
+		Id:          s.Id,
+		Title:       s.Title,
+		Description: s.Description,
+		AuthorId:    s.Author.Id,
+	}
+}
+
+func (c *articleRepository) seriesToDomain(s dao.ArticleSeries) domain.ArticleSeries {
+	return domain.ArticleSeries{
+		Id:          s.Id,
+		Title:       s.Title,
+		Description: s.Description,
+		Author:      domain.Author{Id: s.AuthorId},
+		Ctime:       time.UnixMilli(s.Ctime),
+		Utime:       time.UnixMilli(s.Utime),
+	}
+}
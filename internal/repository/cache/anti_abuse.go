@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+type AntiAbuseCache interface {
+	// MarkRead 窗口期内第一次阅读返回 true
+	MarkRead(ctx context.Context, uid int64, biz string, bizId int64, window time.Duration) (bool, error)
+	// MarkAudited 窗口期内第一次记录审计返回 true，免得一次刷量写进去成千上万条
+	MarkAudited(ctx context.Context, uid int64, action string, window time.Duration) (bool, error)
+}
+
+type AntiAbuseRedisCache struct {
+	client redis.Cmdable
+}
+
+func NewAntiAbuseRedisCache(client redis.Cmdable) AntiAbuseCache {
+	return &AntiAbuseRedisCache{client: client}
+}
+
+func (c *AntiAbuseRedisCache) MarkRead(ctx context.Context, uid int64,
+	biz string, bizId int64, window time.Duration) (bool, error) {
+	key := fmt.Sprintf("abuse:read:%s:%d:%d", biz, uid, bizId)
+	return c.client.SetNX(ctx, key, 1, window).Result()
+}
+
+func (c *AntiAbuseRedisCache) MarkAudited(ctx context.Context, uid int64,
+	action string, window time.Duration) (bool, error) {
+	key := fmt.Sprintf("abuse:audited:%s:%d", action, uid)
+	return c.client.SetNX(ctx, key, 1, window).Result()
+}
@@ -15,8 +15,20 @@ type ArticleCache interface {
 	DelFirstPage(ctx context.Context, uid int64) error
 	Get(ctx context.Context, id int64) (domain.Article, error)
 	Set(ctx context.Context, art domain.Article) error
+	Del(ctx context.Context, id int64) error
 	GetPub(ctx context.Context, id int64) (domain.Article, error)
 	SetPub(ctx context.Context, res domain.Article) error
+	DelPub(ctx context.Context, id int64) error
+
+	// GetTagFirstPage 标签下已发表帖子的第一页
+	GetTagFirstPage(ctx context.Context, tag string) ([]domain.Article, error)
+	SetTagFirstPage(ctx context.Context, tag string, arts []domain.Article) error
+	DelTagFirstPage(ctx context.Context, tags ...string) error
+
+	// GetAuthorProfile 作者的公开主页，发表和撤回的时候删除
+	GetAuthorProfile(ctx context.Context, uid int64) (domain.AuthorProfile, error)
+	SetAuthorProfile(ctx context.Context, profile domain.AuthorProfile) error
+	DelAuthorProfile(ctx context.Context, uid int64) error
 }
 
 type ArticleRedisCache struct {
@@ -48,6 +60,10 @@ func (a *ArticleRedisCache) Set(ctx context.Context, art domain.Article) error {
 	return a.client.Set(ctx, a.key(art.Id), val, time.Minute*10).Err()
 }
 
+func (a *ArticleRedisCache) Del(ctx context.Context, id int64) error {
+	return a.client.Del(ctx, a.key(id)).Err()
+}
+
 func (a *ArticleRedisCache) GetPub(ctx context.Context, id int64) (domain.Article, error) {
 	val, err := a.client.Get(ctx, a.pubKey(id)).Bytes()
 	if err != nil {
@@ -66,6 +82,10 @@ func (a *ArticleRedisCache) SetPub(ctx context.Context, art domain.Article) erro
 	return a.client.Set(ctx, a.pubKey(art.Id), val, time.Minute*10).Err()
 }
 
+func (a *ArticleRedisCache) DelPub(ctx context.Context, id int64) error {
+	return a.client.Del(ctx, a.pubKey(id)).Err()
+}
+
 func (a *ArticleRedisCache) GetFirstPage(ctx context.Context, uid int64) ([]domain.Article, error) {
 	key := a.firstKey(uid)
 	//val, err := a.client.Get(ctx, firstKey).Result()
@@ -84,6 +104,7 @@ func (a *ArticleRedisCache) SetFirstPage(ctx context.Context, uid int64, arts []
 	//只需缓存摘要
 	for i := 0; i < len(arts); i++ {
 		arts[i].Content = arts[i].Abstract()
+		arts[i].Rendered.HTML = ""
 	}
 	key := a.firstKey(uid)
 	val, err := json.Marshal(arts)
@@ -97,6 +118,67 @@ func (a *ArticleRedisCache) DelFirstPage(ctx context.Context, uid int64) error {
 	return a.client.Del(ctx, a.firstKey(uid)).Err()
 }
 
+func (a *ArticleRedisCache) GetTagFirstPage(ctx context.Context, tag string) ([]domain.Article, error) {
+	val, err := a.client.Get(ctx, a.tagFirstKey(tag)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var res []domain.Article
+	err = json.Unmarshal(val, &res)
+	return res, err
+}
+
+// SetTagFirstPage 和作者的第一页一样，只缓存摘要
+func (a *ArticleRedisCache) SetTagFirstPage(ctx context.Context, tag string, arts []domain.Article) error {
+	for i := 0; i < len(arts); i++ {
+		arts[i].Content = arts[i].Abstract()
+		arts[i].Rendered.HTML = ""
+	}
+	val, err := json.Marshal(arts)
+	if err != nil {
+		return err
+	}
+	return a.client.Set(ctx, a.tagFirstKey(tag), val, time.Minute*10).Err()
+}
+
+func (a *ArticleRedisCache) DelTagFirstPage(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		keys = append(keys, a.tagFirstKey(tag))
+	}
+	return a.client.Del(ctx, keys...).Err()
+}
+
+func (a *ArticleRedisCache) GetAuthorProfile(ctx context.Context, uid int64) (domain.AuthorProfile, error) {
+	val, err := a.client.Get(ctx, a.authorProfileKey(uid)).Bytes()
+	if err != nil {
+		return domain.AuthorProfile{}, err
+	}
+	var res domain.AuthorProfile
+	err = json.Unmarshal(val, &res)
+	return res, err
+}
+
+// SetAuthorProfile 帖子只缓存摘要
+func (a *ArticleRedisCache) SetAuthorProfile(ctx context.Context, profile domain.AuthorProfile) error {
+	for i := 0; i < len(profile.Articles); i++ {
+		profile.Articles[i].Content = profile.Articles[i].Abstract()
+		profile.Articles[i].Rendered.HTML = ""
+	}
+	val, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	return a.client.Set(ctx, a.authorProfileKey(profile.Author.Id), val, time.Minute*10).Err()
+}
+
+func (a *ArticleRedisCache) DelAuthorProfile(ctx context.Context, uid int64) error {
+	return a.client.Del(ctx, a.authorProfileKey(uid)).Err()
+}
+
 func (a *ArticleRedisCache) pubKey(id int64) string {
 	return fmt.Sprintf("article:pub:detail:%d", id)
 }
@@ -108,3 +190,11 @@ func (a *ArticleRedisCache) key(id int64) string {
 func (a *ArticleRedisCache) firstKey(uid int64) string {
 	return fmt.Sprintf("article:first_page:%d", uid)
 }
+
+func (a *ArticleRedisCache) tagFirstKey(tag string) string {
+	return fmt.Sprintf("article:tag:first_page:%s", tag)
+}
+
+func (a *ArticleRedisCache) authorProfileKey(uid int64) string {
+	return fmt.Sprintf("article:author_profile:%d", uid)
+}
@@ -0,0 +1,22 @@
+-- 增量更新半衰期榜单
+-- 榜单里面存的是基准时间那一刻的分数，新的增量也要换算到基准时间
+local key = KEYS[1]
+local baseKey = KEYS[2]
+local halfLife = tonumber(ARGV[1])
+local now = tonumber(ARGV[2])
+local expiration = tonumber(ARGV[3])
+local base = tonumber(redis.call("GET", baseKey))
+if base == nil then
+    -- 还没有全量算过，就从现在开始
+    base = now
+    redis.call("SET", baseKey, base)
+end
+-- 后面每三个一组：帖子 id，帖子的更新时间，计数变化的加权和
+for i = 4, #ARGV, 3 do
+    local utime = tonumber(ARGV[i + 1])
+    local factor = math.pow(2, -(base - utime) / halfLife)
+    redis.call("ZINCRBY", key, tonumber(ARGV[i + 2]) * factor, ARGV[i])
+end
+redis.call("PEXPIRE", key, expiration)
+redis.call("PEXPIRE", baseKey, expiration)
+return 0
@@ -0,0 +1,23 @@
+-- 把半衰期榜单的分数统一衰减到现在，并且挪动基准时间
+-- 只是所有分数乘了同一个系数，排名不会变，但是分数不会越来越大
+local key = KEYS[1]
+local baseKey = KEYS[2]
+local halfLife = tonumber(ARGV[1])
+local now = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local expiration = tonumber(ARGV[4])
+local base = tonumber(redis.call("GET", baseKey))
+if base == nil then
+    -- 榜单还没有数据
+    return 0
+end
+-- 增量更新会把榜单外的帖子也加进来，只留前 n 个，剩下的等全量校正
+redis.call("ZREMRANGEBYRANK", key, 0, -n - 1)
+local factor = math.pow(2, -(now - base) / halfLife)
+local items = redis.call("ZRANGE", key, 0, -1, "WITHSCORES")
+for i = 1, #items, 2 do
+    redis.call("ZADD", key, tonumber(items[i + 1]) * factor, items[i])
+end
+redis.call("SET", baseKey, now, "PX", expiration)
+redis.call("PEXPIRE", key, expiration)
+return #items / 2
@@ -2,49 +2,108 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	_ "embed"
+	"fmt"
 	"geektime/webook/internal/domain"
 	"github.com/redis/go-redis/v9"
+	"strconv"
 	"time"
 )
 
+var (
+	//go:embed lua/incr_ranking.lua
+	luaIncrRanking string
+	//go:embed lua/rebase_ranking.lua
+	luaRebaseRanking string
+)
+
 type RankingCache interface {
-	Set(ctx context.Context, arts []domain.Article) error
-	Get(ctx context.Context) ([]domain.Article, error)
+	// ReplaceBoard 整个榜单一起替换，items 为空就是清空榜单
+	// base 是分数的基准时间，只有半衰期榜单用得上
+	ReplaceBoard(ctx context.Context, board string, items []domain.RankingItem, base time.Time) error
+	// GetBoard 按照分数从高到低分页
+	GetBoard(ctx context.Context, board string, offset int, limit int) ([]domain.RankingItem, error)
+	// IncrBoard 半衰期榜单的增量更新，增量按照基准时间换算之后加上去
+	IncrBoard(ctx context.Context, board string, halfLife time.Duration, deltas []domain.RankingDelta) error
+	// RebaseBoard 半衰期榜单的分数衰减到现在，只保留前 n 个
+	RebaseBoard(ctx context.Context, board string, halfLife time.Duration, n int) error
 }
 
+// RankingRedisCache 每个榜单一个 ZSET，member 是帖子 id
 type RankingRedisCache struct {
 	client redis.Cmdable
-	key    string
-	//过期时间可以长一点，甚至可以用不过期
+	//过期时间可以长一点，任务挂了一段时间榜单也还在
 	expiration time.Duration
 }
 
 func NewRankingRedisCache(client redis.Cmdable) RankingCache {
 	return &RankingRedisCache{
 		client:     client,
-		key:        "ranking:top_n",
-		expiration: time.Minute * 3,
+		expiration: time.Hour,
 	}
 }
 
-func (r *RankingRedisCache) Set(ctx context.Context, arts []domain.Article) error {
-	for i := range arts {
-		arts[i].Content = arts[i].Abstract()
-	}
-	val, err := json.Marshal(arts)
-	if err != nil {
-		return err
+func (r *RankingRedisCache) ReplaceBoard(ctx context.Context, board string, items []domain.RankingItem, base time.Time) error {
+	key := r.key(board)
+	members := make([]redis.Z, 0, len(items))
+	for _, item := range items {
+		members = append(members, redis.Z{Score: item.Score, Member: item.BizId})
 	}
-	return r.client.Set(ctx, r.key, val, r.expiration).Err()
+	// 放在一个事务里面，读的人不会看到只有一半的榜单
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(members) > 0 {
+			pipe.ZAdd(ctx, key, members...)
+			pipe.Expire(ctx, key, r.expiration)
+		}
+		pipe.Set(ctx, r.baseKey(board), base.UnixMilli(), r.expiration)
+		return nil
+	})
+	return err
 }
 
-func (r *RankingRedisCache) Get(ctx context.Context) ([]domain.Article, error) {
-	val, err := r.client.Get(ctx, r.key).Bytes()
+func (r *RankingRedisCache) GetBoard(ctx context.Context, board string, offset int, limit int) ([]domain.RankingItem, error) {
+	vals, err := r.client.ZRevRangeWithScores(ctx, r.key(board),
+		int64(offset), int64(offset+limit-1)).Result()
 	if err != nil {
 		return nil, err
 	}
-	var res []domain.Article
-	err = json.Unmarshal(val, &res)
-	return res, err
+	res := make([]domain.RankingItem, 0, len(vals))
+	for _, val := range vals {
+		id, err := strconv.ParseInt(val.Member.(string), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, domain.RankingItem{BizId: id, Score: val.Score})
+	}
+	return res, nil
+}
+
+func (r *RankingRedisCache) IncrBoard(ctx context.Context, board string,
+	halfLife time.Duration, deltas []domain.RankingDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	args := make([]any, 0, 3+len(deltas)*3)
+	args = append(args, halfLife.Milliseconds(), time.Now().UnixMilli(), r.expiration.Milliseconds())
+	for _, d := range deltas {
+		args = append(args, d.BizId, d.Utime.UnixMilli(), d.Weighted)
+	}
+	return r.client.Eval(ctx, luaIncrRanking,
+		[]string{r.key(board), r.baseKey(board)}, args...).Err()
+}
+
+func (r *RankingRedisCache) RebaseBoard(ctx context.Context, board string, halfLife time.Duration, n int) error {
+	return r.client.Eval(ctx, luaRebaseRanking,
+		[]string{r.key(board), r.baseKey(board)},
+		halfLife.Milliseconds(), time.Now().UnixMilli(), n, r.expiration.Milliseconds()).Err()
+}
+
+func (r *RankingRedisCache) key(board string) string {
+	return fmt.Sprintf("ranking:board:%s", board)
+}
+
+// baseKey 半衰期榜单分数的基准时间，毫秒数
+func (r *RankingRedisCache) baseKey(board string) string {
+	return fmt.Sprintf("ranking:board:%s:base", board)
 }
@@ -1,47 +0,0 @@
-package cache
-
-import (
-	"context"
-	"errors"
-	"geektime/webook/internal/domain"
-	"github.com/ecodeclub/ekit/syncx/atomicx"
-	"time"
-)
-
-type RankingLocalCache struct {
-	topN       *atomicx.Value[[]domain.Article]
-	ddl        *atomicx.Value[time.Time]
-	expiration time.Duration
-}
-
-func NewRankingLocalCache() *RankingLocalCache {
-	return &RankingLocalCache{
-		topN:       atomicx.NewValue[[]domain.Article](),
-		ddl:        atomicx.NewValue[time.Time](),
-		expiration: time.Minute * 10}
-}
-
-func (r *RankingLocalCache) Set(ctx context.Context, arts []domain.Article) error {
-	r.topN.Store(arts)
-	r.ddl.Store(time.Now().Add(r.expiration))
-	return nil
-}
-
-func (r *RankingLocalCache) Get(ctx context.Context) ([]domain.Article, error) {
-	//这里有并发问题，但影响不大
-	ddl := r.ddl.Load()
-	arts := r.topN.Load()
-	if len(arts) == 0 || ddl.Before(time.Now()) {
-		return nil, errors.New("本地缓存失效了")
-	}
-	return arts, nil
-}
-
-// ForceGet 不考虑过期时间，强制获取
-func (r *RankingLocalCache) ForceGet(ctx context.Context) ([]domain.Article, error) {
-	arts := r.topN.Load()
-	if len(arts) == 0 {
-		return nil, errors.New("本地缓存失效了")
-	}
-	return arts, nil
-}
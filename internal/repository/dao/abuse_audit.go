@@ -0,0 +1,36 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AbuseAuditDAO interface {
+	Insert(ctx context.Context, a AbuseAudit) error
+}
+
+type GORMAbuseAuditDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMAbuseAuditDAO(db *gorm.DB) AbuseAuditDAO {
+	return &GORMAbuseAuditDAO{db: db}
+}
+
+func (dao *GORMAbuseAuditDAO) Insert(ctx context.Context, a AbuseAudit) error {
+	a.Ctime = time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Create(&a).Error
+}
+
+// AbuseAudit 可疑操作的审计记录，只增不改
+type AbuseAudit struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"index:uid_ctime"`
+	// read 或者 like
+	Action string `gorm:"type:varchar(32)"`
+	// 触发限流的那一次操作的资源
+	Biz   string `gorm:"type:varchar(128)"`
+	BizId int64
+	Ctime int64 `gorm:"index:uid_ctime;index"`
+}
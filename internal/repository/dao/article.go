@@ -11,6 +11,7 @@ import (
 
 type ArticleDAO interface {
 	Insert(ctx context.Context, art Article) (int64, error)
+	// Update art.Version 是作者修改之前看到的版本号，和制作库对不上的时候返回 ArticleVersionConflictError
 	Update(ctx context.Context, art Article) error
 	FindById(ctx context.Context, id int64) (Article, error)
 	Sync(ctx context.Context, art Article) (int64, error)
@@ -20,8 +21,68 @@ type ArticleDAO interface {
 	GetById(ctx context.Context, id int64) (Article, error)
 	GetPubById(ctx context.Context, id int64) (PublishArticle, error)
 	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishArticle, error)
+	// GetByAuthorAfter 按照 utime、id 倒序，从 cur 之后开始取作者的帖子
+	GetByAuthorAfter(ctx context.Context, uid int64, cur ArticleCursor, limit int) ([]Article, error)
+	// ListPubAfter 按照 utime、id 倒序，从 cur 之后开始取已发表的帖子
+	ListPubAfter(ctx context.Context, cur ArticleCursor, limit int) ([]PublishArticle, error)
+	// ListPubByAuthor 作者已发表的帖子，仅自己可见的不算，翻页方式和 ListPubAfter 一样
+	ListPubByAuthor(ctx context.Context, authorId int64, cur ArticleCursor, limit int) ([]PublishArticle, error)
+	// CountPubByAuthor 作者已发表的帖子数量
+	CountPubByAuthor(ctx context.Context, authorId int64) (int64, error)
+	// ListPubByIds 只返回 ids 里面已发表的帖子，没有内容，有标签，顺序不确定
+	ListPubByIds(ctx context.Context, ids []int64) ([]PublishArticle, error)
+
+	ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error)
+	GetRevision(ctx context.Context, id int64) (ArticleRevision, error)
+
+	// ListScheduled 找出已经到了发表时间的定时发表帖子
+	ListScheduled(ctx context.Context, now time.Time, limit int) ([]Article, error)
+	// CancelSchedule 取消定时发表，帖子回到未发表状态
+	CancelSchedule(ctx context.Context, artId int64, authorId int64) error
+	// PublishScheduled 发表到期的定时帖子，返回发表之后的帖子
+	// art 是 ListScheduled 查出来的帖子加上渲染的结果，制作库的 utime 变了说明帖子被改过，不会发表
+	PublishScheduled(ctx context.Context, art Article) (Article, error)
+
+	// ListPubByTag 获取某个标签下已发表的帖子
+	ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]PublishArticle, error)
+	// CountPubTags 统计标签下已发表的帖子数量，数量多的在前面
+	CountPubTags(ctx context.Context, limit int) ([]TagCount, error)
+
+	// Delete 把帖子放进回收站，线上库的帖子同时变成仅自己可见
+	Delete(ctx context.Context, artId int64, authorId int64) error
+	// Restore 从回收站恢复，发表过的帖子恢复之后是仅自己可见，要重新发表
+	Restore(ctx context.Context, artId int64, authorId int64) error
+	// ListTrash 作者回收站里面的帖子，最近删除的在前面
+	ListTrash(ctx context.Context, authorId int64, offset int, limit int) ([]Article, error)
+	// ListExpiredTrash 在 before 之前放进回收站的帖子
+	ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]Article, error)
+	// Purge 彻底删除回收站里面的帖子，连同线上库、标签和历史版本
+	// 已经恢复了的帖子返回 ErrArticleNotInTrash
+	Purge(ctx context.Context, artId int64) error
+}
+
+var (
+	ErrArticleNotScheduled = errors.New("帖子不是定时发表状态或者还没到发表时间")
+	// ErrPubArticleNotFound 线上库没有这篇帖子，或者不是已发表状态
+	ErrPubArticleNotFound = errors.New("帖子不存在或者不可见")
+)
+
+// ArticleVersionConflictError 别人已经改过这篇帖子了，Current 是制作库当前的版本号
+type ArticleVersionConflictError struct {
+	Current int64
+}
+
+func (e ArticleVersionConflictError) Error() string {
+	return fmt.Sprintf("帖子已经被修改过，当前版本 %d", e.Current)
 }
 
+const (
+	articleStatusUnpublished = 1
+	articleStatusPublished   = 2
+	articleStatusPrivate     = 3
+	articleStatusScheduled   = 4
+)
+
 type GROMArticleDAO struct {
 	db *gorm.DB
 }
@@ -36,11 +97,15 @@ func NewGROMArticleDAO(db *gorm.DB) ArticleDAO {
 func (a *GROMArticleDAO) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishArticle, error) {
 	var res []PublishArticle
 	const ArticleStatusPublished = 2
-	err := a.db.WithContext(ctx).
+	db := a.db.WithContext(ctx)
+	err := db.
 		Where("utime < ? AND status = ?", start.UnixMilli(), ArticleStatusPublished).
 		Order("utime DESC").Offset(offset).Limit(limit).
 		Find(&res).Error
-	return res, err
+	if err != nil {
+		return nil, err
+	}
+	return res, fillPubTags(db, res)
 }
 
 // SyncStatus 更新帖子状态
@@ -64,7 +129,7 @@ func (g *GROMArticleDAO) SyncStatus(ctx context.Context, artId int64, authorId i
 			return fmt.Errorf("可能有人修改其他人文章，id:%d, authorId:%d", artId, authorId)
 		}
 		//更新线上库
-		return tx.Model(&Article{}).
+		return tx.Model(&PublishArticle{}).
 			Where("id=?", artId).
 			Updates(map[string]any{
 				"status": status,
@@ -74,9 +139,20 @@ func (g *GROMArticleDAO) SyncStatus(ctx context.Context, artId int64, authorId i
 }
 
 func (g *GROMArticleDAO) Sync(ctx context.Context, art Article) (int64, error) {
+	return g.sync(ctx, art, upsertGORM)
+}
+
+// pubUpsertFunc 在事务里面写线上库，ArticleS3DAO 用它把内容写到对象存储
+type pubUpsertFunc func(ctx context.Context, tx *gorm.DB, art PublishArticle) error
+
+func upsertGORM(ctx context.Context, tx *gorm.DB, art PublishArticle) error {
+	return NewGROMArticleDAO(tx).Upsert(ctx, art)
+}
+
+func (g *GROMArticleDAO) sync(ctx context.Context, art Article, upsert pubUpsertFunc) (int64, error) {
 	//在事务内部，采用了闭包形式
 	var (
-		id  int64
+		id  = art.Id
 		err error
 	)
 	err = g.db.Transaction(func(tx *gorm.DB) error {
@@ -90,108 +166,288 @@ func (g *GROMArticleDAO) Sync(ctx context.Context, art Article) (int64, error) {
 		if err != nil {
 			return err
 		}
-		//操作线上库
-		err = txDAO.Upsert(ctx, PublishArticle(art))
-		return err
+		//操作线上库，新建的帖子要用制作库的id
+		art.Id = id
+		return upsert(ctx, tx, PublishArticle(art))
 	})
 	return id, err
 }
 
-// Upsert 插入或修改线上库
+// Upsert 插入或修改线上库，同时覆盖线上库的标签
 func (g *GROMArticleDAO) Upsert(ctx context.Context, art PublishArticle) error {
 	now := time.Now().UnixMilli()
 	art.Ctime = now
 	art.Utime = now
-	//执行insert xxx on duplicate key update xxx
-	return g.db.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "id"}},
-		DoUpdates: clause.Assignments(map[string]interface{}{
-			"title":   art.Title,
-			"content": art.Content,
-			"status":  art.Status,
-			"utime":   art.Utime,
-		}),
-	}).Create(&art).Error
-	return nil
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		//执行insert xxx on duplicate key update xxx
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"title":        art.Title,
+				"content":      art.Content,
+				"status":       art.Status,
+				"category":     art.Category,
+				"html":         art.HTML,
+				"abstract":     art.Abstract,
+				"word_cnt":     art.WordCnt,
+				"reading_time": art.ReadingTime,
+				"utime":        art.Utime,
+			}),
+		}).Create(&art).Error
+		if err != nil {
+			return err
+		}
+		return replaceTags[PublishArticleTag](tx, art.Id, art.Tags)
+	})
 }
 
-// Insert 插入制作库
+// Insert 插入制作库，同时记录一个历史版本
 func (g *GROMArticleDAO) Insert(ctx context.Context, art Article) (int64, error) {
 	now := time.Now().UnixMilli()
 	art.Ctime = now
 	art.Utime = now
-	err := g.db.WithContext(ctx).Create(&art).Error
+	art.Version = 1
+	// 渲染结果只放线上库
+	setRendered(&art, Article{})
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&art).Error
+		if err != nil {
+			return err
+		}
+		err = replaceTags[ArticleTag](tx, art.Id, art.Tags)
+		if err != nil {
+			return err
+		}
+		rev := newArticleRevision(art)
+		return tx.Create(&rev).Error
+	})
 	return art.Id, err
 }
 
-// Update 更新制作库
+// Update 更新制作库，同时记录一个历史版本
 func (g *GROMArticleDAO) Update(ctx context.Context, art Article) error {
 	now := time.Now().UnixMilli()
 	art.Utime = now
-	//直接指定要更新的具体字段
-	res := g.db.WithContext(ctx).Model(&art).
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		//直接指定要更新的具体字段
+		res := tx.Model(&Article{}).
+			Where("id=? and author_id=? and version=? and deleted_at = 0",
+				art.Id, art.AuthorId, art.Version).
+			Updates(map[string]any{
+				"title":      art.Title,
+				"content":    art.Content,
+				"status":     art.Status,
+				"category":   art.Category,
+				"publish_at": art.PublishAt,
+				"version":    gorm.Expr("version + 1"),
+				"utime":      art.Utime,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		//检查是否真的更新了，要返回一个err
+		if res.RowsAffected == 0 {
+			return g.updateFailed(tx, art)
+		}
+		err := replaceTags[ArticleTag](tx, art.Id, art.Tags)
+		if err != nil {
+			return err
+		}
+		rev := newArticleRevision(art)
+		return tx.Create(&rev).Error
+	})
+}
+
+// updateFailed 区分是版本号对不上、放进了回收站，还是 id 或者作者不对
+func (g *GROMArticleDAO) updateFailed(tx *gorm.DB, art Article) error {
+	var cur Article
+	err := tx.Select("version", "deleted_at").
 		Where("id=? and author_id=?", art.Id, art.AuthorId).
+		First(&cur).Error
+	switch {
+	case err == nil && cur.DeletedAt > 0:
+		return ErrArticleInTrash
+	case err == nil:
+		return ArticleVersionConflictError{Current: cur.Version}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errors.New("更新失败，可能是创作者非法")
+	default:
+		return err
+	}
+}
+
+func (g *GROMArticleDAO) ListScheduled(ctx context.Context, now time.Time, limit int) ([]Article, error) {
+	var res []Article
+	err := g.db.WithContext(ctx).
+		Where("status = ? AND publish_at <= ?", articleStatusScheduled, now.UnixMilli()).
+		Order("publish_at ASC").Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GROMArticleDAO) CancelSchedule(ctx context.Context, artId int64, authorId int64) error {
+	now := time.Now().UnixMilli()
+	res := g.db.WithContext(ctx).Model(&Article{}).
+		Where("id = ? AND author_id = ? AND status = ?", artId, authorId, articleStatusScheduled).
 		Updates(map[string]any{
-			"title":   art.Title,
-			"content": art.Content,
-			"status":  art.Status,
-			"utime":   art.Utime,
+			"status":     articleStatusUnpublished,
+			"publish_at": 0,
+			"utime":      now,
 		})
 	if res.Error != nil {
 		return res.Error
 	}
-	//检查是否真的更新了，要返回一个err
 	if res.RowsAffected == 0 {
-		return errors.New("更新失败，可能是创作者非法")
+		return ErrArticleNotScheduled
 	}
 	return nil
 }
 
+// PublishScheduled 用状态做条件更新制作库，和取消定时发表并发的时候，
+// 已经取消的帖子不会被发表出去
+func (g *GROMArticleDAO) PublishScheduled(ctx context.Context, rendered Article) (Article, error) {
+	return g.publishScheduled(ctx, rendered, upsertGORM)
+}
+
+func (g *GROMArticleDAO) publishScheduled(ctx context.Context, rendered Article, upsert pubUpsertFunc) (Article, error) {
+	var art Article
+	artId := rendered.Id
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		res := tx.Model(&Article{}).
+			Where("id = ? AND status = ? AND publish_at <= ? AND utime = ?",
+				artId, articleStatusScheduled, now, rendered.Utime).
+			Updates(map[string]any{
+				"status":     articleStatusPublished,
+				"publish_at": 0,
+				"utime":      now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrArticleNotScheduled
+		}
+		err := tx.Where("id = ?", artId).First(&art).Error
+		if err != nil {
+			return err
+		}
+		art.Tags, err = findTagsOf[ArticleTag](tx, artId)
+		if err != nil {
+			return err
+		}
+		setRendered(&art, rendered)
+		//操作线上库
+		err = upsert(ctx, tx, PublishArticle(art))
+		if err != nil {
+			return err
+		}
+		rev := newArticleRevision(art)
+		return tx.Create(&rev).Error
+	})
+	return art, err
+}
+
 func (g *GROMArticleDAO) FindById(ctx context.Context, id int64) (Article, error) {
 	var article Article
-	err := g.db.WithContext(ctx).Where("id=?", id).First(&article).Error
+	db := g.db.WithContext(ctx)
+	err := db.Where("id=?", id).First(&article).Error
+	if err != nil {
+		return Article{}, err
+	}
+	article.Tags, err = findTagsOf[ArticleTag](db, id)
 	return article, err
 }
 
+// GetPubById 只返回已发表的帖子，撤回了或者放进回收站的返回 ErrPubArticleNotFound
 func (a *GROMArticleDAO) GetPubById(ctx context.Context, id int64) (PublishArticle, error) {
 	var res PublishArticle
-	err := a.db.WithContext(ctx).
-		Where("id = ?", id).
+	db := a.db.WithContext(ctx)
+	err := db.
+		Where("id = ? AND status = ?", id, articleStatusPublished).
 		First(&res).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return PublishArticle{}, ErrPubArticleNotFound
+	}
+	if err != nil {
+		return PublishArticle{}, err
+	}
+	res.Tags, err = findTagsOf[PublishArticleTag](db, id)
 	return res, err
 }
 
 func (a *GROMArticleDAO) GetById(ctx context.Context, id int64) (Article, error) {
 	var art Article
-	err := a.db.WithContext(ctx).
+	db := a.db.WithContext(ctx)
+	err := db.
 		Where("id = ?", id).First(&art).Error
+	if err != nil {
+		return Article{}, err
+	}
+	art.Tags, err = findTagsOf[ArticleTag](db, id)
 	return art, err
 }
 
 func (a *GROMArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
 	var arts []Article
-	err := a.db.WithContext(ctx).
-		Where("author_id = ?", uid).
+	db := a.db.WithContext(ctx)
+	err := db.
+		Where("author_id = ? AND deleted_at = 0", uid).
 		Offset(offset).Limit(limit).
 		// a ASC, B DESC
 		Order("utime DESC").
 		Find(&arts).Error
-	return arts, err
+	if err != nil {
+		return nil, err
+	}
+	return arts, fillTags(db, arts)
 }
 
+// Article 制作库和线上库（PublishArticle）共用一个结构体，所以两张表都会建下面的联合索引：
+// idx_author_deleted_utime 给作者游标翻页用，idx_status_utime 给线上库游标翻页用
 type Article struct {
-	Id    int64  `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
+	Id    int64  `gorm:"primaryKey,autoIncrement;index:idx_author_deleted_utime,priority:4;index:idx_status_utime,priority:3" bson:"id,omitempty"`
 	Title string `gorm:"type=varchar(4096)" bson:"title,omitempty"`
 	//内容为大文本数据
 	Content string `gorm:"type=BLOB" bson:"content,omitempty"`
-	Status  uint8  `bson:"status,omitempty"`
+	// 内容放在对象存储里面的时候，线上库的 Content 是空的，读者通过这个链接读取内容
+	ContentURL string `gorm:"-" bson:"-"`
+	Status     uint8  `gorm:"index:idx_status_utime,priority:1" bson:"status,omitempty"`
 	// 在作者id和创建时间上创建联合索引
 	//AuthorId int64 `gorm:"index=aid_ctime"`
 	//Ctime    int64 `gorm:"index=aid_ctime"`
 	//在作者id上创建索引
-	AuthorId int64 `gorm:"index" bson:"author_id,omitempty"`
-	Ctime    int64 `bson:"ctime,omitempty"`
-	Utime    int64 `bson:"utime,omitempty"`
+	AuthorId int64 `gorm:"index;index:idx_author_deleted_utime,priority:1" bson:"author_id,omitempty"`
+	// 分类，修改的时候可能清空，所以不能 omitempty
+	Category string `gorm:"type:varchar(64);index" bson:"category"`
+	// 标签在 MySQL 中存放在单独的关联表里，MongoDB 直接内嵌
+	Tags []string `gorm:"-" bson:"tags"`
+	// 定时发表的时间，毫秒数
+	PublishAt int64 `gorm:"index" bson:"publish_at,omitempty"`
+	// 下面几个是发表的时候由 Markdown 计算出来的，只有线上库会用到
+	// 过滤之后的 HTML
+	HTML string `gorm:"type=BLOB" bson:"html,omitempty"`
+	// 纯文本摘要
+	Abstract string `gorm:"type:varchar(1024)" bson:"abstract,omitempty"`
+	WordCnt  int64  `bson:"word_cnt,omitempty"`
+	// 预计阅读时间，秒数
+	ReadingTime int64 `bson:"reading_time,omitempty"`
+	// Version 制作库每次修改内容加一，用来发现两个人同时修改，线上库不用
+	// 老数据加列之后是 NULL 的话 version = ? 永远匹配不上，所以要 not null default 0
+	Version int64 `gorm:"not null;default:0" bson:"version"`
+	// DeletedAt 放进回收站的时间，毫秒数，0 表示没有删除，线上库不用
+	// 必须是 not null default 0，不然 AutoMigrate 加列之后老数据是 NULL，deleted_at = 0 查不出来
+	DeletedAt int64 `gorm:"not null;default:0;index;index:idx_author_deleted_utime,priority:2" bson:"deleted_at,omitempty"`
+	Ctime     int64 `bson:"ctime,omitempty"`
+	Utime     int64 `gorm:"index:idx_author_deleted_utime,priority:3;index:idx_status_utime,priority:2" bson:"utime,omitempty"`
 }
 
 type PublishArticle Article
+
+// setRendered 把渲染的结果复制过去
+func setRendered(dst *Article, src Article) {
+	dst.HTML = src.HTML
+	dst.Abstract = src.Abstract
+	dst.WordCnt = src.WordCnt
+	dst.ReadingTime = src.ReadingTime
+}
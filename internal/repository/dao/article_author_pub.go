@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (a *GROMArticleDAO) ListPubByAuthor(ctx context.Context, authorId int64, cur ArticleCursor, limit int) ([]PublishArticle, error) {
+	var res []PublishArticle
+	db := a.db.WithContext(ctx)
+	err := cur.where(db.Where("author_id = ? AND status = ?", authorId, articleStatusPublished)).
+		Order("utime DESC, id DESC").Limit(limit).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+	return res, fillPubTags(db, res)
+}
+
+func (a *GROMArticleDAO) CountPubByAuthor(ctx context.Context, authorId int64) (int64, error) {
+	var cnt int64
+	err := a.db.WithContext(ctx).Model(&PublishArticle{}).
+		Where("author_id = ? AND status = ?", authorId, articleStatusPublished).
+		Count(&cnt).Error
+	return cnt, err
+}
+
+func (m *MongoDBArticleDAO) ListPubByAuthor(ctx context.Context, authorId int64, cur ArticleCursor, limit int) ([]PublishArticle, error) {
+	filter := cur.filter(bson.D{
+		bson.E{Key: "author_id", Value: authorId},
+		bson.E{Key: "status", Value: articleStatusPublished},
+	})
+	opts := options.Find().SetSort(cursorSort).SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) CountPubByAuthor(ctx context.Context, authorId int64) (int64, error) {
+	return m.liveCol.CountDocuments(ctx, bson.D{
+		bson.E{Key: "author_id", Value: authorId},
+		bson.E{Key: "status", Value: articleStatusPublished},
+	})
+}
+
+// ListPubByIds 专栏的上一篇和下一篇只需要标题，不查内容
+// 热榜按照标签过滤，所以要带上标签
+func (a *GROMArticleDAO) ListPubByIds(ctx context.Context, ids []int64) ([]PublishArticle, error) {
+	var res []PublishArticle
+	if len(ids) == 0 {
+		return res, nil
+	}
+	db := a.db.WithContext(ctx)
+	err := db.
+		Select("id", "title", "author_id", "status", "ctime", "utime").
+		Where("id IN ? AND status = ?", ids, articleStatusPublished).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+	return res, fillPubTags(db, res)
+}
+
+func (m *MongoDBArticleDAO) ListPubByIds(ctx context.Context, ids []int64) ([]PublishArticle, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	filter := bson.D{
+		bson.E{Key: "id", Value: bson.M{"$in": ids}},
+		bson.E{Key: "status", Value: articleStatusPublished},
+	}
+	opts := options.Find().SetProjection(bson.D{
+		bson.E{Key: "id", Value: 1},
+		bson.E{Key: "title", Value: 1},
+		bson.E{Key: "author_id", Value: 1},
+		bson.E{Key: "status", Value: 1},
+		bson.E{Key: "tags", Value: 1},
+		bson.E{Key: "ctime", Value: 1},
+		bson.E{Key: "utime", Value: 1},
+	})
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
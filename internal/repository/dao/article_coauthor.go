@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var ErrCoAuthorNotFound = errors.New("合作者不存在或者状态不对")
+
+const (
+	coAuthorStatusPending  = 1
+	coAuthorStatusAccepted = 2
+	coAuthorStatusRevoked  = 3
+)
+
+// ArticleCoAuthorDAO 帖子的合作者，只存放被邀请的人，作者本人还是 Article.AuthorId
+type ArticleCoAuthorDAO interface {
+	// Invite 邀请合作者，之前撤销过的重新邀请会回到待接受状态
+	Invite(ctx context.Context, c ArticleCoAuthor) error
+	// Accept 接受邀请，只有待接受的邀请才能接受
+	Accept(ctx context.Context, artId int64, uid int64) error
+	// Revoke 撤销邀请或者退出合作
+	Revoke(ctx context.Context, artId int64, uid int64) error
+	// FindByUid 找不到的时候返回 ErrCoAuthorNotFound
+	FindByUid(ctx context.Context, artId int64, uid int64) (ArticleCoAuthor, error)
+	// ListByArticle 帖子的所有合作者，包括待接受和已经撤销的
+	ListByArticle(ctx context.Context, artId int64) ([]ArticleCoAuthor, error)
+	// DeleteByArticle 帖子被彻底删除之后清理所有合作者
+	DeleteByArticle(ctx context.Context, artId int64) error
+}
+
+type GORMArticleCoAuthorDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMArticleCoAuthorDAO(db *gorm.DB) ArticleCoAuthorDAO {
+	return &GORMArticleCoAuthorDAO{
+		db: db,
+	}
+}
+
+func (g *GORMArticleCoAuthorDAO) Invite(ctx context.Context, c ArticleCoAuthor) error {
+	now := time.Now().UnixMilli()
+	c.Status = coAuthorStatusPending
+	c.Ctime = now
+	c.Utime = now
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "article_id"}, {Name: "uid"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"role":    c.Role,
+			"status":  c.Status,
+			"inviter": c.Inviter,
+			"utime":   now,
+		}),
+	}).Create(&c).Error
+}
+
+func (g *GORMArticleCoAuthorDAO) Accept(ctx context.Context, artId int64, uid int64) error {
+	return g.updateStatus(ctx, artId, uid, []uint8{coAuthorStatusPending}, coAuthorStatusAccepted)
+}
+
+func (g *GORMArticleCoAuthorDAO) Revoke(ctx context.Context, artId int64, uid int64) error {
+	return g.updateStatus(ctx, artId, uid,
+		[]uint8{coAuthorStatusPending, coAuthorStatusAccepted}, coAuthorStatusRevoked)
+}
+
+// updateStatus 用原本的状态做条件更新，避免撤销之后又被接受
+func (g *GORMArticleCoAuthorDAO) updateStatus(ctx context.Context, artId int64, uid int64,
+	from []uint8, to uint8) error {
+	res := g.db.WithContext(ctx).Model(&ArticleCoAuthor{}).
+		Where("article_id = ? AND uid = ? AND status IN ?", artId, uid, from).
+		Updates(map[string]any{
+			"status": to,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrCoAuthorNotFound
+	}
+	return nil
+}
+
+func (g *GORMArticleCoAuthorDAO) FindByUid(ctx context.Context, artId int64, uid int64) (ArticleCoAuthor, error) {
+	var res ArticleCoAuthor
+	err := g.db.WithContext(ctx).
+		Where("article_id = ? AND uid = ?", artId, uid).
+		First(&res).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ArticleCoAuthor{}, ErrCoAuthorNotFound
+	}
+	return res, err
+}
+
+func (g *GORMArticleCoAuthorDAO) ListByArticle(ctx context.Context, artId int64) ([]ArticleCoAuthor, error) {
+	var res []ArticleCoAuthor
+	err := g.db.WithContext(ctx).
+		Where("article_id = ?", artId).
+		Order("id ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleCoAuthorDAO) DeleteByArticle(ctx context.Context, artId int64) error {
+	return g.db.WithContext(ctx).
+		Where("article_id = ?", artId).
+		Delete(&ArticleCoAuthor{}).Error
+}
+
+type ArticleCoAuthor struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 同一个人在一篇帖子上只有一条记录
+	ArticleId int64 `gorm:"uniqueIndex:aid_uid"`
+	// 查询某个人参与的帖子
+	Uid     int64 `gorm:"uniqueIndex:aid_uid;index"`
+	Role    uint8
+	Status  uint8
+	Inviter int64
+	Ctime   int64
+	Utime   int64
+}
@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"gorm.io/gorm"
+)
+
+// ArticleCursor 按照 utime、id 倒序翻页的位置，是上一页最后一篇帖子的 utime 和 id
+// Utime 为 0 的时候从头开始
+type ArticleCursor struct {
+	Utime int64
+	Id    int64
+}
+
+// where 翻页过程中插入或者修改了帖子，也不会重复或者漏掉游标之前的帖子
+func (c ArticleCursor) where(db *gorm.DB) *gorm.DB {
+	if c.Utime <= 0 {
+		return db
+	}
+	return db.Where("utime < ? OR (utime = ? AND id < ?)", c.Utime, c.Utime, c.Id)
+}
+
+func (c ArticleCursor) filter(filter bson.D) bson.D {
+	if c.Utime <= 0 {
+		return filter
+	}
+	return append(filter, bson.E{Key: "$or", Value: bson.A{
+		bson.M{"utime": bson.M{"$lt": c.Utime}},
+		bson.M{"utime": c.Utime, "id": bson.M{"$lt": c.Id}},
+	}})
+}
+
+var cursorSort = bson.D{bson.E{Key: "utime", Value: -1}, bson.E{Key: "id", Value: -1}}
+
+func (a *GROMArticleDAO) GetByAuthorAfter(ctx context.Context, uid int64, cur ArticleCursor, limit int) ([]Article, error) {
+	var arts []Article
+	db := a.db.WithContext(ctx)
+	err := cur.where(db.Where("author_id = ? AND deleted_at = 0", uid)).
+		Order("utime DESC, id DESC").Limit(limit).
+		Find(&arts).Error
+	if err != nil {
+		return nil, err
+	}
+	return arts, fillTags(db, arts)
+}
+
+func (a *GROMArticleDAO) ListPubAfter(ctx context.Context, cur ArticleCursor, limit int) ([]PublishArticle, error) {
+	var res []PublishArticle
+	db := a.db.WithContext(ctx)
+	err := cur.where(db.Where("status = ?", articleStatusPublished)).
+		Order("utime DESC, id DESC").Limit(limit).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+	return res, fillPubTags(db, res)
+}
+
+func (m *MongoDBArticleDAO) GetByAuthorAfter(ctx context.Context, uid int64, cur ArticleCursor, limit int) ([]Article, error) {
+	filter := cur.filter(bson.D{bson.E{Key: "author_id", Value: uid}, notDeleted})
+	opts := options.Find().SetSort(cursorSort).SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListPubAfter(ctx context.Context, cur ArticleCursor, limit int) ([]PublishArticle, error) {
+	filter := cur.filter(bson.D{bson.E{Key: "status", Value: articleStatusPublished}})
+	opts := options.Find().SetSort(cursorSort).SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
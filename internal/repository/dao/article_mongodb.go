@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/bwmarrin/snowflake"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -18,26 +19,55 @@ type MongoDBArticleDAO struct {
 	col *mongo.Collection
 	//线上库
 	liveCol *mongo.Collection
+	//历史版本
+	revCol *mongo.Collection
 }
 
+// ListPub 和 GROMArticleDAO 一样，按照更新时间倒序查询 start 之前发表的帖子，
+// 7 天的窗口由调用方控制
 func (m *MongoDBArticleDAO) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishArticle, error) {
-	//TODO implement me
-	panic("implement me")
+	filter := bson.D{bson.E{Key: "utime", Value: bson.M{"$lt": start.UnixMilli()}},
+		bson.E{Key: "status", Value: articleStatusPublished}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishArticle
+	err = cursor.All(ctx, &res)
+	return res, err
 }
 
 func (m *MongoDBArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
-	//TODO implement me
-	panic("implement me")
+	filter := bson.D{bson.E{Key: "author_id", Value: uid}, notDeleted}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
 }
 
 func (m *MongoDBArticleDAO) GetById(ctx context.Context, id int64) (Article, error) {
-	//TODO implement me
-	panic("implement me")
+	return m.FindById(ctx, id)
 }
 
 func (m *MongoDBArticleDAO) GetPubById(ctx context.Context, id int64) (PublishArticle, error) {
-	//TODO implement me
-	panic("implement me")
+	var res PublishArticle
+	err := m.liveCol.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id},
+		bson.E{Key: "status", Value: articleStatusPublished}}).Decode(&res)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return PublishArticle{}, ErrPubArticleNotFound
+	}
+	return res, err
 }
 
 var _ ArticleDAO = &MongoDBArticleDAO{}
@@ -47,17 +77,26 @@ func NewMongoDBArticleDAO(mdb *mongo.Database, node *snowflake.Node) ArticleDAO
 		node:    node,
 		liveCol: mdb.Collection("published_articles"),
 		col:     mdb.Collection("articles"),
+		revCol:  mdb.Collection("article_revisions"),
 	}
 }
 
 func (m *MongoDBArticleDAO) FindById(ctx context.Context, id int64) (Article, error) {
-	//TODO implement me
-	panic("implement me")
+	var res Article
+	err := m.col.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&res)
+	return res, err
 }
 
+// Upsert 线上库是 INSERT or Update 语义，ctime 只在插入的时候设置
 func (m *MongoDBArticleDAO) Upsert(ctx context.Context, art PublishArticle) error {
-	//TODO implement me
-	panic("implement me")
+	now := time.Now().UnixMilli()
+	art.Ctime = 0
+	art.Utime = now
+	_, err := m.liveCol.UpdateOne(ctx, bson.M{"id": art.Id}, bson.M{
+		"$set":         art,
+		"$setOnInsert": bson.M{"ctime": now},
+	}, options.Update().SetUpsert(true))
+	return err
 }
 
 // Insert 制作库保存帖子
@@ -67,29 +106,87 @@ func (m *MongoDBArticleDAO) Insert(ctx context.Context, art Article) (int64, err
 	art.Utime = now
 	//使用雪花算法生成主键，解决主键问题
 	art.Id = m.node.Generate().Int64()
+	art.Version = 1
+	// 渲染结果只放线上库
+	setRendered(&art, Article{})
 	_, err := m.col.InsertOne(ctx, &art)
-	return art.Id, err
+	if err != nil {
+		return 0, err
+	}
+	return art.Id, m.insertRevision(ctx, art)
 }
 
 func (m *MongoDBArticleDAO) Update(ctx context.Context, art Article) error {
 	now := time.Now().UnixMilli()
+	art.Utime = now
 	filter := bson.D{bson.E{"id", art.Id},
-		bson.E{"author_id", art.AuthorId}}
+		bson.E{"author_id", art.AuthorId},
+		versionIs(art.Version), notDeleted}
 	set := bson.D{bson.E{"$set", bson.M{
-		"title":   art.Title,
-		"content": art.Content,
-		"status":  art.Status,
-		"utime":   now,
-	}}}
+		"title":      art.Title,
+		"content":    art.Content,
+		"status":     art.Status,
+		"category":   art.Category,
+		"tags":       art.Tags,
+		"publish_at": art.PublishAt,
+		"utime":      now,
+	}}, bson.E{Key: "$inc", Value: bson.M{"version": 1}}}
 	res, err := m.col.UpdateOne(ctx, filter, set)
 	if err != nil {
 		return err
 	}
-	if res.ModifiedCount == 0 {
+	if res.MatchedCount == 0 {
+		return m.updateFailed(ctx, art)
+	}
+	return m.insertRevision(ctx, art)
+}
+
+// updateFailed 区分是版本号对不上、放进了回收站，还是 id 或者作者不对
+func (m *MongoDBArticleDAO) updateFailed(ctx context.Context, art Article) error {
+	var cur Article
+	err := m.col.FindOne(ctx, bson.D{bson.E{Key: "id", Value: art.Id},
+		bson.E{Key: "author_id", Value: art.AuthorId}}).Decode(&cur)
+	switch {
+	case err == nil && cur.DeletedAt > 0:
+		return ErrArticleInTrash
+	case err == nil:
+		return ArticleVersionConflictError{Current: cur.Version}
+	case errors.Is(err, mongo.ErrNoDocuments):
 		// 创作者不对，说明有人在瞎搞
 		return errors.New("ID 不对或者创作者不对")
+	default:
+		return err
 	}
-	return nil
+}
+
+// insertRevision 记录历史版本
+// 没有用 MongoDB 的事务，制作库更新成功但是历史版本写入失败的时候，会把错误返回给上层
+func (m *MongoDBArticleDAO) insertRevision(ctx context.Context, art Article) error {
+	rev := newArticleRevision(art)
+	rev.Id = m.node.Generate().Int64()
+	_, err := m.revCol.InsertOne(ctx, &rev)
+	return err
+}
+
+func (m *MongoDBArticleDAO) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error) {
+	filter := bson.D{bson.E{Key: "article_id", Value: artId}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "ctime", Value: -1}, bson.E{Key: "id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.revCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []ArticleRevision
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) GetRevision(ctx context.Context, id int64) (ArticleRevision, error) {
+	var res ArticleRevision
+	err := m.revCol.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&res)
+	return res, err
 }
 
 func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article) (int64, error) {
@@ -106,34 +203,235 @@ func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article) (int64, error
 	if err != nil {
 		return 0, err
 	}
-	//更新线上库
+	//更新线上库，新建的帖子要用制作库的id
 	art.Id = id
+	return id, m.Upsert(ctx, PublishArticle(art))
+}
+
+func (m *MongoDBArticleDAO) SyncStatus(ctx context.Context, artId int64, authorId int64, status int) error {
 	now := time.Now().UnixMilli()
-	art.Utime = now
-	// liveCol 是 INSERT or Update 语义
-	filter := bson.M{"id": art.Id}
-	updateV1 := bson.M{
-		"$set": PublishArticle(art),
-		//如果不存在就插入ctime
-		"$setOnInsert": bson.M{"ctime": now},
+	sets := bson.D{bson.E{Key: "$set", Value: bson.M{
+		"status": status,
+		"utime":  now,
+	}}}
+	res, err := m.col.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: artId},
+		bson.E{Key: "author_id", Value: authorId}}, sets)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount != 1 {
+		//要么Id错了，要么作者不对
+		return fmt.Errorf("可能有人修改其他人文章，id:%d, authorId:%d", artId, authorId)
+	}
+	_, err = m.liveCol.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: artId}}, sets)
+	return err
+}
+
+func (m *MongoDBArticleDAO) ListScheduled(ctx context.Context, now time.Time, limit int) ([]Article, error) {
+	filter := bson.D{bson.E{Key: "status", Value: articleStatusScheduled},
+		bson.E{Key: "publish_at", Value: bson.M{"$lte": now.UnixMilli()}}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "publish_at", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
 	}
-	_, err = m.liveCol.UpdateOne(ctx, filter, updateV1,
-		options.Update().SetUpsert(true))
-	return id, err
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
 }
 
-func (m *MongoDBArticleDAO) SyncStatus(ctx context.Context, uid int64, id int64, status int) error {
-	filter := bson.D{bson.E{Key: "id", Value: id},
-		bson.E{Key: "author_id", Value: uid}}
-	sets := bson.D{bson.E{Key: "$set",
-		Value: bson.D{bson.E{Key: "status", Value: status}}}}
+func (m *MongoDBArticleDAO) CancelSchedule(ctx context.Context, artId int64, authorId int64) error {
+	filter := bson.D{bson.E{Key: "id", Value: artId},
+		bson.E{Key: "author_id", Value: authorId},
+		bson.E{Key: "status", Value: articleStatusScheduled}}
+	sets := bson.D{bson.E{Key: "$set", Value: bson.M{
+		"status":     articleStatusUnpublished,
+		"publish_at": 0,
+		"utime":      time.Now().UnixMilli(),
+	}}}
 	res, err := m.col.UpdateOne(ctx, filter, sets)
 	if err != nil {
 		return err
 	}
-	if res.ModifiedCount != 1 {
-		return errors.New("ID 不对或者创作者不对")
+	if res.ModifiedCount == 0 {
+		return ErrArticleNotScheduled
+	}
+	return nil
+}
+
+func (m *MongoDBArticleDAO) PublishScheduled(ctx context.Context, rendered Article) (Article, error) {
+	now := time.Now().UnixMilli()
+	filter := bson.D{bson.E{Key: "id", Value: rendered.Id},
+		bson.E{Key: "status", Value: articleStatusScheduled},
+		bson.E{Key: "publish_at", Value: bson.M{"$lte": now}},
+		bson.E{Key: "utime", Value: rendered.Utime}}
+	sets := bson.D{bson.E{Key: "$set", Value: bson.M{
+		"status":     articleStatusPublished,
+		"publish_at": 0,
+		"utime":      now,
+	}}}
+	var art Article
+	err := m.col.FindOneAndUpdate(ctx, filter, sets,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).
+		Decode(&art)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Article{}, ErrArticleNotScheduled
+	}
+	if err != nil {
+		return Article{}, err
+	}
+	setRendered(&art, rendered)
+	err = m.Upsert(ctx, PublishArticle(art))
+	if err != nil {
+		return Article{}, err
+	}
+	return art, m.insertRevision(ctx, art)
+}
+
+// versionIs 加版本号之前写进去的文档没有 version，当成 0
+func versionIs(version int64) bson.E {
+	if version == 0 {
+		return bson.E{Key: "version", Value: bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.E{Key: "version", Value: version}
+}
+
+// notDeleted 没有放进回收站的帖子，deleted_at 是 omitempty 的，没删除的时候不存在
+var notDeleted = bson.E{Key: "deleted_at", Value: bson.M{"$not": bson.M{"$gt": 0}}}
+
+func (m *MongoDBArticleDAO) Delete(ctx context.Context, artId int64, authorId int64) error {
+	filter := bson.D{bson.E{Key: "id", Value: artId},
+		bson.E{Key: "author_id", Value: authorId}, notDeleted}
+	var art Article
+	err := m.col.FindOne(ctx, filter).Decode(&art)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("帖子不存在或者已经删除，id:%d, authorId:%d", artId, authorId)
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	// 带上原本的状态，避免和修改、发表互相覆盖
+	filter = append(filter, bson.E{Key: "status", Value: art.Status})
+	res, err := m.col.UpdateOne(ctx, filter, bson.D{bson.E{Key: "$set", Value: bson.M{
+		"status":     trashedStatus(art.Status),
+		"publish_at": 0,
+		"deleted_at": now,
+		"utime":      now,
+	}}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("帖子状态已经变了，id:%d, authorId:%d", artId, authorId)
+	}
+	_, err = m.liveCol.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: artId}},
+		bson.D{bson.E{Key: "$set", Value: bson.M{
+			"status": articleStatusPrivate,
+			"utime":  now,
+		}}})
+	return err
+}
+
+func (m *MongoDBArticleDAO) Restore(ctx context.Context, artId int64, authorId int64) error {
+	filter := bson.D{bson.E{Key: "id", Value: artId},
+		bson.E{Key: "author_id", Value: authorId},
+		bson.E{Key: "deleted_at", Value: bson.M{"$gt": 0}}}
+	res, err := m.col.UpdateOne(ctx, filter, bson.D{
+		bson.E{Key: "$set", Value: bson.M{"utime": time.Now().UnixMilli()}},
+		bson.E{Key: "$unset", Value: bson.M{"deleted_at": ""}}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrArticleNotInTrash
+	}
+	return nil
+}
+
+func (m *MongoDBArticleDAO) ListTrash(ctx context.Context, authorId int64, offset int, limit int) ([]Article, error) {
+	filter := bson.D{bson.E{Key: "author_id", Value: authorId},
+		bson.E{Key: "deleted_at", Value: bson.M{"$gt": 0}}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "deleted_at", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]Article, error) {
+	filter := bson.D{bson.E{Key: "deleted_at", Value: bson.M{"$gt": 0, "$lt": before.UnixMilli()}}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "deleted_at", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+// Purge 先删制作库，删掉了才说明帖子确实还在回收站里面
+// 后面的线上库和历史版本删除失败的话，返回错误，下一次调度不会再重试，只能手工处理
+func (m *MongoDBArticleDAO) Purge(ctx context.Context, artId int64) error {
+	res, err := m.col.DeleteOne(ctx, bson.D{bson.E{Key: "id", Value: artId},
+		bson.E{Key: "deleted_at", Value: bson.M{"$gt": 0}}})
+	if err != nil {
+		return err
 	}
-	_, err = m.liveCol.UpdateOne(ctx, filter, sets)
+	if res.DeletedCount == 0 {
+		return ErrArticleNotInTrash
+	}
+	_, err = m.liveCol.DeleteOne(ctx, bson.D{bson.E{Key: "id", Value: artId}})
+	if err != nil {
+		return err
+	}
+	_, err = m.revCol.DeleteMany(ctx, bson.D{bson.E{Key: "article_id", Value: artId}})
 	return err
 }
+
+func (m *MongoDBArticleDAO) ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]PublishArticle, error) {
+	filter := bson.D{bson.E{Key: "tags", Value: tag},
+		bson.E{Key: "status", Value: articleStatusPublished}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) CountPubTags(ctx context.Context, limit int) ([]TagCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "status", Value: articleStatusPublished}}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$tags"},
+			{Key: "cnt", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "cnt", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := m.liveCol.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var res []TagCount
+	err = cursor.All(ctx, &res)
+	return res, err
+}
@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"context"
+	"time"
+)
+
+// ListRevisions 按照时间倒序获取帖子的历史版本
+func (g *GROMArticleDAO) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error) {
+	var res []ArticleRevision
+	err := g.db.WithContext(ctx).
+		Where("article_id = ?", artId).
+		Order("ctime DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GROMArticleDAO) GetRevision(ctx context.Context, id int64) (ArticleRevision, error) {
+	var res ArticleRevision
+	err := g.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&res).Error
+	return res, err
+}
+
+// newArticleRevision 根据制作库最新的数据生成历史版本
+func newArticleRevision(art Article) ArticleRevision {
+	return ArticleRevision{
+		ArticleId: art.Id,
+		Title:     art.Title,
+		Content:   art.Content,
+		Status:    art.Status,
+		AuthorId:  art.AuthorId,
+		Ctime:     time.Now().UnixMilli(),
+	}
+}
+
+// ArticleRevision 制作库帖子的历史版本，只插入，不更新
+type ArticleRevision struct {
+	Id int64 `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
+	// 在帖子id和创建时间上创建联合索引
+	ArticleId int64  `gorm:"index:aid_ctime" bson:"article_id,omitempty"`
+	Title     string `gorm:"type=varchar(4096)" bson:"title,omitempty"`
+	Content   string `gorm:"type=BLOB" bson:"content,omitempty"`
+	Status    uint8  `bson:"status,omitempty"`
+	AuthorId  int64  `bson:"author_id,omitempty"`
+	Ctime     int64  `gorm:"index:aid_ctime" bson:"ctime,omitempty"`
+}
@@ -1,122 +1,152 @@
 package dao
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/ecodeclub/ekit"
+	"geektime/webook/pkg/ossx"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"strconv"
 	"time"
 )
 
+const articleContentType = "text/markdown;charset=utf-8"
+
+// ArticleS3DAO 线上库的内容放在对象存储里面，MySQL 的线上库只保留元数据，
+// 制作库和其它的查询都和 GROMArticleDAO 一样
 type ArticleS3DAO struct {
 	GROMArticleDAO
-	oss *s3.S3
+	store ossx.ObjectStore
+	// presign 大于 0 的时候 GetPubById 不返回内容，而是返回有效期为 presign 的下载链接
+	presign time.Duration
 }
 
-func NewArticleS3DAO(db *gorm.DB, oss *s3.S3) *ArticleS3DAO {
+func NewArticleS3DAO(db *gorm.DB, store ossx.ObjectStore, presign time.Duration) *ArticleS3DAO {
 	return &ArticleS3DAO{
 		GROMArticleDAO: GROMArticleDAO{db: db},
-		oss:            oss,
+		store:          store,
+		presign:        presign,
 	}
 }
 
-func (a *ArticleS3DAO) SyncStatus(ctx context.Context, uid int64, id int64, status uint8) error {
-	now := time.Now().UnixMilli()
-	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		res := tx.Model(&Article{}).
-			Where("id = ? and author_id = ?", uid, id).
-			Updates(map[string]any{
-				"utime":  now,
-				"status": status,
-			})
-		if res.Error != nil {
-			return res.Error
-		}
-		if res.RowsAffected != 1 {
-			return errors.New("ID 不对或者创作者不对")
-		}
-		return tx.Model(&PublishedArticleV2{}).
-			Where("id = ?", uid).
-			Updates(map[string]any{
-				"utime":  now,
-				"status": status,
-			}).Error
+func (a *ArticleS3DAO) Sync(ctx context.Context, art Article) (int64, error) {
+	return a.sync(ctx, art, a.upsert)
+}
+
+func (a *ArticleS3DAO) PublishScheduled(ctx context.Context, art Article) (Article, error) {
+	return a.publishScheduled(ctx, art, a.upsert)
+}
+
+func (a *ArticleS3DAO) Upsert(ctx context.Context, art PublishArticle) error {
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return a.upsert(ctx, tx, art)
 	})
+}
+
+// upsert 先在事务里面写线上库，行锁一直持有到事务提交，再写对象存储，写失败了事务会回滚
+// 这样 Backfill 和发表不会交替写对象存储，不会用旧内容覆盖新内容
+// 反过来事务提交失败的话，对象存储里面会多一份内容，下次发表会覆盖掉
+func (a *ArticleS3DAO) upsert(ctx context.Context, tx *gorm.DB, art PublishArticle) error {
+	content := art.Content
+	art.Content = ""
+	err := upsertGORM(ctx, tx, art)
 	if err != nil {
 		return err
 	}
-	const statusPrivate = 3
-	//如果帖子状态为不可见，就要将content从OSS中删除
-	if status == statusPrivate {
-		_, err = a.oss.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
-			Bucket: ekit.ToPtr[string]("webook-1314583317"),
-			Key:    ekit.ToPtr[string](strconv.FormatInt(id, 10)),
-		})
+	return a.store.Put(ctx, a.key(art.Id), []byte(content), articleContentType)
+}
+
+// Purge 数据库删掉之后再删对象存储里面的内容
+// 删除对象失败的话只会多一份没人用的内容，不影响帖子本身
+func (a *ArticleS3DAO) Purge(ctx context.Context, artId int64) error {
+	err := a.GROMArticleDAO.Purge(ctx, artId)
+	if err != nil {
+		return err
 	}
-	return err
+	return a.store.Delete(ctx, a.key(artId))
 }
 
-func (a *ArticleS3DAO) Sync(ctx context.Context, art Article) (int64, error) {
-	var id = art.Id
+// GetPubById 还没有搬到对象存储的帖子直接返回 MySQL 里面的内容
+func (a *ArticleS3DAO) GetPubById(ctx context.Context, id int64) (PublishArticle, error) {
+	art, err := a.GROMArticleDAO.GetPubById(ctx, id)
+	if err != nil || art.Content != "" {
+		return art, err
+	}
+	if a.presign > 0 {
+		art.ContentURL, err = a.store.PresignGet(ctx, a.key(id), a.presign)
+		return art, err
+	}
+	data, err := a.store.Get(ctx, a.key(id))
+	if errors.Is(err, ossx.ErrObjectNotFound) {
+		// 内容本来就是空的
+		return art, nil
+	}
+	art.Content = string(data)
+	return art, err
+}
+
+// Backfill 把线上库还留在 MySQL 里面的内容搬到对象存储，返回搬了多少篇
+// 每篇帖子一个事务，先 SELECT FOR UPDATE 锁住再写对象存储，和发表互斥
+func (a *ArticleS3DAO) Backfill(ctx context.Context, batchSize int) (int, error) {
+	var (
+		cnt   int
+		maxId int64
+	)
+	db := a.db.WithContext(ctx)
+	for {
+		var ids []int64
+		err := db.Model(&PublishArticle{}).
+			Where("id > ? AND content <> ''", maxId).
+			Order("id ASC").Limit(batchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return cnt, err
+		}
+		for _, id := range ids {
+			moved, err := a.backfillOne(ctx, id)
+			if err != nil {
+				return cnt, err
+			}
+			if moved {
+				cnt++
+			}
+		}
+		if len(ids) < batchSize {
+			return cnt, nil
+		}
+		maxId = ids[len(ids)-1]
+	}
+}
+
+// backfillOne 锁住之后内容已经空了，说明期间发表过，内容已经在对象存储里面了，直接跳过
+func (a *ArticleS3DAO) backfillOne(ctx context.Context, id int64) (bool, error) {
+	moved := false
 	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		var (
-			err error
-		)
-		dao := NewGROMArticleDAO(tx)
-		if id > 0 {
-			err = dao.Update(ctx, art)
-		} else {
-			id, err = dao.Insert(ctx, art)
+		var art PublishArticle
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND content <> ''", id).
+			First(&art).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
 		}
 		if err != nil {
 			return err
 		}
-		art.Id = id
-		now := time.Now().UnixMilli()
-		pubArt := PublishedArticleV2{
-			Id:       art.Id,
-			Title:    art.Title,
-			AuthorId: art.AuthorId,
-			Ctime:    now,
-			Utime:    now,
-			Status:   art.Status,
+		err = a.store.Put(ctx, a.key(id), []byte(art.Content), articleContentType)
+		if err != nil {
+			return err
 		}
-		pubArt.Ctime = now
-		pubArt.Utime = now
-		err = tx.Clauses(clause.OnConflict{
-			Columns: []clause.Column{{Name: "id"}},
-			DoUpdates: clause.Assignments(map[string]interface{}{
-				"title":  pubArt.Title,
-				"utime":  now,
-				"status": pubArt.Status,
-			}),
-		}).Create(&pubArt).Error
-		return err
-	})
-	if err != nil {
-		return 0, err
-	}
-	_, err = a.oss.PutObjectWithContext(ctx, &s3.PutObjectInput{
-		Bucket:      ekit.ToPtr[string]("webook-1314583317"),
-		Key:         ekit.ToPtr[string](strconv.FormatInt(art.Id, 10)),
-		Body:        bytes.NewReader([]byte(art.Content)),
-		ContentType: ekit.ToPtr[string]("text/plain;charset=utf-8"),
+		err = tx.Model(&PublishArticle{}).Where("id = ?", id).
+			Update("content", "").Error
+		if err != nil {
+			return err
+		}
+		moved = true
+		return nil
 	})
-	return id, err
+	return moved, err
 }
 
-// PublishedArticleV2 没有content
-type PublishedArticleV2 struct {
-	Id    int64  `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
-	Title string `gorm:"type=varchar(4096)" bson:"title,omitempty"`
-	// 我要根据创作者ID来查询
-	AuthorId int64 `gorm:"index" bson:"author_id,omitempty"`
-	Status   uint8 `bson:"status,omitempty"`
-	Ctime    int64 `bson:"ctime,omitempty"`
-	// 更新时间
-	Utime int64 `bson:"utime,omitempty"`
+func (a *ArticleS3DAO) key(id int64) string {
+	return strconv.FormatInt(id, 10)
 }
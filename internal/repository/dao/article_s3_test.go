@@ -3,14 +3,21 @@ package dao
 import (
 	"bytes"
 	"context"
+	"database/sql"
+	"geektime/webook/pkg/ossx"
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/ecodeclub/ekit"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
 	"io"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -55,3 +62,116 @@ func TestS3(t *testing.T) {
 	assert.NoError(t, err)
 	t.Log(string(data))
 }
+
+func TestArticleS3DAO_GetPubById(t *testing.T) {
+	testCases := []struct {
+		name string
+		// MySQL 线上库里面的内容
+		content string
+		// 对象存储里面的内容
+		before  func(t *testing.T, store ossx.ObjectStore)
+		presign time.Duration
+
+		wantContent string
+		wantURL     string
+	}{
+		{
+			name: "从对象存储读取内容",
+			before: func(t *testing.T, store ossx.ObjectStore) {
+				err := store.Put(context.Background(), "1", []byte("对象存储的内容"), articleContentType)
+				require.NoError(t, err)
+			},
+			wantContent: "对象存储的内容",
+		},
+		{
+			name: "返回预签名链接",
+			before: func(t *testing.T, store ossx.ObjectStore) {
+				err := store.Put(context.Background(), "1", []byte("对象存储的内容"), articleContentType)
+				require.NoError(t, err)
+			},
+			presign: time.Minute,
+			wantURL: "http://localhost/oss/1?expires=",
+		},
+		{
+			name:        "还没有搬到对象存储",
+			content:     "MySQL 的内容",
+			before:      func(t *testing.T, store ossx.ObjectStore) {},
+			wantContent: "MySQL 的内容",
+		},
+		{
+			name:   "对象存储没有内容",
+			before: func(t *testing.T, store ossx.ObjectStore) {},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			mock.ExpectQuery("SELECT .* FROM `publish_articles`.*").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "status"}).
+					AddRow(1, "标题", tc.content, 2))
+			mock.ExpectQuery("SELECT .* FROM `publish_article_tags`.*").
+				WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag"}))
+			store, err := ossx.NewLocalStore(t.TempDir(), "http://localhost/oss")
+			require.NoError(t, err)
+			tc.before(t, store)
+
+			dao := NewArticleS3DAO(openMockDB(t, sqlDB), store, tc.presign)
+			art, err := dao.GetPubById(context.Background(), 1)
+			require.NoError(t, err)
+			assert.Equal(t, "标题", art.Title)
+			assert.Equal(t, tc.wantContent, art.Content)
+			assert.True(t, strings.HasPrefix(art.ContentURL, tc.wantURL))
+			assert.Equal(t, tc.wantURL == "", art.ContentURL == "")
+		})
+	}
+}
+
+func TestArticleS3DAO_Backfill(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery("SELECT `id` FROM `publish_articles`.*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	// 1 还是老数据，搬到对象存储
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM `publish_articles` .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content"}).AddRow(1, "老内容"))
+	mock.ExpectExec("UPDATE `publish_articles` SET `content`=.*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// 2 在查出来之后又发表了，锁住的时候内容已经清空了，跳过
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM `publish_articles` .* FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content"}))
+	mock.ExpectCommit()
+
+	store, err := ossx.NewLocalStore(t.TempDir(), "http://localhost/oss")
+	require.NoError(t, err)
+	err = store.Put(context.Background(), "2", []byte("新内容"), articleContentType)
+	require.NoError(t, err)
+
+	dao := NewArticleS3DAO(openMockDB(t, sqlDB), store, 0)
+	cnt, err := dao.Backfill(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cnt)
+	data, err := store.Get(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "老内容", string(data))
+	data, err = store.Get(context.Background(), "2")
+	require.NoError(t, err)
+	assert.Equal(t, "新内容", string(data))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func openMockDB(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
@@ -0,0 +1,234 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	ErrSeriesNotFound = errors.New("专栏不存在")
+	// ErrArticleInOtherSeries 一篇帖子只能放在一个专栏里面
+	ErrArticleInOtherSeries = errors.New("帖子已经在专栏里面了")
+	// ErrIllegalSeriesOrder 排序的时候给的帖子和专栏里面的帖子对不上
+	ErrIllegalSeriesOrder = errors.New("专栏排序和专栏里面的帖子对不上")
+)
+
+// ArticleSeriesDAO 专栏和专栏里面帖子的顺序，和合作者一样只放在 MySQL
+type ArticleSeriesDAO interface {
+	Insert(ctx context.Context, s ArticleSeries) (int64, error)
+	// Update 只能修改自己的专栏，找不到的时候返回 ErrSeriesNotFound
+	Update(ctx context.Context, s ArticleSeries) error
+	// Delete 连同专栏里面的帖子关系一起删除，帖子本身不动
+	Delete(ctx context.Context, id int64, authorId int64) error
+	FindById(ctx context.Context, id int64) (ArticleSeries, error)
+	ListByAuthor(ctx context.Context, authorId int64, offset int, limit int) ([]ArticleSeries, error)
+
+	// ListItems 专栏里面的帖子，按照顺序排列
+	ListItems(ctx context.Context, seriesId int64) ([]ArticleSeriesItem, error)
+	// FindItemByArticle 帖子在哪个专栏里面，不在任何专栏里面的时候返回 ErrSeriesNotFound
+	FindItemByArticle(ctx context.Context, artId int64) (ArticleSeriesItem, error)
+	// AddItem 加到专栏的最后面，已经在专栏里面的帖子返回 ErrArticleInOtherSeries
+	AddItem(ctx context.Context, seriesId int64, artId int64) error
+	RemoveItem(ctx context.Context, seriesId int64, artId int64) error
+	// Reorder artIds 是专栏里面所有帖子的新顺序
+	Reorder(ctx context.Context, seriesId int64, artIds []int64) error
+	// DeleteByArticle 帖子被彻底删除之后从专栏里面移除
+	DeleteByArticle(ctx context.Context, artId int64) error
+}
+
+type GORMArticleSeriesDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMArticleSeriesDAO(db *gorm.DB) ArticleSeriesDAO {
+	return &GORMArticleSeriesDAO{
+		db: db,
+	}
+}
+
+func (g *GORMArticleSeriesDAO) Insert(ctx context.Context, s ArticleSeries) (int64, error) {
+	now := time.Now().UnixMilli()
+	s.Ctime = now
+	s.Utime = now
+	err := g.db.WithContext(ctx).Create(&s).Error
+	return s.Id, err
+}
+
+func (g *GORMArticleSeriesDAO) Update(ctx context.Context, s ArticleSeries) error {
+	res := g.db.WithContext(ctx).Model(&ArticleSeries{}).
+		Where("id = ? AND author_id = ?", s.Id, s.AuthorId).
+		Updates(map[string]any{
+			"title":       s.Title,
+			"description": s.Description,
+			"utime":       time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrSeriesNotFound
+	}
+	return nil
+}
+
+func (g *GORMArticleSeriesDAO) Delete(ctx context.Context, id int64, authorId int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND author_id = ?", id, authorId).Delete(&ArticleSeries{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrSeriesNotFound
+		}
+		return tx.Where("series_id = ?", id).Delete(&ArticleSeriesItem{}).Error
+	})
+}
+
+func (g *GORMArticleSeriesDAO) FindById(ctx context.Context, id int64) (ArticleSeries, error) {
+	var res ArticleSeries
+	err := g.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ArticleSeries{}, ErrSeriesNotFound
+	}
+	return res, err
+}
+
+func (g *GORMArticleSeriesDAO) ListByAuthor(ctx context.Context, authorId int64, offset int, limit int) ([]ArticleSeries, error) {
+	var res []ArticleSeries
+	err := g.db.WithContext(ctx).
+		Where("author_id = ?", authorId).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleSeriesDAO) ListItems(ctx context.Context, seriesId int64) ([]ArticleSeriesItem, error) {
+	var res []ArticleSeriesItem
+	err := g.db.WithContext(ctx).
+		Where("series_id = ?", seriesId).
+		Order("position ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleSeriesDAO) FindItemByArticle(ctx context.Context, artId int64) (ArticleSeriesItem, error) {
+	var res ArticleSeriesItem
+	err := g.db.WithContext(ctx).Where("article_id = ?", artId).First(&res).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ArticleSeriesItem{}, ErrSeriesNotFound
+	}
+	return res, err
+}
+
+// AddItem 先锁住专栏，避免并发加帖子的时候拿到同一个 Position
+// 这里不用 (series_id, position) 的唯一索引，因为 Reorder 是一条一条改 Position 的
+func (g *GORMArticleSeriesDAO) AddItem(ctx context.Context, seriesId int64, artId int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := g.lockSeries(tx, seriesId)
+		if err != nil {
+			return err
+		}
+		var pos int
+		err = tx.Model(&ArticleSeriesItem{}).
+			Where("series_id = ?", seriesId).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&pos).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&ArticleSeriesItem{
+			SeriesId:  seriesId,
+			ArticleId: artId,
+			Position:  pos + 1,
+			Ctime:     time.Now().UnixMilli(),
+		}).Error
+		if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == 1062 {
+			return ErrArticleInOtherSeries
+		}
+		return err
+	})
+}
+
+func (g *GORMArticleSeriesDAO) RemoveItem(ctx context.Context, seriesId int64, artId int64) error {
+	return g.db.WithContext(ctx).
+		Where("series_id = ? AND article_id = ?", seriesId, artId).
+		Delete(&ArticleSeriesItem{}).Error
+}
+
+// Reorder 锁住专栏之后比较专栏当前的帖子，避免排序的时候别的请求加了或者删了帖子
+func (g *GORMArticleSeriesDAO) Reorder(ctx context.Context, seriesId int64, artIds []int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := g.lockSeries(tx, seriesId)
+		if err != nil {
+			return err
+		}
+		var items []ArticleSeriesItem
+		err = tx.Where("series_id = ?", seriesId).Find(&items).Error
+		if err != nil {
+			return err
+		}
+		if len(items) != len(artIds) {
+			return ErrIllegalSeriesOrder
+		}
+		exists := make(map[int64]bool, len(items))
+		for _, item := range items {
+			exists[item.ArticleId] = true
+		}
+		for i, artId := range artIds {
+			if !exists[artId] {
+				return ErrIllegalSeriesOrder
+			}
+			// 重复出现的帖子第二次就找不到了
+			delete(exists, artId)
+			err = tx.Model(&ArticleSeriesItem{}).
+				Where("series_id = ? AND article_id = ?", seriesId, artId).
+				Update("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(&ArticleSeries{}).Where("id = ?", seriesId).
+			Update("utime", time.Now().UnixMilli()).Error
+	})
+}
+
+// lockSeries 加帖子和排序都要先锁住专栏这一行，专栏不存在的时候返回 ErrSeriesNotFound
+func (g *GORMArticleSeriesDAO) lockSeries(tx *gorm.DB, seriesId int64) error {
+	var s ArticleSeries
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").Where("id = ?", seriesId).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSeriesNotFound
+	}
+	return err
+}
+
+func (g *GORMArticleSeriesDAO) DeleteByArticle(ctx context.Context, artId int64) error {
+	return g.db.WithContext(ctx).
+		Where("article_id = ?", artId).
+		Delete(&ArticleSeriesItem{}).Error
+}
+
+type ArticleSeries struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	Title       string `gorm:"type:varchar(256)"`
+	Description string `gorm:"type:varchar(1024)"`
+	AuthorId    int64  `gorm:"index"`
+	Ctime       int64
+	Utime       int64
+}
+
+// ArticleSeriesItem 专栏里面的一篇帖子，Position 从 1 开始
+type ArticleSeriesItem struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	SeriesId int64 `gorm:"index:sid_pos"`
+	// 一篇帖子只能在一个专栏里面
+	ArticleId int64 `gorm:"uniqueIndex"`
+	Position  int   `gorm:"index:sid_pos"`
+	Ctime     int64
+}
@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGORMArticleSeriesDAO_AddItem(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+
+		wantErr error
+	}{
+		{
+			name: "锁住专栏之后加到最后面",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `id` FROM `article_series` .* FOR UPDATE").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				mock.ExpectQuery("SELECT COALESCE\\(MAX\\(position\\), 0\\) FROM `article_series_items`.*").
+					WillReturnRows(sqlmock.NewRows([]string{"pos"}).AddRow(2))
+				mock.ExpectExec("INSERT INTO `article_series_items`.*").
+					WithArgs(int64(10), int64(1), 3, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "专栏不存在",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `id` FROM `article_series` .* FOR UPDATE").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrSeriesNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			tc.mock(mock)
+			dao := NewGORMArticleSeriesDAO(openMockDB(t, sqlDB))
+			err = dao.AddItem(context.Background(), 10, 1)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ListPubByTag 按照更新时间倒序获取某个标签下已发表的帖子
+func (g *GROMArticleDAO) ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]PublishArticle, error) {
+	var res []PublishArticle
+	db := g.db.WithContext(ctx)
+	err := db.
+		Joins("JOIN publish_article_tags ON publish_article_tags.article_id = publish_articles.id").
+		Where("publish_article_tags.tag = ? AND publish_articles.status = ?", tag, articleStatusPublished).
+		Order("publish_articles.utime DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+	return res, fillPubTags(db, res)
+}
+
+// CountPubTags 统计每个标签下已发表的帖子数量，按照数量倒序
+func (g *GROMArticleDAO) CountPubTags(ctx context.Context, limit int) ([]TagCount, error) {
+	var res []TagCount
+	err := g.db.WithContext(ctx).Model(&PublishArticleTag{}).
+		Select("publish_article_tags.tag AS tag, COUNT(*) AS cnt").
+		Joins("JOIN publish_articles ON publish_articles.id = publish_article_tags.article_id").
+		Where("publish_articles.status = ?", articleStatusPublished).
+		Group("publish_article_tags.tag").
+		Order("cnt DESC").
+		Limit(limit).
+		Scan(&res).Error
+	return res, err
+}
+
+// replaceTags 用新的标签整个覆盖帖子原本的标签
+func replaceTags[T ArticleTag | PublishArticleTag](tx *gorm.DB, artId int64, tags []string) error {
+	err := tx.Where("article_id = ?", artId).Delete(new(T)).Error
+	if err != nil || len(tags) == 0 {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	rows := make([]T, 0, len(tags))
+	for _, tag := range tags {
+		rows = append(rows, T(ArticleTag{
+			ArticleId: artId,
+			Tag:       tag,
+			Ctime:     now,
+		}))
+	}
+	return tx.Create(&rows).Error
+}
+
+// findTags 批量查询帖子的标签，按照添加的顺序返回
+func findTags[T ArticleTag | PublishArticleTag](tx *gorm.DB, artIds []int64) (map[int64][]string, error) {
+	res := make(map[int64][]string, len(artIds))
+	if len(artIds) == 0 {
+		return res, nil
+	}
+	var rows []T
+	err := tx.Where("article_id IN ?", artIds).Order("id ASC").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		t := ArticleTag(row)
+		res[t.ArticleId] = append(res[t.ArticleId], t.Tag)
+	}
+	return res, nil
+}
+
+// findTagsOf 查询一篇帖子的标签
+func findTagsOf[T ArticleTag | PublishArticleTag](tx *gorm.DB, artId int64) ([]string, error) {
+	tags, err := findTags[T](tx, []int64{artId})
+	return tags[artId], err
+}
+
+// fillTags 查询制作库帖子的标签
+func fillTags(tx *gorm.DB, arts []Article) error {
+	ids := make([]int64, 0, len(arts))
+	for _, art := range arts {
+		ids = append(ids, art.Id)
+	}
+	tags, err := findTags[ArticleTag](tx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range arts {
+		arts[i].Tags = tags[arts[i].Id]
+	}
+	return nil
+}
+
+// fillPubTags 查询线上库帖子的标签
+func fillPubTags(tx *gorm.DB, arts []PublishArticle) error {
+	ids := make([]int64, 0, len(arts))
+	for _, art := range arts {
+		ids = append(ids, art.Id)
+	}
+	tags, err := findTags[PublishArticleTag](tx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range arts {
+		arts[i].Tags = tags[arts[i].Id]
+	}
+	return nil
+}
+
+// ArticleTag 制作库帖子和标签的关联
+type ArticleTag struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 同一篇帖子的标签不能重复
+	ArticleId int64 `gorm:"uniqueIndex:aid_tag"`
+	// 按照标签查询帖子
+	Tag   string `gorm:"type:varchar(64);uniqueIndex:aid_tag;index"`
+	Ctime int64
+}
+
+// PublishArticleTag 线上库帖子和标签的关联
+type PublishArticleTag ArticleTag
+
+// TagCount 某个标签下已发表的帖子数量
+type TagCount struct {
+	Tag string `bson:"_id"`
+	Cnt int64  `bson:"cnt"`
+}
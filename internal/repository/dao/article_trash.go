@@ -0,0 +1,115 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrArticleNotInTrash = errors.New("帖子不在回收站里面")
+	// ErrArticleInTrash 回收站里面的帖子不能修改、发表，要先恢复
+	ErrArticleInTrash = errors.New("帖子在回收站里面")
+)
+
+// trashedStatus 放进回收站之后制作库的状态
+// 发表过的帖子变成仅自己可见，定时发表的帖子取消定时，恢复之后都需要作者重新发表
+func trashedStatus(status uint8) uint8 {
+	switch status {
+	case articleStatusPublished, articleStatusPrivate:
+		return articleStatusPrivate
+	default:
+		return articleStatusUnpublished
+	}
+}
+
+func (g *GROMArticleDAO) Delete(ctx context.Context, artId int64, authorId int64) error {
+	now := time.Now().UnixMilli()
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var art Article
+		err := tx.Where("id = ? AND author_id = ? AND deleted_at = 0", artId, authorId).
+			First(&art).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("帖子不存在或者已经删除，id:%d, authorId:%d", artId, authorId)
+		}
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Article{}).Where("id = ?", artId).
+			Updates(map[string]any{
+				"status":     trashedStatus(art.Status),
+				"publish_at": 0,
+				"deleted_at": now,
+				"utime":      now,
+			}).Error
+		if err != nil {
+			return err
+		}
+		// 线上库可能没有
+		return tx.Model(&PublishArticle{}).Where("id = ?", artId).
+			Updates(map[string]any{
+				"status": articleStatusPrivate,
+				"utime":  now,
+			}).Error
+	})
+}
+
+func (g *GROMArticleDAO) Restore(ctx context.Context, artId int64, authorId int64) error {
+	res := g.db.WithContext(ctx).Model(&Article{}).
+		Where("id = ? AND author_id = ? AND deleted_at > 0", artId, authorId).
+		Updates(map[string]any{
+			"deleted_at": 0,
+			"utime":      time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrArticleNotInTrash
+	}
+	return nil
+}
+
+func (g *GROMArticleDAO) ListTrash(ctx context.Context, authorId int64, offset int, limit int) ([]Article, error) {
+	var arts []Article
+	db := g.db.WithContext(ctx)
+	err := db.Where("author_id = ? AND deleted_at > 0", authorId).
+		Order("deleted_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&arts).Error
+	if err != nil {
+		return nil, err
+	}
+	return arts, fillTags(db, arts)
+}
+
+func (g *GROMArticleDAO) ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]Article, error) {
+	var arts []Article
+	err := g.db.WithContext(ctx).
+		Where("deleted_at > 0 AND deleted_at < ?", before.UnixMilli()).
+		Order("deleted_at ASC").Limit(limit).
+		Find(&arts).Error
+	return arts, err
+}
+
+// Purge 用 deleted_at > 0 做条件删除制作库，避免删掉刚刚恢复的帖子
+func (g *GROMArticleDAO) Purge(ctx context.Context, artId int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND deleted_at > 0", artId).Delete(&Article{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrArticleNotInTrash
+		}
+		for _, entity := range []any{&ArticleTag{}, &PublishArticleTag{}, &ArticleRevision{}} {
+			err := tx.Where("article_id = ?", artId).Delete(entity).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Where("id = ?", artId).Delete(&PublishArticle{}).Error
+	})
+}
@@ -14,7 +14,15 @@ func InitTables(db *gorm.DB) error {
 		&User{},
 		&Article{},
 		&PublishArticle{},
+		&ArticleTag{},
+		&PublishArticleTag{},
+		&ArticleCoAuthor{},
+		&ArticleSeries{},
+		&ArticleSeriesItem{},
+		&ArticleRevision{},
 		&Job{},
+		&JobExecution{},
+		&AbuseAudit{},
 	)
 }
 
@@ -31,6 +39,18 @@ func InitCollection(mdb *mongo.Database) error {
 		{
 			Keys: bson.D{bson.E{"author_id", 1}},
 		},
+		// GetByAuthor 按照更新时间分页，游标翻页的时候还要按照 id 排序
+		{
+			Keys: bson.D{bson.E{"author_id", 1}, bson.E{"utime", -1}, bson.E{"id", -1}},
+		},
+		// ListScheduled 找到期的定时帖子
+		{
+			Keys: bson.D{bson.E{"status", 1}, bson.E{"publish_at", 1}},
+		},
+		// ListExpiredTrash 找回收站里面过期的帖子
+		{
+			Keys: bson.D{bson.E{"deleted_at", 1}},
+		},
 	})
 	if err != nil {
 		return err
@@ -42,8 +62,30 @@ func InitCollection(mdb *mongo.Database) error {
 			Keys:    bson.D{bson.E{"id", 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		// 作者主页按照更新时间倒序列出已发表的帖子
 		{
-			Keys: bson.D{bson.E{"author_id", 1}},
+			Keys: bson.D{bson.E{"author_id", 1}, bson.E{"status", 1}, bson.E{"utime", -1}, bson.E{"id", -1}},
+		},
+		{
+			Keys: bson.D{bson.E{"tags", 1}, bson.E{"utime", -1}},
+		},
+		// ListPub 按照更新时间倒序
+		{
+			Keys: bson.D{bson.E{"status", 1}, bson.E{"utime", -1}, bson.E{"id", -1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	//定义article_revisions索引
+	revCol := mdb.Collection("article_revisions")
+	_, err = revCol.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{"id", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{"article_id", 1}, bson.E{"ctime", -1}},
 		},
 	})
 	return err
@@ -2,15 +2,41 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 	"time"
 )
 
+var (
+	ErrJobDuplicate = errors.New("job 名字冲突")
+	ErrJobNotFound  = errors.New("job 不存在")
+	// ErrJobStatusConflict job 当前的状态不允许这个操作
+	ErrJobStatusConflict = errors.New("job 状态不对")
+	// ErrJobPreempted 租约过期，job 已经被别的节点抢占了
+	ErrJobPreempted = errors.New("job 已经被别的节点抢占")
+)
+
 type JobDAO interface {
-	Preempt(ctx context.Context) (Job, error)
-	Release(ctx context.Context, jid int64) error
-	UpdateUtime(ctx context.Context, id int64) error
-	UpdateNextTime(ctx context.Context, id int64, t time.Time) error
+	Insert(ctx context.Context, j Job) error
+	// Preempt 抢占等待调度的 job，或者续约已经超过 lease 的 job
+	Preempt(ctx context.Context, lease time.Duration) (Job, error)
+	// Release version 是抢占时拿到的版本号，下同
+	Release(ctx context.Context, jid int64, version int) error
+	UpdateUtime(ctx context.Context, id int64, version int) error
+	UpdateNextTime(ctx context.Context, id int64, version int, t time.Time) error
+
+	GetById(ctx context.Context, id int64) (Job, error)
+	List(ctx context.Context, offset, limit int) ([]Job, error)
+	Update(ctx context.Context, j Job) error
+	Delete(ctx context.Context, id int64) error
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64, nextTime time.Time) error
+	Trigger(ctx context.Context, id int64) error
+
+	InsertExecution(ctx context.Context, e JobExecution) (int64, error)
+	FinishExecution(ctx context.Context, e JobExecution) error
+	ListExecutions(ctx context.Context, jid int64, offset, limit int) ([]JobExecution, error)
 }
 
 type GORMJobDAO struct {
@@ -21,14 +47,36 @@ func NewGORMJobDAO(db *gorm.DB) JobDAO {
 	return &GORMJobDAO{db: db}
 }
 
-func (dao *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
+// Insert 新增 job，名字有唯一索引
+func (dao *GORMJobDAO) Insert(ctx context.Context, j Job) error {
+	now := time.Now().UnixMilli()
+	j.Ctime = now
+	j.Utime = now
+	err := dao.db.WithContext(ctx).Create(&j).Error
+	return dao.duplicateErr(err)
+}
+
+func (dao *GORMJobDAO) duplicateErr(err error) error {
+	if mysqlError, ok := err.(*mysql.MySQLError); ok {
+		//唯一索引键冲突码
+		const uniqueConflictsErrNo uint16 = 1062
+		if mysqlError.Number == uniqueConflictsErrNo {
+			return ErrJobDuplicate
+		}
+	}
+	return err
+}
+
+// Preempt 持有者会定时更新 utime 来续约，超过 lease 没有续约的认为持有者已经挂了，
+// 这种 job 也可以被抢占
+func (dao *GORMJobDAO) Preempt(ctx context.Context, lease time.Duration) (Job, error) {
 	db := dao.db.WithContext(ctx)
 	for {
 		var j Job
 		now := time.Now().UnixMilli()
-		// 作业：这里是缺少找到续约失败的 JOB 出来执行
-		err := db.Where("status = ? AND next_time <?",
-			jobStatusWaiting, now).
+		err := db.Where("(status = ? AND next_time < ?) OR (status = ? AND utime < ?)",
+			jobStatusWaiting, now,
+			jobStatusRunning, now-lease.Milliseconds()).
 			First(&j).Error
 		if err != nil {
 			return j, err
@@ -51,36 +99,194 @@ func (dao *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
 			// 没抢到，只能进入下一轮
 			continue
 		}
-		return j, err
+		// 后续的续约、释放都要用新的版本号
+		j.Status = jobStatusRunning
+		j.Version = j.Version + 1
+		j.Utime = now
+		return j, nil
 	}
 }
 
-func (dao *GORMJobDAO) Release(ctx context.Context, jid int64) error {
+// Release 只释放还在运行的 job，执行期间被暂停的 job 保持暂停。
+// 已经被别的节点抢走的 job 版本号对不上，什么也不会发生
+func (dao *GORMJobDAO) Release(ctx context.Context, jid int64, version int) error {
 	now := time.Now().UnixMilli()
 	return dao.db.WithContext(ctx).Model(&Job{}).
-		Where("id = ?", jid).Updates(map[string]any{
-		"status": jobStatusWaiting,
-		"utime":  now,
-	}).Error
+		Where("id = ? AND version = ? AND status = ?", jid, version, jobStatusRunning).
+		Updates(map[string]any{
+			"status": jobStatusWaiting,
+			"utime":  now,
+		}).Error
 }
 
-func (dao *GORMJobDAO) UpdateUtime(ctx context.Context, jid int64) error {
+func (dao *GORMJobDAO) UpdateUtime(ctx context.Context, jid int64, version int) error {
 	now := time.Now().UnixMilli()
-	return dao.db.WithContext(ctx).Model(&Job{}).
-		Where("id = ?", jid).Updates(map[string]any{
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND version = ?", jid, version).Updates(map[string]any{
 		"utime": now,
-	}).Error
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobPreempted
+	}
+	return nil
 }
 
-func (dao *GORMJobDAO) UpdateNextTime(ctx context.Context, jid int64, t time.Time) error {
+func (dao *GORMJobDAO) UpdateNextTime(ctx context.Context, jid int64, version int, t time.Time) error {
 	now := time.Now().UnixMilli()
-	return dao.db.WithContext(ctx).Model(&Job{}).
-		Where("id = ?", jid).Updates(map[string]any{
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND version = ?", jid, version).Updates(map[string]any{
 		"utime":     now,
 		"next_time": t.UnixMilli(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobPreempted
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) GetById(ctx context.Context, id int64) (Job, error) {
+	var j Job
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&j).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return j, ErrJobNotFound
+	}
+	return j, err
+}
+
+func (dao *GORMJobDAO) List(ctx context.Context, offset, limit int) ([]Job, error) {
+	var res []Job
+	err := dao.db.WithContext(ctx).Order("id").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+// Update 修改 job 的定义，不会动调度相关的状态
+// 正在运行的 job 不能修改，不然执行完之后 UpdateNextTime 会把新的 next_time 覆盖掉
+func (dao *GORMJobDAO) Update(ctx context.Context, j Job) error {
+	db := dao.db.WithContext(ctx)
+	res := db.Model(&Job{}).
+		Where("id = ? AND status <> ?", j.Id, jobStatusRunning).Updates(map[string]any{
+		"name":       j.Name,
+		"executor":   j.Executor,
+		"expression": j.Expression,
+		"cfg":        j.Cfg,
+		"next_time":  j.NextTime,
+		"utime":      time.Now().UnixMilli(),
+	})
+	if res.Error != nil {
+		return dao.duplicateErr(res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+	// 区分一下是不存在还是正在运行
+	var cnt int64
+	err := db.Model(&Job{}).Where("id = ?", j.Id).Count(&cnt).Error
+	if err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return ErrJobNotFound
+	}
+	return ErrJobStatusConflict
+}
+
+// Delete 执行记录和 job 一起删掉
+func (dao *GORMJobDAO) Delete(ctx context.Context, id int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ?", id).Delete(&Job{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrJobNotFound
+		}
+		return tx.Where("jid = ?", id).Delete(&JobExecution{}).Error
+	})
+}
+
+// Pause 正在运行的 job 也可以暂停，这一次执行完之后就不会再被调度了
+func (dao *GORMJobDAO) Pause(ctx context.Context, id int64) error {
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ?", id).Updates(map[string]any{
+		"status": jobStatusPaused,
+		"utime":  time.Now().UnixMilli(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
+// Resume 只恢复暂停了的 job，下一次调度的时间要重新计算
+func (dao *GORMJobDAO) Resume(ctx context.Context, id int64, nextTime time.Time) error {
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND status = ?", id, jobStatusPaused).Updates(map[string]any{
+		"status":    jobStatusWaiting,
+		"next_time": nextTime.UnixMilli(),
+		"utime":     time.Now().UnixMilli(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobStatusConflict
+	}
+	return nil
+}
+
+// Trigger 把下一次调度的时间提前到现在，只有等待中的 job 可以触发
+func (dao *GORMJobDAO) Trigger(ctx context.Context, id int64) error {
+	now := time.Now().UnixMilli()
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND status = ?", id, jobStatusWaiting).Updates(map[string]any{
+		"next_time": now,
+		"utime":     now,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobStatusConflict
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) InsertExecution(ctx context.Context, e JobExecution) (int64, error) {
+	now := time.Now().UnixMilli()
+	e.Ctime = now
+	e.Utime = now
+	err := dao.db.WithContext(ctx).Create(&e).Error
+	return e.Id, err
+}
+
+func (dao *GORMJobDAO) FinishExecution(ctx context.Context, e JobExecution) error {
+	return dao.db.WithContext(ctx).Model(&JobExecution{}).
+		Where("id = ?", e.Id).Updates(map[string]any{
+		"status":   e.Status,
+		"err":      e.Err,
+		"end_time": e.EndTime,
+		"utime":    time.Now().UnixMilli(),
 	}).Error
 }
 
+// ListExecutions 最近的执行记录在前面
+func (dao *GORMJobDAO) ListExecutions(ctx context.Context, jid int64, offset, limit int) ([]JobExecution, error) {
+	var res []JobExecution
+	err := dao.db.WithContext(ctx).Where("jid = ?", jid).
+		Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
 type Job struct {
 	Id         int64  `gorm:"primaryKey,autoIncrement"`
 	Name       string `gorm:"type:varchar(128);unique"`
@@ -107,3 +313,19 @@ const (
 	// jobStatusPaused 不再需要调度了
 	jobStatusPaused
 )
+
+// JobExecution job 的执行记录
+type JobExecution struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Jid int64 `gorm:"index"`
+	// Node 执行这一次的节点
+	Node   string `gorm:"type:varchar(128)"`
+	Status uint8
+	// Err 执行失败的原因
+	Err       string `gorm:"type:text"`
+	StartTime int64
+	EndTime   int64
+
+	Utime int64
+	Ctime int64
+}
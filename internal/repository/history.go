@@ -1,10 +0,0 @@
-package repository
-
-import (
-	"context"
-	"geektime/webook/internal/domain"
-)
-
-type HistoryRecordRepository interface {
-	AddRecord(ctx context.Context, record domain.HistoryRecord) error
-}
@@ -7,11 +7,34 @@ import (
 	"time"
 )
 
+var (
+	ErrJobDuplicate      = dao.ErrJobDuplicate
+	ErrJobNotFound       = dao.ErrJobNotFound
+	ErrJobStatusConflict = dao.ErrJobStatusConflict
+	ErrJobPreempted      = dao.ErrJobPreempted
+)
+
 type CronJobRepository interface {
-	Preempt(ctx context.Context) (domain.Job, error)
-	Release(ctx context.Context, jid int64) error
-	UpdateUtime(ctx context.Context, id int64) error
-	UpdateNextTime(ctx context.Context, id int64, time time.Time) error
+	AddJob(ctx context.Context, j domain.Job) error
+	// Preempt 超过 lease 没有续约的 job 也会被抢占
+	Preempt(ctx context.Context, lease time.Duration) (domain.Job, error)
+	// Release version 是抢占时拿到的 domain.Job.Version，下同
+	Release(ctx context.Context, jid int64, version int64) error
+	// UpdateUtime 续约，已经被别的节点抢走了会返回 ErrJobPreempted
+	UpdateUtime(ctx context.Context, id int64, version int64) error
+	UpdateNextTime(ctx context.Context, id int64, version int64, time time.Time) error
+
+	GetById(ctx context.Context, id int64) (domain.Job, error)
+	List(ctx context.Context, offset, limit int) ([]domain.Job, error)
+	Update(ctx context.Context, j domain.Job) error
+	Delete(ctx context.Context, id int64) error
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64, nextTime time.Time) error
+	Trigger(ctx context.Context, id int64) error
+
+	AddExecution(ctx context.Context, e domain.JobExecution) (int64, error)
+	FinishExecution(ctx context.Context, e domain.JobExecution) error
+	ListExecutions(ctx context.Context, jid int64, offset, limit int) ([]domain.JobExecution, error)
 }
 
 type PreemptJobRepository struct {
@@ -22,24 +45,134 @@ func NewPreemptJobRepository(dao dao.JobDAO) CronJobRepository {
 	return &PreemptJobRepository{dao: dao}
 }
 
-func (p *PreemptJobRepository) Preempt(ctx context.Context) (domain.Job, error) {
-	j, err := p.dao.Preempt(ctx)
-	return domain.Job{
-		Id:         j.Id,
-		Expression: j.Expression,
+func (p *PreemptJobRepository) AddJob(ctx context.Context, j domain.Job) error {
+	return p.dao.Insert(ctx, dao.Job{
+		Name:       j.Name,
 		Executor:   j.Executor,
+		Expression: j.Expression,
+		Cfg:        j.Cfg,
+		NextTime:   j.NextTime().UnixMilli(),
+	})
+}
+
+func (p *PreemptJobRepository) Preempt(ctx context.Context, lease time.Duration) (domain.Job, error) {
+	j, err := p.dao.Preempt(ctx, lease)
+	return p.toDomain(j), err
+}
+
+func (p *PreemptJobRepository) Release(ctx context.Context, jid int64, version int64) error {
+	return p.dao.Release(ctx, jid, int(version))
+}
+
+func (p *PreemptJobRepository) UpdateUtime(ctx context.Context, id int64, version int64) error {
+	return p.dao.UpdateUtime(ctx, id, int(version))
+}
+
+func (p *PreemptJobRepository) UpdateNextTime(ctx context.Context, id int64, version int64, time time.Time) error {
+	return p.dao.UpdateNextTime(ctx, id, int(version), time)
+}
+
+func (p *PreemptJobRepository) GetById(ctx context.Context, id int64) (domain.Job, error) {
+	j, err := p.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.Job{}, err
+	}
+	return p.toDomain(j), nil
+}
+
+func (p *PreemptJobRepository) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	jobs, err := p.dao.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Job, 0, len(jobs))
+	for _, j := range jobs {
+		res = append(res, p.toDomain(j))
+	}
+	return res, nil
+}
+
+func (p *PreemptJobRepository) Update(ctx context.Context, j domain.Job) error {
+	return p.dao.Update(ctx, dao.Job{
+		Id:         j.Id,
 		Name:       j.Name,
-	}, err
+		Executor:   j.Executor,
+		Expression: j.Expression,
+		Cfg:        j.Cfg,
+		NextTime:   j.NextTime().UnixMilli(),
+	})
+}
+
+func (p *PreemptJobRepository) Delete(ctx context.Context, id int64) error {
+	return p.dao.Delete(ctx, id)
 }
 
-func (p *PreemptJobRepository) Release(ctx context.Context, jid int64) error {
-	return p.dao.Release(ctx, jid)
+func (p *PreemptJobRepository) Pause(ctx context.Context, id int64) error {
+	return p.dao.Pause(ctx, id)
 }
 
-func (p *PreemptJobRepository) UpdateUtime(ctx context.Context, id int64) error {
-	return p.dao.UpdateUtime(ctx, id)
+func (p *PreemptJobRepository) Resume(ctx context.Context, id int64, nextTime time.Time) error {
+	return p.dao.Resume(ctx, id, nextTime)
 }
 
-func (p *PreemptJobRepository) UpdateNextTime(ctx context.Context, id int64, time time.Time) error {
-	return p.dao.UpdateNextTime(ctx, id, time)
+func (p *PreemptJobRepository) Trigger(ctx context.Context, id int64) error {
+	return p.dao.Trigger(ctx, id)
+}
+
+func (p *PreemptJobRepository) AddExecution(ctx context.Context, e domain.JobExecution) (int64, error) {
+	return p.dao.InsertExecution(ctx, dao.JobExecution{
+		Jid:       e.Jid,
+		Node:      e.Node,
+		Status:    e.Status.ToUint8(),
+		StartTime: e.StartTime.UnixMilli(),
+	})
+}
+
+func (p *PreemptJobRepository) FinishExecution(ctx context.Context, e domain.JobExecution) error {
+	return p.dao.FinishExecution(ctx, dao.JobExecution{
+		Id:      e.Id,
+		Status:  e.Status.ToUint8(),
+		Err:     e.Err,
+		EndTime: e.EndTime.UnixMilli(),
+	})
+}
+
+func (p *PreemptJobRepository) ListExecutions(ctx context.Context,
+	jid int64, offset, limit int) ([]domain.JobExecution, error) {
+	es, err := p.dao.ListExecutions(ctx, jid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.JobExecution, 0, len(es))
+	for _, e := range es {
+		de := domain.JobExecution{
+			Id:        e.Id,
+			Jid:       e.Jid,
+			Node:      e.Node,
+			Status:    domain.JobExecutionStatus(e.Status),
+			Err:       e.Err,
+			StartTime: time.UnixMilli(e.StartTime),
+		}
+		// 还没执行完的没有结束时间
+		if e.EndTime > 0 {
+			de.EndTime = time.UnixMilli(e.EndTime)
+		}
+		res = append(res, de)
+	}
+	return res, nil
+}
+
+func (p *PreemptJobRepository) toDomain(j dao.Job) domain.Job {
+	return domain.Job{
+		Id:           j.Id,
+		Name:         j.Name,
+		Expression:   j.Expression,
+		Executor:     j.Executor,
+		Cfg:          j.Cfg,
+		Status:       domain.JobStatus(j.Status),
+		Version:      int64(j.Version),
+		NextExecTime: time.UnixMilli(j.NextTime),
+		Ctime:        time.UnixMilli(j.Ctime),
+		Utime:        time.UnixMilli(j.Utime),
+	}
 }
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/anti_abuse.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/anti_abuse.go -package=repomocks -destination=./internal/repository/mocks/anti_abuse.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	domain "geektime/webook/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAntiAbuseRepository is a mock of AntiAbuseRepository interface.
+type MockAntiAbuseRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAntiAbuseRepositoryMockRecorder
+}
+
+// MockAntiAbuseRepositoryMockRecorder is the mock recorder for MockAntiAbuseRepository.
+type MockAntiAbuseRepositoryMockRecorder struct {
+	mock *MockAntiAbuseRepository
+}
+
+// NewMockAntiAbuseRepository creates a new mock instance.
+func NewMockAntiAbuseRepository(ctrl *gomock.Controller) *MockAntiAbuseRepository {
+	mock := &MockAntiAbuseRepository{ctrl: ctrl}
+	mock.recorder = &MockAntiAbuseRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAntiAbuseRepository) EXPECT() *MockAntiAbuseRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddAudit mocks base method.
+func (m *MockAntiAbuseRepository) AddAudit(ctx context.Context, a domain.AbuseAudit, window time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAudit", ctx, a, window)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAudit indicates an expected call of AddAudit.
+func (mr *MockAntiAbuseRepositoryMockRecorder) AddAudit(ctx, a, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAudit", reflect.TypeOf((*MockAntiAbuseRepository)(nil).AddAudit), ctx, a, window)
+}
+
+// FirstRead mocks base method.
+func (m *MockAntiAbuseRepository) FirstRead(ctx context.Context, uid int64, biz string, bizId int64, window time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FirstRead", ctx, uid, biz, bizId, window)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FirstRead indicates an expected call of FirstRead.
+func (mr *MockAntiAbuseRepositoryMockRecorder) FirstRead(ctx, uid, biz, bizId, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FirstRead", reflect.TypeOf((*MockAntiAbuseRepository)(nil).FirstRead), ctx, uid, biz, bizId, window)
+}
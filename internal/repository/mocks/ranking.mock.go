@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/ranking.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/ranking.go -package=repomocks -destination=./internal/repository/mocks/ranking.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	domain "geektime/webook/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRankingRepository is a mock of RankingRepository interface.
+type MockRankingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRankingRepositoryMockRecorder
+}
+
+// MockRankingRepositoryMockRecorder is the mock recorder for MockRankingRepository.
+type MockRankingRepositoryMockRecorder struct {
+	mock *MockRankingRepository
+}
+
+// NewMockRankingRepository creates a new mock instance.
+func NewMockRankingRepository(ctrl *gomock.Controller) *MockRankingRepository {
+	mock := &MockRankingRepository{ctrl: ctrl}
+	mock.recorder = &MockRankingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRankingRepository) EXPECT() *MockRankingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetBoard mocks base method.
+func (m *MockRankingRepository) GetBoard(ctx context.Context, board string, offset, limit int) ([]domain.RankingItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoard", ctx, board, offset, limit)
+	ret0, _ := ret[0].([]domain.RankingItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoard indicates an expected call of GetBoard.
+func (mr *MockRankingRepositoryMockRecorder) GetBoard(ctx, board, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoard", reflect.TypeOf((*MockRankingRepository)(nil).GetBoard), ctx, board, offset, limit)
+}
+
+// IncrBoard mocks base method.
+func (m *MockRankingRepository) IncrBoard(ctx context.Context, board string, halfLife time.Duration, deltas []domain.RankingDelta) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBoard", ctx, board, halfLife, deltas)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrBoard indicates an expected call of IncrBoard.
+func (mr *MockRankingRepositoryMockRecorder) IncrBoard(ctx, board, halfLife, deltas any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBoard", reflect.TypeOf((*MockRankingRepository)(nil).IncrBoard), ctx, board, halfLife, deltas)
+}
+
+// RebaseBoard mocks base method.
+func (m *MockRankingRepository) RebaseBoard(ctx context.Context, board string, halfLife time.Duration, n int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebaseBoard", ctx, board, halfLife, n)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebaseBoard indicates an expected call of RebaseBoard.
+func (mr *MockRankingRepositoryMockRecorder) RebaseBoard(ctx, board, halfLife, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebaseBoard", reflect.TypeOf((*MockRankingRepository)(nil).RebaseBoard), ctx, board, halfLife, n)
+}
+
+// ReplaceBoard mocks base method.
+func (m *MockRankingRepository) ReplaceBoard(ctx context.Context, board string, items []domain.RankingItem, base time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceBoard", ctx, board, items, base)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceBoard indicates an expected call of ReplaceBoard.
+func (mr *MockRankingRepositoryMockRecorder) ReplaceBoard(ctx, board, items, base any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceBoard", reflect.TypeOf((*MockRankingRepository)(nil).ReplaceBoard), ctx, board, items, base)
+}
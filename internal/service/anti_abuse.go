@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/repository"
+	"geektime/webook/pkg/logger"
+	"geektime/webook/pkg/ratelimit"
+	"time"
+)
+
+// AntiAbuseService 防刷，Redis 出问题的时候都放行，不影响正常用户
+type AntiAbuseService interface {
+	// CountRead 这次阅读要不要计数，窗口期内重复阅读和读得太快的都不算
+	CountRead(ctx context.Context, uid int64, biz string, bizId int64) bool
+	// AllowLike 点赞和取消点赞按照用户限流
+	AllowLike(ctx context.Context, uid int64, biz string, bizId int64) bool
+}
+
+type antiAbuseService struct {
+	repo        repository.AntiAbuseRepository
+	readLimiter ratelimit.Limiter
+	likeLimiter ratelimit.Limiter
+	readWindow  time.Duration
+	auditWindow time.Duration
+	l           logger.LoggerV1
+}
+
+func NewAntiAbuseService(repo repository.AntiAbuseRepository,
+	readLimiter ratelimit.Limiter, likeLimiter ratelimit.Limiter,
+	readWindow time.Duration, auditWindow time.Duration,
+	l logger.LoggerV1) AntiAbuseService {
+	return &antiAbuseService{
+		repo:        repo,
+		readLimiter: readLimiter,
+		likeLimiter: likeLimiter,
+		readWindow:  readWindow,
+		auditWindow: auditWindow,
+		l:           l,
+	}
+}
+
+func (a *antiAbuseService) CountRead(ctx context.Context, uid int64, biz string, bizId int64) bool {
+	first, err := a.repo.FirstRead(ctx, uid, biz, bizId, a.readWindow)
+	if err != nil {
+		a.l.Error("阅读去重失败",
+			logger.Int64("uid", uid),
+			logger.Int64("bizId", bizId),
+			logger.Error(err))
+		return true
+	}
+	// 刷新页面不算新的阅读，也不占限流的额度
+	if !first {
+		return false
+	}
+	return !a.limited(ctx, a.readLimiter, domain.AbuseAudit{
+		Uid: uid, Action: domain.AbuseActionRead, Biz: biz, BizId: bizId,
+	})
+}
+
+func (a *antiAbuseService) AllowLike(ctx context.Context, uid int64, biz string, bizId int64) bool {
+	return !a.limited(ctx, a.likeLimiter, domain.AbuseAudit{
+		Uid: uid, Action: domain.AbuseActionLike, Biz: biz, BizId: bizId,
+	})
+}
+
+// limited 触发限流的时候记一条审计
+func (a *antiAbuseService) limited(ctx context.Context,
+	limiter ratelimit.Limiter, audit domain.AbuseAudit) bool {
+	limited, err := limiter.Limit(ctx, fmt.Sprintf("abuse:limit:%s:%d", audit.Action, audit.Uid))
+	if err != nil {
+		a.l.Error("防刷限流失败",
+			logger.String("action", audit.Action),
+			logger.Int64("uid", audit.Uid),
+			logger.Error(err))
+		return false
+	}
+	if !limited {
+		return false
+	}
+	err = a.repo.AddAudit(ctx, audit, a.auditWindow)
+	if err != nil {
+		a.l.Error("记录可疑操作失败",
+			logger.String("action", audit.Action),
+			logger.Int64("uid", audit.Uid),
+			logger.Error(err))
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/repository"
+	repomocks "geektime/webook/internal/repository/mocks"
+	"geektime/webook/pkg/logger"
+	"geektime/webook/pkg/ratelimit"
+	limitmocks "geektime/webook/pkg/ratelimit/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestAntiAbuseService_CountRead(t *testing.T) {
+	const readWindow, auditWindow = time.Minute * 30, time.Minute * 10
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.AntiAbuseRepository, ratelimit.Limiter)
+
+		want bool
+	}{
+		{
+			name: "第一次阅读",
+			mock: func(ctrl *gomock.Controller) (repository.AntiAbuseRepository, ratelimit.Limiter) {
+				repo := repomocks.NewMockAntiAbuseRepository(ctrl)
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				repo.EXPECT().FirstRead(gomock.Any(), int64(123), domain.BizArticle, int64(1), readWindow).
+					Return(true, nil)
+				limiter.EXPECT().Limit(gomock.Any(), "abuse:limit:read:123").Return(false, nil)
+				return repo, limiter
+			},
+			want: true,
+		},
+		{
+			name: "窗口期内重复阅读",
+			mock: func(ctrl *gomock.Controller) (repository.AntiAbuseRepository, ratelimit.Limiter) {
+				repo := repomocks.NewMockAntiAbuseRepository(ctrl)
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				repo.EXPECT().FirstRead(gomock.Any(), int64(123), domain.BizArticle, int64(1), readWindow).
+					Return(false, nil)
+				return repo, limiter
+			},
+			want: false,
+		},
+		{
+			name: "读得太快，记审计",
+			mock: func(ctrl *gomock.Controller) (repository.AntiAbuseRepository, ratelimit.Limiter) {
+				repo := repomocks.NewMockAntiAbuseRepository(ctrl)
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				repo.EXPECT().FirstRead(gomock.Any(), int64(123), domain.BizArticle, int64(1), readWindow).
+					Return(true, nil)
+				limiter.EXPECT().Limit(gomock.Any(), "abuse:limit:read:123").Return(true, nil)
+				repo.EXPECT().AddAudit(gomock.Any(), domain.AbuseAudit{
+					Uid: 123, Action: domain.AbuseActionRead, Biz: domain.BizArticle, BizId: 1,
+				}, auditWindow).Return(nil)
+				return repo, limiter
+			},
+			want: false,
+		},
+		{
+			name: "去重失败放行",
+			mock: func(ctrl *gomock.Controller) (repository.AntiAbuseRepository, ratelimit.Limiter) {
+				repo := repomocks.NewMockAntiAbuseRepository(ctrl)
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				repo.EXPECT().FirstRead(gomock.Any(), int64(123), domain.BizArticle, int64(1), readWindow).
+					Return(false, errors.New("mock redis error"))
+				return repo, limiter
+			},
+			want: true,
+		},
+		{
+			name: "限流失败放行",
+			mock: func(ctrl *gomock.Controller) (repository.AntiAbuseRepository, ratelimit.Limiter) {
+				repo := repomocks.NewMockAntiAbuseRepository(ctrl)
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				repo.EXPECT().FirstRead(gomock.Any(), int64(123), domain.BizArticle, int64(1), readWindow).
+					Return(true, nil)
+				limiter.EXPECT().Limit(gomock.Any(), "abuse:limit:read:123").
+					Return(false, errors.New("mock redis error"))
+				return repo, limiter
+			},
+			want: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, limiter := tc.mock(ctrl)
+			svc := NewAntiAbuseService(repo, limiter, nil, readWindow, auditWindow, logger.NewNopLogger())
+			got := svc.CountRead(context.Background(), 123, domain.BizArticle, 1)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAntiAbuseService_AllowLike(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockAntiAbuseRepository(ctrl)
+	limiter := limitmocks.NewMockLimiter(ctrl)
+	gomock.InOrder(
+		limiter.EXPECT().Limit(gomock.Any(), "abuse:limit:like:123").Return(false, nil),
+		limiter.EXPECT().Limit(gomock.Any(), "abuse:limit:like:123").Return(true, nil),
+	)
+	// 审计写失败也要拦住
+	repo.EXPECT().AddAudit(gomock.Any(), domain.AbuseAudit{
+		Uid: 123, Action: domain.AbuseActionLike, Biz: domain.BizArticle, BizId: 1,
+	}, time.Minute).Return(errors.New("mock db error"))
+	svc := NewAntiAbuseService(repo, nil, limiter, time.Minute, time.Minute, logger.NewNopLogger())
+	assert.True(t, svc.AllowLike(context.Background(), 123, domain.BizArticle, 1))
+	assert.False(t, svc.AllowLike(context.Background(), 123, domain.BizArticle, 1))
+}
@@ -2,14 +2,39 @@ package service
 
 import (
 	"context"
+	"errors"
 	"geektime/webook/internal/domain"
 	event "geektime/webook/internal/events/article"
 	events "geektime/webook/internal/events/article"
 	repository2 "geektime/webook/internal/repository"
+	"geektime/webook/pkg/diffx"
 	"geektime/webook/pkg/logger"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+var (
+	ErrIllegalRevision      = errors.New("非法访问文章历史版本")
+	ErrArticleNotScheduled  = repository2.ErrArticleNotScheduled
+	ErrIllegalTags          = errors.New("标签或者分类不合法")
+	ErrPermissionDenied     = repository2.ErrPermissionDenied
+	ErrCoAuthorNotFound     = repository2.ErrCoAuthorNotFound
+	ErrIllegalCoAuthor      = errors.New("不能邀请自己或者角色不对")
+	ErrArticleNotInTrash    = repository2.ErrArticleNotInTrash
+	ErrArticleInTrash       = repository2.ErrArticleInTrash
+	ErrPubArticleNotFound   = repository2.ErrPubArticleNotFound
+	ErrAuthorNotFound       = repository2.ErrAuthorNotFound
+	ErrSeriesNotFound       = repository2.ErrSeriesNotFound
+	ErrArticleInOtherSeries = repository2.ErrArticleInOtherSeries
+	ErrIllegalSeriesOrder   = repository2.ErrIllegalSeriesOrder
+	ErrIllegalSeries        = errors.New("专栏标题为空或者太长")
+	ErrRevisionDiffTooLarge = diffx.ErrTooManyLines
+)
+
+// ArticleVersionConflictError 保存的时候帖子已经被改过了，Current 是最新的版本号
+type ArticleVersionConflictError = repository2.ArticleVersionConflictError
+
 type ArticleService interface {
 	Save(ctx context.Context, art domain.Article) (int64, error)
 	Publish(ctx context.Context, art domain.Article) (int64, error)
@@ -18,8 +43,76 @@ type ArticleService interface {
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
 	// ListPub 只取7天内的数据
 	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]domain.Article, error)
+	// GetByAuthorAfter 游标翻页，cur 是上一页的 NextArticleCursor，第一页传零值
+	GetByAuthorAfter(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// ListPubAfter 游标翻页，翻页过程中有新发表的帖子也不会重复或者漏掉
+	ListPubAfter(ctx context.Context, cur domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// AuthorProfile 作者的公开主页，带上第一页已发表的帖子
+	AuthorProfile(ctx context.Context, uid int64) (domain.AuthorProfile, error)
+	// ListPubByAuthor 作者主页后面几页的帖子
+	ListPubByAuthor(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error)
+	// ListPubByIds 批量查询已发表的帖子，撤回了的帖子不会返回
+	ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
 	GetById(ctx context.Context, id int64) (domain.Article, error)
 	GetPubById(ctx context.Context, id int64, uid int64) (domain.Article, error)
+
+	// ListRevisions 作者查看自己帖子的历史版本
+	ListRevisions(ctx context.Context, artId int64, uid int64, offset int, limit int) ([]domain.ArticleRevision, error)
+	// DiffRevision 比较历史版本和制作库当前的内容
+	DiffRevision(ctx context.Context, revId int64, uid int64) ([]diffx.Line, error)
+	// Rollback 把帖子恢复成某个历史版本，恢复之后是未发表状态
+	Rollback(ctx context.Context, revId int64, uid int64) (int64, error)
+
+	// SchedulePublish 定时发表，art.PublishAt 到了之后才会同步到线上库
+	// 在发表之前再次调用就是修改发表时间
+	SchedulePublish(ctx context.Context, art domain.Article) (int64, error)
+	// CancelSchedule 取消定时发表
+	CancelSchedule(ctx context.Context, art domain.Article) error
+	// ListDueScheduled 找出到了发表时间的定时发表帖子
+	ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error)
+	// PublishScheduled 发表到期的定时帖子，art 是 ListDueScheduled 返回的帖子
+	PublishScheduled(ctx context.Context, art domain.Article) error
+
+	// ListPubByTag 按照标签浏览已发表的帖子
+	ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]domain.Article, error)
+	// CountTags 标签和标签下已发表的帖子数量，数量多的在前面
+	CountTags(ctx context.Context, limit int) ([]domain.TagCount, error)
+
+	// Role uid 在制作库的帖子 art 上的角色
+	Role(ctx context.Context, art domain.Article, uid int64) (domain.CoAuthorRole, error)
+	// InviteCoAuthor 只有 owner 可以邀请，role 只能是 editor 或者 viewer
+	InviteCoAuthor(ctx context.Context, artId int64, uid int64, invitee int64, role domain.CoAuthorRole) error
+	// AcceptCoAuthor uid 接受帖子的邀请
+	AcceptCoAuthor(ctx context.Context, artId int64, uid int64) error
+	// RevokeCoAuthor owner 撤销别人，或者合作者自己退出
+	RevokeCoAuthor(ctx context.Context, artId int64, uid int64, coAuthor int64) error
+	// ListCoAuthors 能看帖子的人都能看到合作者
+	ListCoAuthors(ctx context.Context, artId int64, uid int64) ([]domain.CoAuthor, error)
+
+	// Delete 只有 owner 可以把帖子放进回收站，发表过的帖子读者就看不到了
+	Delete(ctx context.Context, artId int64, uid int64) error
+	// Restore 从回收站恢复，发表过的帖子要重新发表
+	Restore(ctx context.Context, artId int64, uid int64) error
+	// ListTrash uid 回收站里面的帖子
+	ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	// ListExpiredTrash 在 before 之前放进回收站的帖子
+	ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]domain.Article, error)
+	// Purge 彻底删除回收站里面的帖子，art 是 ListExpiredTrash 返回的帖子
+	Purge(ctx context.Context, art domain.Article) error
+
+	// SaveSeries 新建或者修改专栏，s.Author 是操作的人，只能修改自己的专栏
+	SaveSeries(ctx context.Context, s domain.ArticleSeries) (int64, error)
+	DeleteSeries(ctx context.Context, id int64, uid int64) error
+	// GetSeries 作者查看自己的专栏，带上专栏里面还没发表的帖子
+	GetSeries(ctx context.Context, id int64, uid int64) (domain.ArticleSeries, error)
+	ListSeries(ctx context.Context, uid int64, offset int, limit int) ([]domain.ArticleSeries, error)
+	// AddSeriesArticle 只能把自己的帖子加到自己的专栏最后面
+	AddSeriesArticle(ctx context.Context, seriesId int64, artId int64, uid int64) error
+	RemoveSeriesArticle(ctx context.Context, seriesId int64, artId int64, uid int64) error
+	// ReorderSeries artIds 是专栏里面所有帖子的新顺序
+	ReorderSeries(ctx context.Context, seriesId int64, uid int64, artIds []int64) error
+	// SeriesNav 读者看帖子的时候专栏里面的上一篇和下一篇
+	SeriesNav(ctx context.Context, artId int64) (domain.SeriesNav, error)
 }
 
 type articleService struct {
@@ -29,13 +122,16 @@ type articleService struct {
 	reader   repository2.ArticleReaderRepository
 	l        logger.LoggerV1
 	producer events.Producer
+	abuse    AntiAbuseService
 }
 
-func NewArticleService(repo repository2.ArticleRepository, l logger.LoggerV1, producer event.Producer) ArticleService {
+func NewArticleService(repo repository2.ArticleRepository, l logger.LoggerV1,
+	producer event.Producer, abuse AntiAbuseService) ArticleService {
 	return &articleService{
 		repo:     repo,
 		l:        l,
 		producer: producer,
+		abuse:    abuse,
 	}
 }
 
@@ -44,10 +140,34 @@ func (a *articleService) ListPub(ctx context.Context, start time.Time, offset in
 	return a.repo.ListPub(ctx, start, offset, limit)
 }
 
+func (a *articleService) ListPubAfter(ctx context.Context, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.ListPubAfter(ctx, cur, limit)
+}
+
+func (a *articleService) AuthorProfile(ctx context.Context, uid int64) (domain.AuthorProfile, error) {
+	return a.repo.GetAuthorProfile(ctx, uid)
+}
+
+func (a *articleService) ListPubByAuthor(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.ListPubByAuthor(ctx, uid, cur, limit)
+}
+
+func (a *articleService) ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	return a.repo.ListPubByIds(ctx, ids)
+}
+
 // Save 修改或者创建帖子，保存
+// 保存草稿会取消定时发表
 func (a *articleService) Save(ctx context.Context, art domain.Article) (int64, error) {
+	if err := a.normalizeTags(&art); err != nil {
+		return 0, err
+	}
+	if err := a.asOwner(ctx, &art); err != nil {
+		return 0, err
+	}
 	//将帖子的状态设置为未发表
 	art.Status = domain.ArticleStatusUnpublished
+	art.PublishAt = time.Time{}
 	if art.Id > 0 {
 		err := a.repo.Update(ctx, art)
 		return art.Id, err
@@ -56,8 +176,238 @@ func (a *articleService) Save(ctx context.Context, art domain.Article) (int64, e
 }
 
 func (a *articleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
+	if err := a.normalizeTags(&art); err != nil {
+		return 0, err
+	}
+	if err := a.asOwner(ctx, &art); err != nil {
+		return 0, err
+	}
 	art.Status = domain.ArticleStatusPublished
-	return a.repo.Sync(ctx, art)
+	art.PublishAt = time.Time{}
+	// 发表的时候渲染一次，读者看的时候就不用再渲染了
+	if err := art.Render(); err != nil {
+		return 0, err
+	}
+	id, err := a.repo.Sync(ctx, art)
+	if err == nil {
+		art.Id = id
+		art.Utime = time.Now()
+		a.producePublishedEvent(ctx, art)
+	}
+	return id, err
+}
+
+// SchedulePublish 只保存到制作库，由定时发表的 job 在 PublishAt 之后同步到线上库
+func (a *articleService) SchedulePublish(ctx context.Context, art domain.Article) (int64, error) {
+	if err := a.normalizeTags(&art); err != nil {
+		return 0, err
+	}
+	if err := a.asOwner(ctx, &art); err != nil {
+		return 0, err
+	}
+	art.Status = domain.ArticleStatusScheduled
+	if art.Id > 0 {
+		err := a.repo.Update(ctx, art)
+		return art.Id, err
+	}
+	return a.repo.Create(ctx, art)
+}
+
+func (a *articleService) CancelSchedule(ctx context.Context, art domain.Article) error {
+	if err := a.asOwner(ctx, &art); err != nil {
+		return err
+	}
+	return a.repo.CancelSchedule(ctx, art.Id, art.Author.Id)
+}
+
+func (a *articleService) ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	return a.repo.ListScheduled(ctx, now, limit)
+}
+
+func (a *articleService) ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]domain.Article, error) {
+	return a.repo.ListPubByTag(ctx, strings.TrimSpace(tag), offset, limit)
+}
+
+func (a *articleService) CountTags(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	return a.repo.CountTags(ctx, limit)
+}
+
+// asOwner 检查 art.Author 能不能修改已有的帖子，可以的话把作者换成 owner
+// 合作者改过之后帖子还是 owner 的，制作库和线上库都按照 owner 来更新
+func (a *articleService) asOwner(ctx context.Context, art *domain.Article) error {
+	if art.Id <= 0 {
+		return nil
+	}
+	old, err := a.repo.GetById(ctx, art.Id)
+	if err != nil {
+		return err
+	}
+	// 回收站里面的帖子要先恢复，不然发表之后读者又能看到
+	if !old.DeletedAt.IsZero() {
+		return ErrArticleInTrash
+	}
+	role, err := a.repo.Role(ctx, old, art.Author.Id)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return ErrPermissionDenied
+	}
+	art.Author = old.Author
+	return nil
+}
+
+func (a *articleService) Role(ctx context.Context, art domain.Article, uid int64) (domain.CoAuthorRole, error) {
+	return a.repo.Role(ctx, art, uid)
+}
+
+func (a *articleService) InviteCoAuthor(ctx context.Context, artId int64, uid int64,
+	invitee int64, role domain.CoAuthorRole) error {
+	art, err := a.repo.GetById(ctx, artId)
+	if err != nil {
+		return err
+	}
+	r, err := a.repo.Role(ctx, art, uid)
+	if err != nil {
+		return err
+	}
+	if !r.CanManage() {
+		return ErrPermissionDenied
+	}
+	if !role.Invitable() || invitee <= 0 || invitee == art.Author.Id {
+		return ErrIllegalCoAuthor
+	}
+	return a.repo.InviteCoAuthor(ctx, domain.CoAuthor{
+		ArticleId: artId,
+		Uid:       invitee,
+		Role:      role,
+		Inviter:   uid,
+	})
+}
+
+func (a *articleService) AcceptCoAuthor(ctx context.Context, artId int64, uid int64) error {
+	return a.repo.AcceptCoAuthor(ctx, artId, uid)
+}
+
+func (a *articleService) RevokeCoAuthor(ctx context.Context, artId int64, uid int64, coAuthor int64) error {
+	if uid != coAuthor {
+		art, err := a.repo.GetById(ctx, artId)
+		if err != nil {
+			return err
+		}
+		r, err := a.repo.Role(ctx, art, uid)
+		if err != nil {
+			return err
+		}
+		if !r.CanManage() {
+			return ErrPermissionDenied
+		}
+	}
+	return a.repo.RevokeCoAuthor(ctx, artId, coAuthor)
+}
+
+func (a *articleService) ListCoAuthors(ctx context.Context, artId int64, uid int64) ([]domain.CoAuthor, error) {
+	art, err := a.repo.GetById(ctx, artId)
+	if err != nil {
+		return nil, err
+	}
+	r, err := a.repo.Role(ctx, art, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !r.CanView() {
+		return nil, ErrPermissionDenied
+	}
+	return a.repo.ListCoAuthors(ctx, artId)
+}
+
+func (a *articleService) Delete(ctx context.Context, artId int64, uid int64) error {
+	art, err := a.manage(ctx, artId, uid)
+	if err != nil {
+		return err
+	}
+	err = a.repo.Delete(ctx, art)
+	if err == nil {
+		// 对下游来说和撤回一样
+		art.Status = domain.ArticleStatusPrivate
+		art.Utime = time.Now()
+		a.producePublishedEvent(ctx, art)
+	}
+	return err
+}
+
+func (a *articleService) Restore(ctx context.Context, artId int64, uid int64) error {
+	art, err := a.manage(ctx, artId, uid)
+	if err != nil {
+		return err
+	}
+	return a.repo.Restore(ctx, art)
+}
+
+func (a *articleService) ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+	return a.repo.ListTrash(ctx, uid, offset, limit)
+}
+
+func (a *articleService) ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]domain.Article, error) {
+	return a.repo.ListExpiredTrash(ctx, before, limit)
+}
+
+// Purge 帖子已经删掉了，发送消息失败只记录日志，下游的数据要手工清理
+func (a *articleService) Purge(ctx context.Context, art domain.Article) error {
+	err := a.repo.Purge(ctx, art)
+	if err != nil {
+		return err
+	}
+	err = a.producer.ProduceDeletedEvent(ctx, event.DeletedEvent{
+		Biz:   domain.BizArticle,
+		BizId: art.Id,
+	})
+	if err != nil {
+		a.l.Error("发送帖子删除消息失败",
+			logger.Int64("aid", art.Id),
+			logger.Error(err))
+	}
+	return nil
+}
+
+// manage 查出制作库的帖子，并且确认 uid 是 owner
+func (a *articleService) manage(ctx context.Context, artId int64, uid int64) (domain.Article, error) {
+	art, err := a.repo.GetById(ctx, artId)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	r, err := a.repo.Role(ctx, art, uid)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if !r.CanManage() {
+		return domain.Article{}, ErrPermissionDenied
+	}
+	return art, nil
+}
+
+// normalizeTags 整理标签和分类，超过限制的直接拒绝
+func (a *articleService) normalizeTags(art *domain.Article) error {
+	art.Tags = domain.NormalizeTags(art.Tags)
+	art.Category = strings.TrimSpace(art.Category)
+	if !domain.ValidTags(art.Tags) ||
+		utf8.RuneCountInString(art.Category) > domain.MaxCategoryLen {
+		return ErrIllegalTags
+	}
+	return nil
+}
+
+// PublishScheduled 渲染的是查出来的那一版内容，如果在这之后作者又改了，
+// 制作库的 utime 对不上，这一次就不会发表
+func (a *articleService) PublishScheduled(ctx context.Context, art domain.Article) error {
+	if err := art.Render(); err != nil {
+		return err
+	}
+	art, err := a.repo.PublishScheduled(ctx, art)
+	if err == nil {
+		a.producePublishedEvent(ctx, art)
+	}
+	return err
 }
 
 // PublishV1 依靠两个个不同repository来完成
@@ -100,20 +450,54 @@ func (a *articleService) PublishV1(ctx context.Context, art domain.Article) (int
 }
 
 func (a *articleService) Withdraw(ctx context.Context, art domain.Article) error {
-	return a.repo.SyncStatus(ctx, art.Id, art.Author.Id, domain.ArticleStatusPrivate)
+	err := a.repo.SyncStatus(ctx, art.Id, art.Author.Id, domain.ArticleStatusPrivate)
+	if err == nil {
+		art.Status = domain.ArticleStatusPrivate
+		art.Utime = time.Now()
+		a.producePublishedEvent(ctx, art)
+	}
+	return err
+}
+
+// producePublishedEvent 通知下游线上库的帖子变了
+// 线上库已经改成功了，发送失败只记录日志，不影响发表和撤回
+func (a *articleService) producePublishedEvent(ctx context.Context, art domain.Article) {
+	// 直接发表的时候拿不到创建时间
+	var ctime int64
+	if !art.Ctime.IsZero() {
+		ctime = art.Ctime.UnixMilli()
+	}
+	err := a.producer.ProducePublishedEvent(ctx, event.PublishedEvent{
+		Id:       art.Id,
+		Title:    art.Title,
+		Content:  art.Content,
+		AuthorId: art.Author.Id,
+		Status:   art.Status.ToUint8(),
+		Ctime:    ctime,
+		Utime:    art.Utime.UnixMilli(),
+	})
+	if err != nil {
+		a.l.Error("发送帖子发表消息失败",
+			logger.Int64("aid", art.Id),
+			logger.Error(err))
+	}
 }
 
 func (a *articleService) GetPubById(ctx context.Context, id int64, uid int64) (domain.Article, error) {
 	art, err := a.repo.GetPubById(ctx, id)
-	//向kafka发送已读消息
+	//向kafka发送已读消息，重复阅读和刷量的也要发，阅读历史要用，只是不计数
 	if err == nil {
+		counted := a.abuse.CountRead(ctx, uid, domain.BizArticle, id)
 		go func() {
 			err2 := a.producer.ProduceReadEvent(ctx, event.ReadEvent{
-				Uid: uid,
-				Aid: id,
+				Uid:     uid,
+				Biz:     domain.BizArticle,
+				BizId:   id,
+				Aid:     id,
+				Counted: counted,
 			})
 			if err2 != nil {
-				a.l.Error("发送消息失败", logger.Error(err))
+				a.l.Error("发送消息失败", logger.Error(err2))
 			}
 		}()
 	}
@@ -127,3 +511,67 @@ func (a *articleService) GetById(ctx context.Context, id int64) (domain.Article,
 func (a *articleService) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
 	return a.repo.GetByAuthor(ctx, uid, offset, limit)
 }
+
+func (a *articleService) GetByAuthorAfter(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.GetByAuthorAfter(ctx, uid, cur, limit)
+}
+
+func (a *articleService) ListRevisions(ctx context.Context, artId int64, uid int64, offset int, limit int) ([]domain.ArticleRevision, error) {
+	art, err := a.repo.GetById(ctx, artId)
+	if err != nil {
+		return nil, err
+	}
+	if art.Author.Id != uid {
+		return nil, ErrIllegalRevision
+	}
+	return a.repo.ListRevisions(ctx, artId, offset, limit)
+}
+
+// DiffRevision 以历史版本为旧文本，制作库当前内容为新文本，逐行比较
+func (a *articleService) DiffRevision(ctx context.Context, revId int64, uid int64) ([]diffx.Line, error) {
+	rev, err := a.getRevision(ctx, revId, uid)
+	if err != nil {
+		return nil, err
+	}
+	art, err := a.repo.GetById(ctx, rev.Article.Id)
+	if err != nil {
+		return nil, err
+	}
+	return diffx.Lines(rev.Article.Content, art.Content)
+}
+
+// Rollback 回滚就是把历史版本的内容再保存一次，所以回滚本身也会留下一个新的历史版本
+func (a *articleService) Rollback(ctx context.Context, revId int64, uid int64) (int64, error) {
+	rev, err := a.getRevision(ctx, revId, uid)
+	if err != nil {
+		return 0, err
+	}
+	// 历史版本只记录了标题和内容，分类和标签保持现在的
+	art, err := a.repo.GetById(ctx, rev.Article.Id)
+	if err != nil {
+		return 0, err
+	}
+	return a.Save(ctx, domain.Article{
+		Id:       rev.Article.Id,
+		Title:    rev.Article.Title,
+		Content:  rev.Article.Content,
+		Category: art.Category,
+		Tags:     art.Tags,
+		// 回滚是在最新的版本上再保存一次
+		Version: art.Version,
+		Author: domain.Author{
+			Id: uid,
+		},
+	})
+}
+
+func (a *articleService) getRevision(ctx context.Context, revId int64, uid int64) (domain.ArticleRevision, error) {
+	rev, err := a.repo.GetRevision(ctx, revId)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	if rev.Article.Author.Id != uid {
+		return domain.ArticleRevision{}, ErrIllegalRevision
+	}
+	return rev, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"geektime/webook/internal/domain"
+	"strings"
+	"unicode/utf8"
+)
+
+func (a *articleService) SaveSeries(ctx context.Context, s domain.ArticleSeries) (int64, error) {
+	s.Title = strings.TrimSpace(s.Title)
+	if s.Title == "" || utf8.RuneCountInString(s.Title) > domain.MaxSeriesTitleLen {
+		return 0, ErrIllegalSeries
+	}
+	if s.Id > 0 {
+		return s.Id, a.repo.UpdateSeries(ctx, s)
+	}
+	return a.repo.CreateSeries(ctx, s)
+}
+
+func (a *articleService) DeleteSeries(ctx context.Context, id int64, uid int64) error {
+	return a.repo.DeleteSeries(ctx, id, uid)
+}
+
+func (a *articleService) GetSeries(ctx context.Context, id int64, uid int64) (domain.ArticleSeries, error) {
+	s, err := a.ownSeries(ctx, id, uid)
+	if err != nil {
+		return domain.ArticleSeries{}, err
+	}
+	s.Articles, err = a.repo.ListSeriesArticles(ctx, id)
+	return s, err
+}
+
+func (a *articleService) ListSeries(ctx context.Context, uid int64, offset int, limit int) ([]domain.ArticleSeries, error) {
+	return a.repo.ListSeries(ctx, uid, offset, limit)
+}
+
+func (a *articleService) AddSeriesArticle(ctx context.Context, seriesId int64, artId int64, uid int64) error {
+	_, err := a.ownSeries(ctx, seriesId, uid)
+	if err != nil {
+		return err
+	}
+	// 合作者不能把帖子放进自己的专栏
+	_, err = a.manage(ctx, artId, uid)
+	if err != nil {
+		return err
+	}
+	return a.repo.AddSeriesArticle(ctx, seriesId, artId)
+}
+
+func (a *articleService) RemoveSeriesArticle(ctx context.Context, seriesId int64, artId int64, uid int64) error {
+	_, err := a.ownSeries(ctx, seriesId, uid)
+	if err != nil {
+		return err
+	}
+	return a.repo.RemoveSeriesArticle(ctx, seriesId, artId)
+}
+
+func (a *articleService) ReorderSeries(ctx context.Context, seriesId int64, uid int64, artIds []int64) error {
+	_, err := a.ownSeries(ctx, seriesId, uid)
+	if err != nil {
+		return err
+	}
+	return a.repo.ReorderSeries(ctx, seriesId, artIds)
+}
+
+func (a *articleService) SeriesNav(ctx context.Context, artId int64) (domain.SeriesNav, error) {
+	return a.repo.SeriesNav(ctx, artId)
+}
+
+// ownSeries 专栏只有作者自己可以管理
+func (a *articleService) ownSeries(ctx context.Context, id int64, uid int64) (domain.ArticleSeries, error) {
+	s, err := a.repo.GetSeries(ctx, id)
+	if err != nil {
+		return domain.ArticleSeries{}, err
+	}
+	if s.Author.Id != uid {
+		return domain.ArticleSeries{}, ErrPermissionDenied
+	}
+	return s, nil
+}
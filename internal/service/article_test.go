@@ -1 +1,622 @@
 package service
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/repository"
+	repomocks "geektime/webook/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func Test_articleService_Save_CoAuthor(t *testing.T) {
+	owner := domain.Article{
+		Id:    1,
+		Title: "旧标题",
+		Author: domain.Author{
+			Id: 123,
+		},
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.ArticleRepository
+		art     domain.Article
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "新建帖子不检查权限",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Article{
+					Title:  "标题",
+					Author: domain.Author{Id: 456},
+					Tags:   []string{},
+					Status: domain.ArticleStatusUnpublished,
+				}).Return(int64(2), nil)
+				return repo
+			},
+			art: domain.Article{
+				Title:  "标题",
+				Author: domain.Author{Id: 456},
+			},
+			wantId: 2,
+		},
+		{
+			name: "编辑修改之后作者还是 owner",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(owner, nil)
+				repo.EXPECT().Role(gomock.Any(), owner, int64(456)).
+					Return(domain.CoAuthorRoleEditor, nil)
+				repo.EXPECT().Update(gomock.Any(), domain.Article{
+					Id:     1,
+					Title:  "新标题",
+					Author: domain.Author{Id: 123},
+					Tags:   []string{},
+					Status: domain.ArticleStatusUnpublished,
+				}).Return(nil)
+				return repo
+			},
+			art: domain.Article{
+				Id:     1,
+				Title:  "新标题",
+				Author: domain.Author{Id: 456},
+			},
+			wantId: 1,
+		},
+		{
+			name: "只读的合作者不能修改",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(owner, nil)
+				repo.EXPECT().Role(gomock.Any(), owner, int64(456)).
+					Return(domain.CoAuthorRoleViewer, nil)
+				return repo
+			},
+			art: domain.Article{
+				Id:     1,
+				Title:  "新标题",
+				Author: domain.Author{Id: 456},
+			},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "没有关系的人不能修改",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(owner, nil)
+				repo.EXPECT().Role(gomock.Any(), owner, int64(789)).
+					Return(domain.CoAuthorRoleUnknown, nil)
+				return repo
+			},
+			art: domain.Article{
+				Id:     1,
+				Title:  "新标题",
+				Author: domain.Author{Id: 789},
+			},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "查询角色失败",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(owner, nil)
+				repo.EXPECT().Role(gomock.Any(), owner, int64(456)).
+					Return(domain.CoAuthorRoleUnknown, errors.New("mock db 错误"))
+				return repo
+			},
+			art: domain.Article{
+				Id:     1,
+				Title:  "新标题",
+				Author: domain.Author{Id: 456},
+			},
+			wantErr: errors.New("mock db 错误"),
+		},
+		{
+			name: "回收站里面的帖子不能修改",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				trashed := owner
+				trashed.DeletedAt = time.Now()
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(trashed, nil)
+				return repo
+			},
+			art: domain.Article{
+				Id:     1,
+				Title:  "新标题",
+				Author: domain.Author{Id: 123},
+			},
+			wantErr: ErrArticleInTrash,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil)
+			id, err := svc.Save(context.Background(), tc.art)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func Test_articleService_InviteCoAuthor(t *testing.T) {
+	art := domain.Article{
+		Id: 1,
+		Author: domain.Author{
+			Id: 123,
+		},
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.ArticleRepository
+		uid     int64
+		invitee int64
+		role    domain.CoAuthorRole
+		wantErr error
+	}{
+		{
+			name: "邀请成功",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				repo.EXPECT().InviteCoAuthor(gomock.Any(), domain.CoAuthor{
+					ArticleId: 1,
+					Uid:       456,
+					Role:      domain.CoAuthorRoleEditor,
+					Inviter:   123,
+				}).Return(nil)
+				return repo
+			},
+			uid:     123,
+			invitee: 456,
+			role:    domain.CoAuthorRoleEditor,
+		},
+		{
+			name: "编辑不能邀请别人",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(456)).
+					Return(domain.CoAuthorRoleEditor, nil)
+				return repo
+			},
+			uid:     456,
+			invitee: 789,
+			role:    domain.CoAuthorRoleViewer,
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "不能邀请别人做 owner",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				return repo
+			},
+			uid:     123,
+			invitee: 456,
+			role:    domain.CoAuthorRoleOwner,
+			wantErr: ErrIllegalCoAuthor,
+		},
+		{
+			name: "不能邀请自己",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				return repo
+			},
+			uid:     123,
+			invitee: 123,
+			role:    domain.CoAuthorRoleEditor,
+			wantErr: ErrIllegalCoAuthor,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil)
+			err := svc.InviteCoAuthor(context.Background(), 1, tc.uid, tc.invitee, tc.role)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleService_RevokeCoAuthor(t *testing.T) {
+	art := domain.Article{
+		Id: 1,
+		Author: domain.Author{
+			Id: 123,
+		},
+	}
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) repository.ArticleRepository
+		uid      int64
+		coAuthor int64
+		wantErr  error
+	}{
+		{
+			name: "自己退出",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().RevokeCoAuthor(gomock.Any(), int64(1), int64(456)).Return(nil)
+				return repo
+			},
+			uid:      456,
+			coAuthor: 456,
+		},
+		{
+			name: "owner 撤销",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				repo.EXPECT().RevokeCoAuthor(gomock.Any(), int64(1), int64(456)).Return(nil)
+				return repo
+			},
+			uid:      123,
+			coAuthor: 456,
+		},
+		{
+			name: "编辑不能撤销别人",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(456)).
+					Return(domain.CoAuthorRoleEditor, nil)
+				return repo
+			},
+			uid:      456,
+			coAuthor: 789,
+			wantErr:  ErrPermissionDenied,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil)
+			err := svc.RevokeCoAuthor(context.Background(), 1, tc.uid, tc.coAuthor)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleService_Restore(t *testing.T) {
+	art := domain.Article{
+		Id: 1,
+		Author: domain.Author{
+			Id: 123,
+		},
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.ArticleRepository
+		uid     int64
+		wantErr error
+	}{
+		{
+			name: "恢复成功",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				repo.EXPECT().Restore(gomock.Any(), art).Return(nil)
+				return repo
+			},
+			uid: 123,
+		},
+		{
+			name: "编辑不能恢复",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(456)).
+					Return(domain.CoAuthorRoleEditor, nil)
+				return repo
+			},
+			uid:     456,
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "不在回收站里面",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				repo.EXPECT().Restore(gomock.Any(), art).Return(ErrArticleNotInTrash)
+				return repo
+			},
+			uid:     123,
+			wantErr: ErrArticleNotInTrash,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil)
+			err := svc.Restore(context.Background(), 1, tc.uid)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleService_AddSeriesArticle(t *testing.T) {
+	series := domain.ArticleSeries{
+		Id:     10,
+		Title:  "专栏",
+		Author: domain.Author{Id: 123},
+	}
+	art := domain.Article{
+		Id:     1,
+		Author: domain.Author{Id: 123},
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.ArticleRepository
+		uid     int64
+		wantErr error
+	}{
+		{
+			name: "添加成功",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetSeries(gomock.Any(), int64(10)).Return(series, nil)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				repo.EXPECT().AddSeriesArticle(gomock.Any(), int64(10), int64(1)).Return(nil)
+				return repo
+			},
+			uid: 123,
+		},
+		{
+			name: "不是自己的专栏",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetSeries(gomock.Any(), int64(10)).Return(series, nil)
+				return repo
+			},
+			uid:     456,
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "合作者不能把帖子放进专栏",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetSeries(gomock.Any(), int64(10)).
+					Return(domain.ArticleSeries{Id: 10, Author: domain.Author{Id: 456}}, nil)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(456)).
+					Return(domain.CoAuthorRoleEditor, nil)
+				return repo
+			},
+			uid:     456,
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "帖子已经在专栏里面了",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetSeries(gomock.Any(), int64(10)).Return(series, nil)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().Role(gomock.Any(), art, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				repo.EXPECT().AddSeriesArticle(gomock.Any(), int64(10), int64(1)).
+					Return(ErrArticleInOtherSeries)
+				return repo
+			},
+			uid:     123,
+			wantErr: ErrArticleInOtherSeries,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil)
+			err := svc.AddSeriesArticle(context.Background(), 10, 1, tc.uid)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_articleService_SchedulePublish(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour)
+	old := domain.Article{
+		Id: 1,
+		Author: domain.Author{
+			Id: 123,
+		},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.ArticleRepository
+		art  domain.Article
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "新建帖子定时发表",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Article{
+					Title:     "标题",
+					Tags:      []string{"go"},
+					Category:  "后端",
+					Author:    domain.Author{Id: 123},
+					Status:    domain.ArticleStatusScheduled,
+					PublishAt: publishAt,
+				}).Return(int64(1), nil)
+				return repo
+			},
+			art: domain.Article{
+				Title:     "标题",
+				Tags:      []string{"go"},
+				Category:  " 后端 ",
+				Author:    domain.Author{Id: 123},
+				PublishAt: publishAt,
+			},
+			wantId: 1,
+		},
+		{
+			name: "合作者修改已有帖子，按照 owner 更新",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(old, nil)
+				repo.EXPECT().Role(gomock.Any(), old, int64(456)).
+					Return(domain.CoAuthorRoleEditor, nil)
+				repo.EXPECT().Update(gomock.Any(), domain.Article{
+					Id:        1,
+					Title:     "标题",
+					Tags:      []string{},
+					Author:    domain.Author{Id: 123},
+					Status:    domain.ArticleStatusScheduled,
+					PublishAt: publishAt,
+				}).Return(nil)
+				return repo
+			},
+			art: domain.Article{
+				Id:        1,
+				Title:     "标题",
+				Author:    domain.Author{Id: 456},
+				PublishAt: publishAt,
+			},
+			wantId: 1,
+		},
+		{
+			name: "标签太多",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				return repomocks.NewMockArticleRepository(ctrl)
+			},
+			art: domain.Article{
+				Tags:   []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+				Author: domain.Author{Id: 123},
+			},
+			wantErr: ErrIllegalTags,
+		},
+		{
+			name: "没有权限",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(old, nil)
+				repo.EXPECT().Role(gomock.Any(), old, int64(789)).
+					Return(domain.CoAuthorRoleUnknown, nil)
+				return repo
+			},
+			art: domain.Article{
+				Id:     1,
+				Author: domain.Author{Id: 789},
+			},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "回收站里面的帖子",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				trashed := old
+				trashed.DeletedAt = time.Now()
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(trashed, nil)
+				return repo
+			},
+			art: domain.Article{
+				Id:     1,
+				Author: domain.Author{Id: 123},
+			},
+			wantErr: ErrArticleInTrash,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil)
+			id, err := svc.SchedulePublish(context.Background(), tc.art)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func Test_articleService_CancelSchedule(t *testing.T) {
+	old := domain.Article{
+		Id: 1,
+		Author: domain.Author{
+			Id: 123,
+		},
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.ArticleRepository
+		uid     int64
+		wantErr error
+	}{
+		{
+			name: "合作者取消，按照 owner 取消",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(old, nil)
+				repo.EXPECT().Role(gomock.Any(), old, int64(456)).
+					Return(domain.CoAuthorRoleEditor, nil)
+				repo.EXPECT().CancelSchedule(gomock.Any(), int64(1), int64(123)).Return(nil)
+				return repo
+			},
+			uid: 456,
+		},
+		{
+			name: "没有权限",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(old, nil)
+				repo.EXPECT().Role(gomock.Any(), old, int64(789)).
+					Return(domain.CoAuthorRoleUnknown, nil)
+				return repo
+			},
+			uid:     789,
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "已经不是定时发表",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(old, nil)
+				repo.EXPECT().Role(gomock.Any(), old, int64(123)).
+					Return(domain.CoAuthorRoleOwner, nil)
+				repo.EXPECT().CancelSchedule(gomock.Any(), int64(1), int64(123)).
+					Return(ErrArticleNotScheduled)
+				return repo
+			},
+			uid:     123,
+			wantErr: ErrArticleNotScheduled,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil)
+			err := svc.CancelSchedule(context.Background(), domain.Article{
+				Id:     1,
+				Author: domain.Author{Id: tc.uid},
+			})
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
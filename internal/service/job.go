@@ -2,48 +2,102 @@ package service
 
 import (
 	"context"
+	"errors"
 	"geektime/webook/internal/domain"
 	"geektime/webook/internal/repository"
 	"geektime/webook/pkg/logger"
 	"time"
 )
 
+var (
+	ErrJobDuplicate         = repository.ErrJobDuplicate
+	ErrJobNotFound          = repository.ErrJobNotFound
+	ErrJobStatusConflict    = repository.ErrJobStatusConflict
+	ErrJobPreempted         = repository.ErrJobPreempted
+	ErrInvalidJobExpression = errors.New("job 的 cron 表达式不合法")
+)
+
 type CronJobService interface {
-	// Preempt 抢占
+	// AddJob 新增 job，同名的 job 已经存在会返回 ErrJobDuplicate
+	AddJob(ctx context.Context, j domain.Job) error
+	// Preempt 抢占，抢到之后会定时续约，用完要调用 CancelFunc 释放
 	Preempt(ctx context.Context) (domain.Job, error)
+	// ResetNextTime 租约过期被别的节点抢走了会返回 ErrJobPreempted
 	ResetNextTime(ctx context.Context, j domain.Job) error
-	//Release(ctx context.Context, job domain.Job) error
-	// 暴露 job 的增删改查方法
+
+	GetJob(ctx context.Context, id int64) (domain.Job, error)
+	ListJobs(ctx context.Context, offset, limit int) ([]domain.Job, error)
+	// UpdateJob 修改 job 的定义，下一次调度的时间按照新的表达式重新计算
+	// 正在运行的 job 返回 ErrJobStatusConflict，等这一次执行完再改
+	UpdateJob(ctx context.Context, j domain.Job) error
+	DeleteJob(ctx context.Context, id int64) error
+	// Pause 暂停调度，正在执行的那一次不受影响
+	Pause(ctx context.Context, id int64) error
+	// Resume 恢复暂停了的 job，不是暂停状态返回 ErrJobStatusConflict
+	Resume(ctx context.Context, id int64) error
+	// Trigger 让 job 马上被调度一次，只有等待中的 job 可以触发
+	Trigger(ctx context.Context, id int64) error
+
+	// StartExecution 记录开始执行，返回执行记录的 ID
+	StartExecution(ctx context.Context, e domain.JobExecution) (int64, error)
+	FinishExecution(ctx context.Context, e domain.JobExecution) error
+	ListExecutions(ctx context.Context, jid int64, offset, limit int) ([]domain.JobExecution, error)
 }
 
 type cronJobService struct {
 	repo            repository.CronJobRepository
 	l               logger.LoggerV1
 	refreshInterval time.Duration
+	// lease 超过这么久没有续约，就认为持有 job 的节点已经挂了
+	lease time.Duration
 }
 
-func NewCronJobService(repo repository.CronJobRepository, l logger.LoggerV1) CronJobService {
+// NewCronJobService lease 内会续约三次，偶尔一两次续约失败不会被别人抢走
+func NewCronJobService(repo repository.CronJobRepository, l logger.LoggerV1,
+	lease time.Duration) CronJobService {
 	return &cronJobService{repo: repo,
 		l:               l,
-		refreshInterval: time.Minute}
+		lease:           lease,
+		refreshInterval: lease / 3}
+}
+
+func (c *cronJobService) AddJob(ctx context.Context, j domain.Job) error {
+	if !j.ValidExpression() {
+		return ErrInvalidJobExpression
+	}
+	return c.repo.AddJob(ctx, j)
 }
 
 func (c *cronJobService) Preempt(ctx context.Context) (domain.Job, error) {
-	j, err := c.repo.Preempt(ctx)
+	j, err := c.repo.Preempt(ctx, c.lease)
 	if err != nil {
 		return domain.Job{}, err
 	}
 	ticker := time.NewTicker(c.refreshInterval)
+	done := make(chan struct{})
+	runCtx, cancelRun := context.WithCancel(context.Background())
 	go func() {
-		for range ticker.C {
-			c.refresh(j.Id)
+		for {
+			select {
+			case <-ticker.C:
+				if errors.Is(c.refresh(j), ErrJobPreempted) {
+					// 已经是别人的了，续约也没有意义，正在执行的也要停下来
+					cancelRun()
+					return
+				}
+			case <-done:
+				return
+			}
 		}
 	}()
+	j.Ctx = runCtx
 	j.CancelFunc = func() {
 		ticker.Stop()
+		close(done)
+		cancelRun()
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
-		err := c.repo.Release(ctx, j.Id)
+		err := c.repo.Release(ctx, j.Id, j.Version)
 		if err != nil {
 			c.l.Error("释放 job 失败",
 				logger.Error(err),
@@ -54,16 +108,78 @@ func (c *cronJobService) Preempt(ctx context.Context) (domain.Job, error) {
 }
 func (c *cronJobService) ResetNextTime(ctx context.Context, j domain.Job) error {
 	nextTime := j.NextTime()
-	return c.repo.UpdateNextTime(ctx, j.Id, nextTime)
+	return c.repo.UpdateNextTime(ctx, j.Id, j.Version, nextTime)
 }
 
-func (c *cronJobService) refresh(id int64) {
+func (c *cronJobService) refresh(j domain.Job) error {
 	// 本质上就是更新一下更新时间
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	err := c.repo.UpdateUtime(ctx, id)
+	err := c.repo.UpdateUtime(ctx, j.Id, j.Version)
 	if err != nil {
 		c.l.Error("续约失败", logger.Error(err),
-			logger.Int64("jid", id))
+			logger.Int64("jid", j.Id),
+			logger.Int64("version", j.Version))
+	}
+	return err
+}
+
+func (c *cronJobService) GetJob(ctx context.Context, id int64) (domain.Job, error) {
+	return c.repo.GetById(ctx, id)
+}
+
+func (c *cronJobService) ListJobs(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	return c.repo.List(ctx, offset, limit)
+}
+
+func (c *cronJobService) UpdateJob(ctx context.Context, j domain.Job) error {
+	if !j.ValidExpression() {
+		return ErrInvalidJobExpression
+	}
+	return c.repo.Update(ctx, j)
+}
+
+func (c *cronJobService) DeleteJob(ctx context.Context, id int64) error {
+	return c.repo.Delete(ctx, id)
+}
+
+func (c *cronJobService) Pause(ctx context.Context, id int64) error {
+	return c.repo.Pause(ctx, id)
+}
+
+func (c *cronJobService) Resume(ctx context.Context, id int64) error {
+	// 要用表达式重新计算下一次调度的时间
+	j, err := c.repo.GetById(ctx, id)
+	if err != nil {
+		return err
 	}
+	if j.Status != domain.JobStatusPaused {
+		return ErrJobStatusConflict
+	}
+	return c.repo.Resume(ctx, id, j.NextTime())
+}
+
+func (c *cronJobService) Trigger(ctx context.Context, id int64) error {
+	j, err := c.repo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if j.Status != domain.JobStatusWaiting {
+		return ErrJobStatusConflict
+	}
+	return c.repo.Trigger(ctx, id)
+}
+
+func (c *cronJobService) StartExecution(ctx context.Context, e domain.JobExecution) (int64, error) {
+	e.Status = domain.JobExecutionRunning
+	return c.repo.AddExecution(ctx, e)
+}
+
+func (c *cronJobService) FinishExecution(ctx context.Context, e domain.JobExecution) error {
+	return c.repo.FinishExecution(ctx, e)
+}
+
+func (c *cronJobService) ListExecutions(ctx context.Context,
+	jid int64, offset, limit int) ([]domain.JobExecution, error) {
+	return c.repo.ListExecutions(ctx, jid, offset, limit)
 }
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/repository"
+	repomocks "geektime/webook/internal/repository/mocks"
+	"geektime/webook/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestCronJobService_AddJob(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+		job  domain.Job
+
+		wantErr error
+	}{
+		{
+			name: "秒级表达式",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().AddJob(gomock.Any(), gomock.Any()).Return(nil)
+				return repo
+			},
+			job: domain.Job{Name: "test", Expression: "*/5 * * * * *"},
+		},
+		{
+			name: "描述符",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().AddJob(gomock.Any(), gomock.Any()).Return(nil)
+				return repo
+			},
+			job: domain.Job{Name: "test", Expression: "@every 30s"},
+		},
+		{
+			name: "表达式不合法",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				return repomocks.NewMockCronJobRepository(ctrl)
+			},
+			job:     domain.Job{Name: "test", Expression: "every 30s"},
+			wantErr: ErrInvalidJobExpression,
+		},
+		{
+			// 调度器的表达式带秒，少了一位也不合法
+			name: "少了秒",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				return repomocks.NewMockCronJobRepository(ctrl)
+			},
+			job:     domain.Job{Name: "test", Expression: "*/5 * * * *"},
+			wantErr: ErrInvalidJobExpression,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCronJobService(tc.mock(ctrl), logger.NewNopLogger(), time.Minute)
+			err := svc.AddJob(context.Background(), tc.job)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestCronJobService_UpdateJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockCronJobRepository(ctrl)
+	svc := NewCronJobService(repo, logger.NewNopLogger(), time.Minute)
+
+	err := svc.UpdateJob(context.Background(), domain.Job{Id: 1, Expression: "abc"})
+	assert.Equal(t, ErrInvalidJobExpression, err)
+
+	j := domain.Job{Id: 1, Name: "test", Expression: "@every 1m"}
+	repo.EXPECT().Update(gomock.Any(), j).Return(ErrJobNotFound)
+	err = svc.UpdateJob(context.Background(), j)
+	assert.Equal(t, ErrJobNotFound, err)
+
+	// 正在运行的不能修改
+	repo.EXPECT().Update(gomock.Any(), j).Return(ErrJobStatusConflict)
+	err = svc.UpdateJob(context.Background(), j)
+	assert.Equal(t, ErrJobStatusConflict, err)
+}
+
+func TestCronJobService_Resume(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+
+		wantErr error
+	}{
+		{
+			name: "按照表达式重新计算下一次调度的时间",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Job{
+					Id:         1,
+					Expression: "@every 1h",
+					Status:     domain.JobStatusPaused,
+					// 暂停之前的时间已经过去了
+					NextExecTime: time.Now().Add(-time.Hour * 24),
+				}, nil)
+				repo.EXPECT().Resume(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, nextTime time.Time) error {
+						assert.True(t, nextTime.After(time.Now().Add(time.Minute*59)))
+						return nil
+					})
+				return repo
+			},
+		},
+		{
+			name: "没有暂停",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Job{
+					Id:         1,
+					Expression: "@every 1h",
+					Status:     domain.JobStatusRunning,
+				}, nil)
+				return repo
+			},
+			wantErr: ErrJobStatusConflict,
+		},
+		{
+			name: "job 不存在",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Job{}, ErrJobNotFound)
+				return repo
+			},
+			wantErr: ErrJobNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCronJobService(tc.mock(ctrl), logger.NewNopLogger(), time.Minute)
+			err := svc.Resume(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestCronJobService_Trigger(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status domain.JobStatus
+
+		wantErr error
+	}{
+		{
+			name:   "等待中",
+			status: domain.JobStatusWaiting,
+		},
+		{
+			name:    "正在执行",
+			status:  domain.JobStatusRunning,
+			wantErr: ErrJobStatusConflict,
+		},
+		{
+			name:    "暂停了",
+			status:  domain.JobStatusPaused,
+			wantErr: ErrJobStatusConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomocks.NewMockCronJobRepository(ctrl)
+			repo.EXPECT().GetById(gomock.Any(), int64(1)).
+				Return(domain.Job{Id: 1, Status: tc.status}, nil)
+			if tc.wantErr == nil {
+				repo.EXPECT().Trigger(gomock.Any(), int64(1)).Return(nil)
+			}
+			svc := NewCronJobService(repo, logger.NewNopLogger(), time.Minute)
+			err := svc.Trigger(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestCronJobService_Preempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockCronJobRepository(ctrl)
+	lease := time.Millisecond * 30
+	repo.EXPECT().Preempt(gomock.Any(), lease).Return(domain.Job{Id: 1, Version: 2}, nil)
+	// 第一次续约成功，第二次发现已经被别的节点抢走了
+	gomock.InOrder(
+		repo.EXPECT().UpdateUtime(gomock.Any(), int64(1), int64(2)).Return(nil),
+		repo.EXPECT().UpdateUtime(gomock.Any(), int64(1), int64(2)).Return(ErrJobPreempted),
+	)
+	repo.EXPECT().Release(gomock.Any(), int64(1), int64(2)).Return(nil)
+
+	svc := NewCronJobService(repo, logger.NewNopLogger(), lease)
+	j, err := svc.Preempt(context.Background())
+	assert.NoError(t, err)
+	select {
+	case <-j.Ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("被抢占之后没有取消执行")
+	}
+	j.CancelFunc()
+}
@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/anti_abuse.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/anti_abuse.go -package=svcmocks -destination=./internal/service/mocks/anti_abuse.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAntiAbuseService is a mock of AntiAbuseService interface.
+type MockAntiAbuseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAntiAbuseServiceMockRecorder
+}
+
+// MockAntiAbuseServiceMockRecorder is the mock recorder for MockAntiAbuseService.
+type MockAntiAbuseServiceMockRecorder struct {
+	mock *MockAntiAbuseService
+}
+
+// NewMockAntiAbuseService creates a new mock instance.
+func NewMockAntiAbuseService(ctrl *gomock.Controller) *MockAntiAbuseService {
+	mock := &MockAntiAbuseService{ctrl: ctrl}
+	mock.recorder = &MockAntiAbuseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAntiAbuseService) EXPECT() *MockAntiAbuseServiceMockRecorder {
+	return m.recorder
+}
+
+// AllowLike mocks base method.
+func (m *MockAntiAbuseService) AllowLike(ctx context.Context, uid int64, biz string, bizId int64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowLike", ctx, uid, biz, bizId)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// AllowLike indicates an expected call of AllowLike.
+func (mr *MockAntiAbuseServiceMockRecorder) AllowLike(ctx, uid, biz, bizId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowLike", reflect.TypeOf((*MockAntiAbuseService)(nil).AllowLike), ctx, uid, biz, bizId)
+}
+
+// CountRead mocks base method.
+func (m *MockAntiAbuseService) CountRead(ctx context.Context, uid int64, biz string, bizId int64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRead", ctx, uid, biz, bizId)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CountRead indicates an expected call of CountRead.
+func (mr *MockAntiAbuseServiceMockRecorder) CountRead(ctx, uid, biz, bizId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRead", reflect.TypeOf((*MockAntiAbuseService)(nil).CountRead), ctx, uid, biz, bizId)
+}
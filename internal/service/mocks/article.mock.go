@@ -12,7 +12,9 @@ package svcmocks
 import (
 	context "context"
 	domain "geektime/webook/internal/domain"
+	diffx "geektime/webook/pkg/diffx"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,6 +42,121 @@ func (m *MockArticleService) EXPECT() *MockArticleServiceMockRecorder {
 	return m.recorder
 }
 
+// AcceptCoAuthor mocks base method.
+func (m *MockArticleService) AcceptCoAuthor(ctx context.Context, artId, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptCoAuthor", ctx, artId, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptCoAuthor indicates an expected call of AcceptCoAuthor.
+func (mr *MockArticleServiceMockRecorder) AcceptCoAuthor(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptCoAuthor", reflect.TypeOf((*MockArticleService)(nil).AcceptCoAuthor), ctx, artId, uid)
+}
+
+// AddSeriesArticle mocks base method.
+func (m *MockArticleService) AddSeriesArticle(ctx context.Context, seriesId, artId, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSeriesArticle", ctx, seriesId, artId, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSeriesArticle indicates an expected call of AddSeriesArticle.
+func (mr *MockArticleServiceMockRecorder) AddSeriesArticle(ctx, seriesId, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSeriesArticle", reflect.TypeOf((*MockArticleService)(nil).AddSeriesArticle), ctx, seriesId, artId, uid)
+}
+
+// AuthorProfile mocks base method.
+func (m *MockArticleService) AuthorProfile(ctx context.Context, uid int64) (domain.AuthorProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorProfile", ctx, uid)
+	ret0, _ := ret[0].(domain.AuthorProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorProfile indicates an expected call of AuthorProfile.
+func (mr *MockArticleServiceMockRecorder) AuthorProfile(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorProfile", reflect.TypeOf((*MockArticleService)(nil).AuthorProfile), ctx, uid)
+}
+
+// CancelSchedule mocks base method.
+func (m *MockArticleService) CancelSchedule(ctx context.Context, art domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockArticleServiceMockRecorder) CancelSchedule(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockArticleService)(nil).CancelSchedule), ctx, art)
+}
+
+// CountTags mocks base method.
+func (m *MockArticleService) CountTags(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTags", ctx, limit)
+	ret0, _ := ret[0].([]domain.TagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTags indicates an expected call of CountTags.
+func (mr *MockArticleServiceMockRecorder) CountTags(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTags", reflect.TypeOf((*MockArticleService)(nil).CountTags), ctx, limit)
+}
+
+// Delete mocks base method.
+func (m *MockArticleService) Delete(ctx context.Context, artId, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, artId, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockArticleServiceMockRecorder) Delete(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArticleService)(nil).Delete), ctx, artId, uid)
+}
+
+// DeleteSeries mocks base method.
+func (m *MockArticleService) DeleteSeries(ctx context.Context, id, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ctx, id, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries.
+func (mr *MockArticleServiceMockRecorder) DeleteSeries(ctx, id, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockArticleService)(nil).DeleteSeries), ctx, id, uid)
+}
+
+// DiffRevision mocks base method.
+func (m *MockArticleService) DiffRevision(ctx context.Context, revId, uid int64) ([]diffx.Line, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevision", ctx, revId, uid)
+	ret0, _ := ret[0].([]diffx.Line)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevision indicates an expected call of DiffRevision.
+func (mr *MockArticleServiceMockRecorder) DiffRevision(ctx, revId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevision", reflect.TypeOf((*MockArticleService)(nil).DiffRevision), ctx, revId, uid)
+}
+
 // GetByAuthor mocks base method.
 func (m *MockArticleService) GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -55,6 +172,21 @@ func (mr *MockArticleServiceMockRecorder) GetByAuthor(ctx, uid, offset, limit an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthor", reflect.TypeOf((*MockArticleService)(nil).GetByAuthor), ctx, uid, offset, limit)
 }
 
+// GetByAuthorAfter mocks base method.
+func (m *MockArticleService) GetByAuthorAfter(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAuthorAfter", ctx, uid, cur, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAuthorAfter indicates an expected call of GetByAuthorAfter.
+func (mr *MockArticleServiceMockRecorder) GetByAuthorAfter(ctx, uid, cur, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthorAfter", reflect.TypeOf((*MockArticleService)(nil).GetByAuthorAfter), ctx, uid, cur, limit)
+}
+
 // GetById mocks base method.
 func (m *MockArticleService) GetById(ctx context.Context, id int64) (domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -85,19 +217,198 @@ func (mr *MockArticleServiceMockRecorder) GetPubById(ctx, id, uid any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubById", reflect.TypeOf((*MockArticleService)(nil).GetPubById), ctx, id, uid)
 }
 
+// GetSeries mocks base method.
+func (m *MockArticleService) GetSeries(ctx context.Context, id, uid int64) (domain.ArticleSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", ctx, id, uid)
+	ret0, _ := ret[0].(domain.ArticleSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockArticleServiceMockRecorder) GetSeries(ctx, id, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockArticleService)(nil).GetSeries), ctx, id, uid)
+}
+
+// InviteCoAuthor mocks base method.
+func (m *MockArticleService) InviteCoAuthor(ctx context.Context, artId, uid, invitee int64, role domain.CoAuthorRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteCoAuthor", ctx, artId, uid, invitee, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InviteCoAuthor indicates an expected call of InviteCoAuthor.
+func (mr *MockArticleServiceMockRecorder) InviteCoAuthor(ctx, artId, uid, invitee, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteCoAuthor", reflect.TypeOf((*MockArticleService)(nil).InviteCoAuthor), ctx, artId, uid, invitee, role)
+}
+
+// ListCoAuthors mocks base method.
+func (m *MockArticleService) ListCoAuthors(ctx context.Context, artId, uid int64) ([]domain.CoAuthor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoAuthors", ctx, artId, uid)
+	ret0, _ := ret[0].([]domain.CoAuthor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoAuthors indicates an expected call of ListCoAuthors.
+func (mr *MockArticleServiceMockRecorder) ListCoAuthors(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoAuthors", reflect.TypeOf((*MockArticleService)(nil).ListCoAuthors), ctx, artId, uid)
+}
+
+// ListDueScheduled mocks base method.
+func (m *MockArticleService) ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueScheduled", ctx, now, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueScheduled indicates an expected call of ListDueScheduled.
+func (mr *MockArticleServiceMockRecorder) ListDueScheduled(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueScheduled", reflect.TypeOf((*MockArticleService)(nil).ListDueScheduled), ctx, now, limit)
+}
+
+// ListExpiredTrash mocks base method.
+func (m *MockArticleService) ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredTrash", ctx, before, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredTrash indicates an expected call of ListExpiredTrash.
+func (mr *MockArticleServiceMockRecorder) ListExpiredTrash(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredTrash", reflect.TypeOf((*MockArticleService)(nil).ListExpiredTrash), ctx, before, limit)
+}
+
 // ListPub mocks base method.
-func (m *MockArticleService) ListPub(ctx context.Context, offset, limit int) ([]domain.Article, error) {
+func (m *MockArticleService) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPub", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "ListPub", ctx, start, offset, limit)
 	ret0, _ := ret[0].([]domain.Article)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListPub indicates an expected call of ListPub.
-func (mr *MockArticleServiceMockRecorder) ListPub(ctx, offset, limit any) *gomock.Call {
+func (mr *MockArticleServiceMockRecorder) ListPub(ctx, start, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleService)(nil).ListPub), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleService)(nil).ListPub), ctx, start, offset, limit)
+}
+
+// ListPubAfter mocks base method.
+func (m *MockArticleService) ListPubAfter(ctx context.Context, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubAfter", ctx, cur, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubAfter indicates an expected call of ListPubAfter.
+func (mr *MockArticleServiceMockRecorder) ListPubAfter(ctx, cur, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubAfter", reflect.TypeOf((*MockArticleService)(nil).ListPubAfter), ctx, cur, limit)
+}
+
+// ListPubByAuthor mocks base method.
+func (m *MockArticleService) ListPubByAuthor(ctx context.Context, uid int64, cur domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByAuthor", ctx, uid, cur, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByAuthor indicates an expected call of ListPubByAuthor.
+func (mr *MockArticleServiceMockRecorder) ListPubByAuthor(ctx, uid, cur, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByAuthor", reflect.TypeOf((*MockArticleService)(nil).ListPubByAuthor), ctx, uid, cur, limit)
+}
+
+// ListPubByIds mocks base method.
+func (m *MockArticleService) ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByIds", ctx, ids)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByIds indicates an expected call of ListPubByIds.
+func (mr *MockArticleServiceMockRecorder) ListPubByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByIds", reflect.TypeOf((*MockArticleService)(nil).ListPubByIds), ctx, ids)
+}
+
+// ListPubByTag mocks base method.
+func (m *MockArticleService) ListPubByTag(ctx context.Context, tag string, offset, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByTag", ctx, tag, offset, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByTag indicates an expected call of ListPubByTag.
+func (mr *MockArticleServiceMockRecorder) ListPubByTag(ctx, tag, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByTag", reflect.TypeOf((*MockArticleService)(nil).ListPubByTag), ctx, tag, offset, limit)
+}
+
+// ListRevisions mocks base method.
+func (m *MockArticleService) ListRevisions(ctx context.Context, artId, uid int64, offset, limit int) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, artId, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockArticleServiceMockRecorder) ListRevisions(ctx, artId, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockArticleService)(nil).ListRevisions), ctx, artId, uid, offset, limit)
+}
+
+// ListSeries mocks base method.
+func (m *MockArticleService) ListSeries(ctx context.Context, uid int64, offset, limit int) ([]domain.ArticleSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSeries", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSeries indicates an expected call of ListSeries.
+func (mr *MockArticleServiceMockRecorder) ListSeries(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSeries", reflect.TypeOf((*MockArticleService)(nil).ListSeries), ctx, uid, offset, limit)
+}
+
+// ListTrash mocks base method.
+func (m *MockArticleService) ListTrash(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockArticleServiceMockRecorder) ListTrash(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockArticleService)(nil).ListTrash), ctx, uid, offset, limit)
 }
 
 // Publish mocks base method.
@@ -115,6 +426,20 @@ func (mr *MockArticleServiceMockRecorder) Publish(ctx, art any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockArticleService)(nil).Publish), ctx, art)
 }
 
+// PublishScheduled mocks base method.
+func (m *MockArticleService) PublishScheduled(ctx context.Context, art domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduled", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishScheduled indicates an expected call of PublishScheduled.
+func (mr *MockArticleServiceMockRecorder) PublishScheduled(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduled", reflect.TypeOf((*MockArticleService)(nil).PublishScheduled), ctx, art)
+}
+
 // PublishV1 mocks base method.
 func (m *MockArticleService) PublishV1(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -130,6 +455,106 @@ func (mr *MockArticleServiceMockRecorder) PublishV1(ctx, art any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishV1", reflect.TypeOf((*MockArticleService)(nil).PublishV1), ctx, art)
 }
 
+// Purge mocks base method.
+func (m *MockArticleService) Purge(ctx context.Context, art domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockArticleServiceMockRecorder) Purge(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockArticleService)(nil).Purge), ctx, art)
+}
+
+// RemoveSeriesArticle mocks base method.
+func (m *MockArticleService) RemoveSeriesArticle(ctx context.Context, seriesId, artId, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSeriesArticle", ctx, seriesId, artId, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSeriesArticle indicates an expected call of RemoveSeriesArticle.
+func (mr *MockArticleServiceMockRecorder) RemoveSeriesArticle(ctx, seriesId, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSeriesArticle", reflect.TypeOf((*MockArticleService)(nil).RemoveSeriesArticle), ctx, seriesId, artId, uid)
+}
+
+// ReorderSeries mocks base method.
+func (m *MockArticleService) ReorderSeries(ctx context.Context, seriesId, uid int64, artIds []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderSeries", ctx, seriesId, uid, artIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderSeries indicates an expected call of ReorderSeries.
+func (mr *MockArticleServiceMockRecorder) ReorderSeries(ctx, seriesId, uid, artIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderSeries", reflect.TypeOf((*MockArticleService)(nil).ReorderSeries), ctx, seriesId, uid, artIds)
+}
+
+// Restore mocks base method.
+func (m *MockArticleService) Restore(ctx context.Context, artId, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, artId, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockArticleServiceMockRecorder) Restore(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockArticleService)(nil).Restore), ctx, artId, uid)
+}
+
+// RevokeCoAuthor mocks base method.
+func (m *MockArticleService) RevokeCoAuthor(ctx context.Context, artId, uid, coAuthor int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeCoAuthor", ctx, artId, uid, coAuthor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeCoAuthor indicates an expected call of RevokeCoAuthor.
+func (mr *MockArticleServiceMockRecorder) RevokeCoAuthor(ctx, artId, uid, coAuthor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeCoAuthor", reflect.TypeOf((*MockArticleService)(nil).RevokeCoAuthor), ctx, artId, uid, coAuthor)
+}
+
+// Role mocks base method.
+func (m *MockArticleService) Role(ctx context.Context, art domain.Article, uid int64) (domain.CoAuthorRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Role", ctx, art, uid)
+	ret0, _ := ret[0].(domain.CoAuthorRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Role indicates an expected call of Role.
+func (mr *MockArticleServiceMockRecorder) Role(ctx, art, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Role", reflect.TypeOf((*MockArticleService)(nil).Role), ctx, art, uid)
+}
+
+// Rollback mocks base method.
+func (m *MockArticleService) Rollback(ctx context.Context, revId, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", ctx, revId, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockArticleServiceMockRecorder) Rollback(ctx, revId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockArticleService)(nil).Rollback), ctx, revId, uid)
+}
+
 // Save mocks base method.
 func (m *MockArticleService) Save(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +570,51 @@ func (mr *MockArticleServiceMockRecorder) Save(ctx, art any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockArticleService)(nil).Save), ctx, art)
 }
 
+// SaveSeries mocks base method.
+func (m *MockArticleService) SaveSeries(ctx context.Context, s domain.ArticleSeries) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSeries", ctx, s)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveSeries indicates an expected call of SaveSeries.
+func (mr *MockArticleServiceMockRecorder) SaveSeries(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSeries", reflect.TypeOf((*MockArticleService)(nil).SaveSeries), ctx, s)
+}
+
+// SchedulePublish mocks base method.
+func (m *MockArticleService) SchedulePublish(ctx context.Context, art domain.Article) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePublish", ctx, art)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchedulePublish indicates an expected call of SchedulePublish.
+func (mr *MockArticleServiceMockRecorder) SchedulePublish(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePublish", reflect.TypeOf((*MockArticleService)(nil).SchedulePublish), ctx, art)
+}
+
+// SeriesNav mocks base method.
+func (m *MockArticleService) SeriesNav(ctx context.Context, artId int64) (domain.SeriesNav, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesNav", ctx, artId)
+	ret0, _ := ret[0].(domain.SeriesNav)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SeriesNav indicates an expected call of SeriesNav.
+func (mr *MockArticleServiceMockRecorder) SeriesNav(ctx, artId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesNav", reflect.TypeOf((*MockArticleService)(nil).SeriesNav), ctx, artId)
+}
+
 // Withdraw mocks base method.
 func (m *MockArticleService) Withdraw(ctx context.Context, art domain.Article) error {
 	m.ctrl.T.Helper()
@@ -2,135 +2,236 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	commentv1 "geektime/webook/api/proto/gen/comment/v1"
 	intrv1 "geektime/webook/api/proto/gen/intr/v1"
 	"geektime/webook/internal/domain"
 	"geektime/webook/internal/repository"
 	"github.com/ecodeclub/ekit/queue"
 	"github.com/ecodeclub/ekit/slice"
-	"math"
 	"time"
 )
 
+var ErrUnknownBoard = errors.New("榜单不存在")
+
 type RankingService interface {
+	// TopN 重新计算有时间段的榜单
 	TopN(ctx context.Context) error
-	GetTopN(ctx context.Context) ([]domain.Article, error)
+	// TopNUnbounded 重新计算不限时间段的榜单，要扫全部帖子，调度得慢一点
+	TopNUnbounded(ctx context.Context) error
+	// GetBoard 分页查询榜单上的帖子，热度高的在前面，撤回了的帖子不会返回
+	GetBoard(ctx context.Context, board string, offset int, limit int) ([]domain.Article, error)
 }
 
 type BatchRankingService struct {
-	repo      repository.RankingRepository
-	artSvc    ArticleService
-	intrSvc   intrv1.InteractiveServiceClient
-	batchSize int
-	n         int
-	scoreFunc func(t time.Time, likeCnt int64) float64
+	repo       repository.RankingRepository
+	artSvc     ArticleService
+	intrSvc    intrv1.InteractiveServiceClient
+	commentSvc commentv1.CommentServiceClient
+	biz        string // 热榜上的资源类型，目前只有帖子
+	batchSize  int
+	// bounded 有时间段的榜单，只需要扫最近的帖子
+	bounded []domain.RankingBoard
+	// unbounded 不限时间段的榜单，要扫全部帖子
+	unbounded []domain.RankingBoard
+	// 用名字找榜单
+	boardIdx map[string]domain.RankingBoard
 }
 
-func NewBatchRankingService(repo repository.RankingRepository, artSvc ArticleService, intrSvc intrv1.InteractiveServiceClient) RankingService {
+func NewBatchRankingService(repo repository.RankingRepository, artSvc ArticleService,
+	intrSvc intrv1.InteractiveServiceClient, commentSvc commentv1.CommentServiceClient,
+	boards []domain.RankingBoard) RankingService {
+	boardIdx := make(map[string]domain.RankingBoard, len(boards))
+	var bounded, unbounded []domain.RankingBoard
+	for _, board := range boards {
+		boardIdx[board.Name] = board
+		if board.Period > 0 {
+			bounded = append(bounded, board)
+		} else {
+			unbounded = append(unbounded, board)
+		}
+	}
 	return &BatchRankingService{
-		repo:      repo,
-		artSvc:    artSvc,
-		intrSvc:   intrSvc,
-		batchSize: 100,
-		n:         100,
-		scoreFunc: func(t time.Time, likeCnt int64) float64 {
-			sec := time.Since(t).Seconds()
-			return float64(likeCnt-1) / math.Pow(float64(sec+2), 1.5)
-		},
+		repo:       repo,
+		artSvc:     artSvc,
+		intrSvc:    intrSvc,
+		commentSvc: commentSvc,
+		biz:        domain.BizArticle,
+		batchSize:  100,
+		bounded:    bounded,
+		unbounded:  unbounded,
+		boardIdx:   boardIdx,
 	}
 }
 
-// GetTopN 查询热榜数据
-func (b *BatchRankingService) GetTopN(ctx context.Context) ([]domain.Article, error) {
-	return b.repo.GetTopN(ctx)
+func (b *BatchRankingService) GetBoard(ctx context.Context, board string, offset int, limit int) ([]domain.Article, error) {
+	if _, ok := b.boardIdx[board]; !ok {
+		return nil, ErrUnknownBoard
+	}
+	items, err := b.repo.GetBoard(ctx, board, offset, limit)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	ids := slice.Map[domain.RankingItem, int64](items, func(idx int, src domain.RankingItem) int64 {
+		return src.BizId
+	})
+	arts, err := b.artSvc.ListPubByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	// ListPubByIds 不保证顺序，按照榜单的顺序排回来
+	artMap := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		artMap[art.Id] = art
+	}
+	res := make([]domain.Article, 0, len(arts))
+	for _, id := range ids {
+		if art, ok := artMap[id]; ok {
+			res = append(res, art)
+		}
+	}
+	return res, nil
 }
 
-// TopN 更新热榜数据
+// TopN 更新有时间段的榜单
 func (b *BatchRankingService) TopN(ctx context.Context) error {
-	//获取热度前100的数据
-	arts, err := b.topN(ctx)
-	if err != nil {
-		return err
-	}
-	// 最终是要放到缓存里面的
-	// 存到缓存里面
-	return b.repo.ReplaceTopN(ctx, arts)
+	return b.refresh(ctx, b.bounded)
+}
+
+// TopNUnbounded 更新不限时间段的榜单
+func (b *BatchRankingService) TopNUnbounded(ctx context.Context) error {
+	return b.refresh(ctx, b.unbounded)
 }
 
-// 更新并获取热榜article
-func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error) {
-	//只取7天内的数据
+// refresh 每个榜单算完了就单独替换，一个榜单失败了不影响别的榜单
+func (b *BatchRankingService) refresh(ctx context.Context, boards []domain.RankingBoard) error {
+	if len(boards) == 0 {
+		return nil
+	}
 	now := time.Now()
-	//先拿一批数据
-	offset := 0
-	type Score struct {
-		art   domain.Article
-		score float64
+	var errs []error
+	err := b.topN(ctx, now, boards, func(board domain.RankingBoard, items []domain.RankingItem) {
+		// 半衰期榜单的分数是按照 now 算的，顺便校正了增量更新的结果
+		er := b.repo.ReplaceBoard(ctx, board.Name, items, now)
+		if er != nil {
+			errs = append(errs, fmt.Errorf("替换榜单 %s 失败 %w", board.Name, er))
+		}
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// topN 扫一遍帖子，同时算 boards 里面的所有榜单
+// 扫过了某个榜单的时间段，这个榜单就算完了，马上交给 done，后面扫描失败也不影响它
+func (b *BatchRankingService) topN(ctx context.Context, now time.Time, boards []domain.RankingBoard,
+	done func(board domain.RankingBoard, items []domain.RankingItem)) error {
+	queues := make([]*queue.ConcurrentPriorityQueue[domain.RankingItem], 0, len(boards))
+	for _, board := range boards {
+		queues = append(queues, queue.NewConcurrentPriorityQueue[domain.RankingItem](board.N,
+			func(src domain.RankingItem, dst domain.RankingItem) int {
+				if src.Score > dst.Score {
+					return 1
+				} else if src.Score == dst.Score {
+					return 0
+				} else {
+					return -1
+				}
+			}))
 	}
-	topN := queue.NewConcurrentPriorityQueue[Score](b.n,
-		func(src Score, dst Score) int {
-			if src.score > dst.score {
-				return 1
-			} else if src.score == dst.score {
-				return 0
-			} else {
-				return -1
-			}
-		})
-	//先拿一批数据
-	for {
-		arts, err := b.artSvc.ListPub(ctx, now, offset, b.batchSize)
+	finished := make([]bool, len(boards))
+	remaining := len(boards)
+	finish := func(i int) {
+		finished[i] = true
+		remaining--
+		q := queues[i]
+		items := make([]domain.RankingItem, q.Len())
+		//小顶堆，从后往前放
+		for j := len(items) - 1; j >= 0; j-- {
+			items[j], _ = q.Dequeue()
+		}
+		done(boards[i], items)
+	}
+	//从现在开始往前翻，扫描过程中有新发表的帖子也不会重复或者漏掉
+	cur := domain.ArticleCursor{Utime: now}
+	for remaining > 0 {
+		arts, err := b.artSvc.ListPubAfter(ctx, cur, b.batchSize)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		cnts := map[int64]domain.RankingCnt{}
+		if len(arts) > 0 {
+			cnts, err = b.cnts(ctx, arts)
+			if err != nil {
+				return err
+			}
 		}
-		//获取ids
-		ids := slice.Map[domain.Article, int64](arts,
-			func(idx int, src domain.Article) int64 {
-				return src.Id
-			})
-		//找对应的点赞数据
-		intrs, err := b.intrSvc.GetByIds(ctx, &intrv1.GetByIdsRequest{
-			Biz: "article",
-			Ids: ids,
-		})
-		//计算score
 		for _, art := range arts {
-			intr, ok := intrs.Intrs[art.Id]
-			//没有对应的点赞数据
-			if !ok {
+			cnt := cnts[art.Id]
+			for i, board := range boards {
+				if finished[i] || !board.Match(art, now) {
+					continue
+				}
+				item := domain.RankingItem{
+					BizId: art.Id,
+					Score: board.Formula.Score(cnt, now.Sub(art.Utime)),
+				}
+				//队列已满，比最小的大才替换
+				if queues[i].Len() >= board.N {
+					peek, _ := queues[i].Peek()
+					if peek.Score >= item.Score {
+						continue
+					}
+					_, _ = queues[i].Dequeue()
+				}
+				_ = queues[i].Enqueue(item)
+			}
+		}
+		last := len(arts) < b.batchSize
+		for i, board := range boards {
+			if finished[i] {
 				continue
 			}
-			score := b.scoreFunc(art.Utime, intr.LikeCnt)
-			//队列未满直接入队
-			err = topN.Enqueue(Score{
-				art:   art,
-				score: score,
-			})
-			//队列已满，考虑score在不在前100名，在就替换score最小的数据
-			if err == queue.ErrOutOfCapacity {
-				val, _ := topN.Dequeue()
-				if val.score < score {
-					topN.Enqueue(Score{
-						art:   art,
-						score: score,
-					})
-				}
+			//没有下一批数据了，或者已经超出了这个榜单的时间段
+			if last || (board.Period > 0 && now.Sub(arts[len(arts)-1].Utime) > board.Period) {
+				finish(i)
 			}
 		}
-		//如果没有下一批数据了，或已经到了7天前的数据
-		if len(arts) < b.batchSize || now.Sub(arts[len(arts)-1].Utime).Hours() > 7*24 {
+		if last {
 			break
 		}
-		offset = offset + len(arts)
+		cur = domain.NextArticleCursor(arts, b.batchSize)
 	}
-	//最后得出结果
-	res := make([]domain.Article, b.n)
-	for i := b.n - 1; i >= 0; i-- {
-		val, err := topN.Dequeue()
-		//取完了不够n个数据
-		if err != nil {
-			break
+	return nil
+}
+
+// cnts 一批帖子的点赞、阅读、收藏和评论数
+func (b *BatchRankingService) cnts(ctx context.Context, arts []domain.Article) (map[int64]domain.RankingCnt, error) {
+	ids := slice.Map[domain.Article, int64](arts, func(idx int, src domain.Article) int64 {
+		return src.Id
+	})
+	intrs, err := b.intrSvc.GetByIds(ctx, &intrv1.GetByIdsRequest{
+		Biz: b.biz,
+		Ids: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	comments, err := b.commentSvc.CountByBiz(ctx, &commentv1.CountByBizRequest{
+		Biz:    b.biz,
+		Bizids: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]domain.RankingCnt, len(ids))
+	for _, id := range ids {
+		intr := intrs.GetIntrs()[id]
+		res[id] = domain.RankingCnt{
+			Like:    intr.GetLikeCnt(),
+			Read:    intr.GetReadCnt(),
+			Collect: intr.GetCollectCnt(),
+			Comment: comments.GetCnts()[id],
 		}
-		res[i] = val.art
 	}
 	return res, nil
 }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/repository"
+	"time"
+)
+
+// IncrRankingService 根据点赞、收藏、阅读数的变化增量更新半衰期榜单
+// 评论数不在里面，和其它误差一起靠 BatchRankingService 定期全量校正
+type IncrRankingService interface {
+	// Incr cnts 是每个帖子的计数变化
+	Incr(ctx context.Context, cnts map[int64]domain.RankingCnt) error
+	// Rebase 把分数衰减到现在，不然分数会越来越大
+	Rebase(ctx context.Context) error
+}
+
+type incrRankingService struct {
+	repo   repository.RankingRepository
+	artSvc ArticleService
+	// 只有半衰期榜单
+	boards []domain.RankingBoard
+}
+
+func NewIncrRankingService(repo repository.RankingRepository, artSvc ArticleService,
+	boards []domain.RankingBoard) IncrRankingService {
+	res := &incrRankingService{
+		repo:   repo,
+		artSvc: artSvc,
+	}
+	for _, board := range boards {
+		if board.Incremental() {
+			res.boards = append(res.boards, board)
+		}
+	}
+	return res
+}
+
+func (s *incrRankingService) Incr(ctx context.Context, cnts map[int64]domain.RankingCnt) error {
+	if len(s.boards) == 0 || len(cnts) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(cnts))
+	for id := range cnts {
+		ids = append(ids, id)
+	}
+	// 撤回了的帖子查不到，也就不会上榜
+	arts, err := s.artSvc.ListPubByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, board := range s.boards {
+		deltas := make([]domain.RankingDelta, 0, len(arts))
+		for _, art := range arts {
+			if !board.Match(art, now) {
+				continue
+			}
+			weighted := board.Formula.Weighted(cnts[art.Id])
+			if weighted == 0 {
+				continue
+			}
+			deltas = append(deltas, domain.RankingDelta{
+				BizId:    art.Id,
+				Utime:    art.Utime,
+				Weighted: weighted,
+			})
+		}
+		err = s.repo.IncrBoard(ctx, board.Name, board.Formula.HalfLife, deltas)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *incrRankingService) Rebase(ctx context.Context) error {
+	for _, board := range s.boards {
+		err := s.repo.RebaseBoard(ctx, board.Name, board.Formula.HalfLife, board.N)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
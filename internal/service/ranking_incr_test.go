@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/internal/domain"
+	repomocks "geektime/webook/internal/repository/mocks"
+	svcmocks "geektime/webook/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestIncrRankingService_Incr(t *testing.T) {
+	now := time.Now()
+	boards := []domain.RankingBoard{
+		{
+			Name:    "realtime",
+			Period:  time.Hour * 24,
+			N:       100,
+			Formula: domain.RankingFormula{Like: 1, Read: 0.5, HalfLife: time.Hour},
+		},
+		{
+			Name:    "go",
+			Tag:     "go",
+			N:       100,
+			Formula: domain.RankingFormula{Collect: 2, HalfLife: time.Hour * 2},
+		},
+		{
+			// 不是半衰期榜单，只能全量计算
+			Name:    "all_time",
+			N:       100,
+			Formula: domain.RankingFormula{Like: 1},
+		},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (ArticleService, *repomocks.MockRankingRepository)
+		cnts map[int64]domain.RankingCnt
+
+		wantErr error
+	}{
+		{
+			name: "按照榜单过滤并且加权",
+			cnts: map[int64]domain.RankingCnt{
+				1: {Like: 2, Read: 4},
+				2: {Collect: 1},
+				3: {Like: 1},
+			},
+			mock: func(ctrl *gomock.Controller) (ArticleService, *repomocks.MockRankingRepository) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				// 帖子 3 撤回了
+				artSvc.EXPECT().ListPubByIds(gomock.Any(), gomock.Any()).
+					Return([]domain.Article{
+						{Id: 1, Utime: now},
+						{Id: 2, Utime: now.Add(-time.Hour * 48), Tags: []string{"go"}},
+					}, nil)
+				// 帖子 2 超出了日榜的时间段，帖子 1 没有收藏的变化
+				repo.EXPECT().IncrBoard(gomock.Any(), "realtime", time.Hour,
+					[]domain.RankingDelta{{BizId: 1, Utime: now, Weighted: 4}}).Return(nil)
+				repo.EXPECT().IncrBoard(gomock.Any(), "go", time.Hour*2,
+					[]domain.RankingDelta{{BizId: 2, Utime: now.Add(-time.Hour * 48), Weighted: 2}}).Return(nil)
+				return artSvc, repo
+			},
+		},
+		{
+			name: "查询帖子失败",
+			cnts: map[int64]domain.RankingCnt{1: {Like: 1}},
+			mock: func(ctrl *gomock.Controller) (ArticleService, *repomocks.MockRankingRepository) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{1}).
+					Return(nil, errors.New("mock error"))
+				return artSvc, repo
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			artSvc, repo := tc.mock(ctrl)
+			svc := NewIncrRankingService(repo, artSvc, boards)
+			err := svc.Incr(context.Background(), tc.cnts)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestIncrRankingService_Rebase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockRankingRepository(ctrl)
+	svc := NewIncrRankingService(repo, nil, []domain.RankingBoard{
+		{Name: "realtime", N: 50, Formula: domain.RankingFormula{HalfLife: time.Hour}},
+		{Name: "daily", N: 100, Formula: domain.RankingFormula{Gravity: 1.5}},
+	})
+	repo.EXPECT().RebaseBoard(gomock.Any(), "realtime", time.Hour, 50).Return(nil)
+	assert.NoError(t, svc.Rebase(context.Background()))
+}
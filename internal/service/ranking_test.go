@@ -1,74 +1,151 @@
-//go:build need_fix
-
 package service
 
 import (
 	"context"
+	"errors"
+	commentv1 "geektime/webook/api/proto/gen/comment/v1"
+	commentv1mocks "geektime/webook/api/proto/gen/comment/v1/mocks"
 	intrv1 "geektime/webook/api/proto/gen/intr/v1"
-	domain2 "geektime/webook/interactive/domain"
-	"geektime/webook/interactive/service"
+	intrv1mocks "geektime/webook/api/proto/gen/intr/v1/mocks"
 	"geektime/webook/internal/domain"
+	repomocks "geektime/webook/internal/repository/mocks"
 	svcmocks "geektime/webook/internal/service/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"testing"
 	"time"
 )
 
 func TestRankingTopN(t *testing.T) {
-	const batchSize = 2
 	now := time.Now()
+	boards := []domain.RankingBoard{
+		{
+			Name:    "daily",
+			Period:  time.Hour * 24,
+			N:       2,
+			Formula: domain.RankingFormula{Like: 1},
+		},
+		{
+			Name:    "go",
+			Tag:     "go",
+			N:       2,
+			Formula: domain.RankingFormula{Read: 1, Comment: 10},
+		},
+	}
 	testCases := []struct {
 		name string
 
-		mock func(ctrl *gomock.Controller) (service.InteractiveService, ArticleService)
+		mock func(ctrl *gomock.Controller) (ArticleService,
+			intrv1.InteractiveServiceClient, commentv1.CommentServiceClient)
 
-		wantArts []domain.Article
-		wantErr  error
+		wantBoards map[string][]domain.RankingItem
+		wantErr    error
 	}{
 		{
-			name: "成功获取",
-			mock: func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient, ArticleService) {
-				intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+			name: "每个榜单单独排序",
+			mock: func(ctrl *gomock.Controller) (ArticleService,
+				intrv1.InteractiveServiceClient, commentv1.CommentServiceClient) {
 				artSvc := svcmocks.NewMockArticleService(ctrl)
-				// 先模拟批量获取数据
-				// 先模拟第一批
-				artSvc.EXPECT().ListPub(gomock.Any(), 0, 3).
-					Return([]domain.Article{
-						{Id: 1, Utime: now},
-						{Id: 2, Utime: now},
-						{Id: 3, Utime: now},
-					}, nil)
-				// 模拟第二批
-				artSvc.EXPECT().ListPub(gomock.Any(), 3, 3).
+				intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+				commentSvc := commentv1mocks.NewMockCommentServiceClient(ctrl)
+				first := []domain.Article{
+					{Id: 5, Utime: now.Add(-time.Hour), Tags: []string{"go"}},
+					{Id: 4, Utime: now.Add(-time.Hour * 2)},
+					{Id: 3, Utime: now.Add(-time.Hour * 3), Tags: []string{"go"}},
+				}
+				artSvc.EXPECT().ListPubAfter(gomock.Any(), domain.ArticleCursor{Utime: now}, 3).
+					Return(first, nil)
+				// 第二批已经不在日榜的时间段里面了，但是标签榜不限时间
+				artSvc.EXPECT().ListPubAfter(gomock.Any(), domain.NextArticleCursor(first, 3), 3).
 					Return([]domain.Article{
-						{Id: 4, Utime: now},
-						{Id: 5, Utime: now},
+						{Id: 2, Utime: now.Add(-time.Hour * 48), Tags: []string{"go"}},
+						{Id: 1, Utime: now.Add(-time.Hour * 49)},
 					}, nil)
-
-				// 第一批的点赞数据
-				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{1, 2, 3}).
-					Return(map[int64]domain2.Interactive{
-						1: {LikeCnt: 1},
-						2: {LikeCnt: 1},
-						3: {LikeCnt: 2},
-					}, nil)
-
-				// 第二批的点赞数据
-				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{4, 5}).
-					Return(map[int64]domain2.Interactive{
-						4: {LikeCnt: 1},
-						5: {LikeCnt: 4},
+				intrSvc.EXPECT().GetByIds(gomock.Any(), &intrv1.GetByIdsRequest{
+					Biz: domain.BizArticle, Ids: []int64{5, 4, 3},
+				}).Return(&intrv1.GetByIdsResponse{
+					Intrs: map[int64]*intrv1.Interactive{
+						5: {LikeCnt: 1, ReadCnt: 10},
+						4: {LikeCnt: 3},
+						3: {LikeCnt: 2, ReadCnt: 5},
+					},
+				}, nil)
+				intrSvc.EXPECT().GetByIds(gomock.Any(), &intrv1.GetByIdsRequest{
+					Biz: domain.BizArticle, Ids: []int64{2, 1},
+				}).Return(&intrv1.GetByIdsResponse{
+					Intrs: map[int64]*intrv1.Interactive{
+						2: {LikeCnt: 100, ReadCnt: 1},
+						1: {LikeCnt: 100},
+					},
+				}, nil)
+				commentSvc.EXPECT().CountByBiz(gomock.Any(), &commentv1.CountByBizRequest{
+					Biz: domain.BizArticle, Bizids: []int64{5, 4, 3},
+				}).Return(&commentv1.CountByBizResponse{
+					Cnts: map[int64]int64{3: 1},
+				}, nil)
+				commentSvc.EXPECT().CountByBiz(gomock.Any(), &commentv1.CountByBizRequest{
+					Biz: domain.BizArticle, Bizids: []int64{2, 1},
+				}).Return(&commentv1.CountByBizResponse{
+					Cnts: map[int64]int64{2: 2},
+				}, nil)
+				return artSvc, intrSvc, commentSvc
+			},
+			wantBoards: map[string][]domain.RankingItem{
+				"daily": {{BizId: 4, Score: 3}, {BizId: 3, Score: 2}},
+				"go":    {{BizId: 2, Score: 21}, {BizId: 3, Score: 15}},
+			},
+		},
+		{
+			// 第一批就已经超出了日榜的时间段，日榜先算完，后面失败了也不影响
+			name: "扫描失败之前算完的榜单照样返回",
+			mock: func(ctrl *gomock.Controller) (ArticleService,
+				intrv1.InteractiveServiceClient, commentv1.CommentServiceClient) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+				commentSvc := commentv1mocks.NewMockCommentServiceClient(ctrl)
+				first := []domain.Article{
+					{Id: 3, Utime: now.Add(-time.Hour)},
+					{Id: 2, Utime: now.Add(-time.Hour * 2), Tags: []string{"go"}},
+					{Id: 1, Utime: now.Add(-time.Hour * 25)},
+				}
+				artSvc.EXPECT().ListPubAfter(gomock.Any(), domain.ArticleCursor{Utime: now}, 3).
+					Return(first, nil)
+				artSvc.EXPECT().ListPubAfter(gomock.Any(), domain.NextArticleCursor(first, 3), 3).
+					Return(nil, errors.New("mock error"))
+				intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any()).
+					Return(&intrv1.GetByIdsResponse{
+						Intrs: map[int64]*intrv1.Interactive{
+							3: {LikeCnt: 1},
+							2: {LikeCnt: 2},
+						},
 					}, nil)
-
-				return intrSvc, artSvc
+				commentSvc.EXPECT().CountByBiz(gomock.Any(), gomock.Any()).
+					Return(&commentv1.CountByBizResponse{}, nil)
+				return artSvc, intrSvc, commentSvc
 			},
-
-			wantErr: nil,
-			wantArts: []domain.Article{
-				{Id: 5, Utime: now},
-				{Id: 3, Utime: now},
+			wantBoards: map[string][]domain.RankingItem{
+				"daily": {{BizId: 2, Score: 2}, {BizId: 3, Score: 1}},
+			},
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name: "查询评论数失败",
+			mock: func(ctrl *gomock.Controller) (ArticleService,
+				intrv1.InteractiveServiceClient, commentv1.CommentServiceClient) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+				commentSvc := commentv1mocks.NewMockCommentServiceClient(ctrl)
+				artSvc.EXPECT().ListPubAfter(gomock.Any(), domain.ArticleCursor{Utime: now}, 3).
+					Return([]domain.Article{{Id: 1, Utime: now}}, nil)
+				intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any()).
+					Return(&intrv1.GetByIdsResponse{}, nil)
+				commentSvc.EXPECT().CountByBiz(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("mock error"))
+				return artSvc, intrSvc, commentSvc
 			},
+			wantBoards: map[string][]domain.RankingItem{},
+			wantErr:    errors.New("mock error"),
 		},
 	}
 
@@ -76,13 +153,61 @@ func TestRankingTopN(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
-			intrSvc, artSvc := tc.mock(ctrl)
-			svc := NewBatchRankingService(artSvc, intrSvc)
+			artSvc, intrSvc, commentSvc := tc.mock(ctrl)
+			svc := NewBatchRankingService(nil, artSvc, intrSvc, commentSvc, boards).(*BatchRankingService)
 			svc.batchSize = 3
-			svc.n = 2
-			arts, err := svc.topN(context.Background())
+			res := map[string][]domain.RankingItem{}
+			err := svc.topN(context.Background(), now, boards,
+				func(board domain.RankingBoard, items []domain.RankingItem) {
+					res[board.Name] = items
+				})
 			assert.Equal(t, tc.wantErr, err)
-			assert.Equal(t, tc.wantArts, arts)
+			assert.Equal(t, tc.wantBoards, res)
 		})
 	}
 }
+
+func TestRankingTopN_ReplaceEachBoard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	boards := []domain.RankingBoard{
+		{Name: "daily", Period: time.Hour * 24, N: 10, Formula: domain.RankingFormula{Like: 1}},
+		{Name: "weekly", Period: time.Hour * 168, N: 10, Formula: domain.RankingFormula{Like: 1}},
+		// 不限时间段的榜单不在 TopN 里面算
+		{Name: "all_time", N: 10, Formula: domain.RankingFormula{Like: 1}},
+	}
+	artSvc := svcmocks.NewMockArticleService(ctrl)
+	intrSvc := intrv1mocks.NewMockInteractiveServiceClient(ctrl)
+	commentSvc := commentv1mocks.NewMockCommentServiceClient(ctrl)
+	repo := repomocks.NewMockRankingRepository(ctrl)
+	artSvc.EXPECT().ListPubAfter(gomock.Any(), gomock.Any(), 100).Return(nil, nil)
+	// 日榜替换失败了，周榜照样替换
+	repo.EXPECT().ReplaceBoard(gomock.Any(), "daily", gomock.Any(), gomock.Any()).
+		Return(errors.New("mock error"))
+	repo.EXPECT().ReplaceBoard(gomock.Any(), "weekly", gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := NewBatchRankingService(repo, artSvc, intrSvc, commentSvc, boards)
+	err := svc.TopN(context.Background())
+	assert.ErrorContains(t, err, "mock error")
+}
+
+func TestRankingGetBoard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockRankingRepository(ctrl)
+	artSvc := svcmocks.NewMockArticleService(ctrl)
+	boards := []domain.RankingBoard{{Name: "daily", N: 100}}
+	svc := NewBatchRankingService(repo, artSvc, nil, nil, boards)
+
+	_, err := svc.GetBoard(context.Background(), "monthly", 0, 10)
+	assert.Equal(t, ErrUnknownBoard, err)
+
+	repo.EXPECT().GetBoard(gomock.Any(), "daily", 0, 10).
+		Return([]domain.RankingItem{{BizId: 3, Score: 3}, {BizId: 1, Score: 2}, {BizId: 2, Score: 1}}, nil)
+	// 帖子 1 已经撤回了，顺序也和榜单不一样
+	artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{3, 1, 2}).
+		Return([]domain.Article{{Id: 2}, {Id: 3}}, nil)
+	arts, err := svc.GetBoard(context.Background(), "daily", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Article{{Id: 3}, {Id: 2}}, arts)
+}
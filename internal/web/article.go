@@ -1,13 +1,18 @@
 package web
 
 import (
+	"errors"
 	intrv1 "geektime/webook/api/proto/gen/intr/v1"
 	"geektime/webook/internal/domain"
+	"geektime/webook/internal/errs"
 	"geektime/webook/internal/service"
 	jwt2 "geektime/webook/internal/web/jwt"
+	"geektime/webook/pkg/diffx"
 	"geektime/webook/pkg/logger"
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"net/http"
 	"strconv"
 	"time"
@@ -17,15 +22,18 @@ type ArticleHandler struct {
 	svc     service.ArticleService
 	l       logger.LoggerV1
 	intrSvc intrv1.InteractiveServiceClient
+	abuse   service.AntiAbuseService
 	biz     string
 }
 
-func NewArticleHandler(articleSvc service.ArticleService, l logger.LoggerV1, intrSvc intrv1.InteractiveServiceClient) *ArticleHandler {
+func NewArticleHandler(articleSvc service.ArticleService, l logger.LoggerV1,
+	intrSvc intrv1.InteractiveServiceClient, abuse service.AntiAbuseService) *ArticleHandler {
 	return &ArticleHandler{
 		svc:     articleSvc,
 		l:       l,
 		intrSvc: intrSvc,
-		biz:     "article",
+		abuse:   abuse,
+		biz:     domain.BizArticle,
 	}
 }
 
@@ -34,24 +42,56 @@ func (h *ArticleHandler) RegisterRoutes(r *gin.Engine) {
 
 	g.POST("/edit", h.Edit)
 	g.POST("/publish", h.Publish)
+	g.POST("/publish/cancel", h.CancelPublish)
 	g.POST("/withdraw", h.Withdraw)
 	// 创作者接口
 	// 按照道理来说，这边就是 GET 方法 /list?offset=?&limit=?
 	g.POST("/list", h.List)
+	// 游标翻页，返回 nextCursor，/list 的返回值是数组，不能改
+	g.POST("/list/cursor", h.ListByCursor)
 	g.GET("/detail/:id", h.Detail)
+	// 历史版本
+	g.GET("/revisions/:id", h.Revisions)
+	g.GET("/revisions/:id/diff", h.RevisionDiff)
+	g.POST("/rollback", h.Rollback)
+	// 合作者
+	g.POST("/coauthors/invite", h.InviteCoAuthor)
+	g.POST("/coauthors/accept", h.AcceptCoAuthor)
+	g.POST("/coauthors/revoke", h.RevokeCoAuthor)
+	g.GET("/coauthors/:id", h.CoAuthors)
+
+	g.POST("/delete", h.Delete)
+	g.POST("/restore", h.Restore)
+	g.POST("/trash", h.Trash)
+	// 专栏
+	g.POST("/series/edit", h.EditSeries)
+	g.POST("/series/delete", h.DeleteSeries)
+	g.POST("/series/list", h.ListSeries)
+	g.GET("/series/:id", h.SeriesDetail)
+	g.POST("/series/articles/add", h.AddSeriesArticle)
+	g.POST("/series/articles/remove", h.RemoveSeriesArticle)
+	g.POST("/series/reorder", h.ReorderSeries)
 
 	pub := g.Group("/pub")
 	pub.GET("/:id", h.PubDetail)
+	// 按照标签浏览 /tag/:tag?offset=?&limit=?
+	pub.GET("/tag/:tag", h.PubListByTag)
+	pub.GET("/tags", h.TagCounts)
 	//点赞或取消点赞
 	pub.POST("/like", h.Like)
 	pub.POST("/collect", h.Collect)
+	pub.POST("/collect/cancel", h.CancelCollect)
 }
 
 func (h *ArticleHandler) Edit(ctx *gin.Context) {
 	type Req struct {
-		Id      int64  `json:"id"`
-		Title   string `json:"title,omitempty"`
-		Content string `json:"content,omitempty"`
+		Id       int64    `json:"id"`
+		Title    string   `json:"title,omitempty"`
+		Content  string   `json:"content,omitempty"`
+		Category string   `json:"category,omitempty"`
+		Tags     []string `json:"tags,omitempty"`
+		// 修改已有的帖子时带上 Detail 返回的版本号
+		Version int64 `json:"version"`
 	}
 	var req Req
 	if err := ctx.Bind(&req); err != nil {
@@ -68,13 +108,40 @@ func (h *ArticleHandler) Edit(ctx *gin.Context) {
 		return
 	}
 	id, err := h.svc.Save(ctx, domain.Article{
-		Id:      req.Id,
-		Title:   req.Title,
-		Content: req.Content,
+		Id:       req.Id,
+		Title:    req.Title,
+		Content:  req.Content,
+		Category: req.Category,
+		Tags:     req.Tags,
+		Version:  req.Version,
 		Author: domain.Author{
 			Id: claims.Uid,
 		},
 	})
+	if errors.Is(err, service.ErrIllegalTags) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "标签或者分类不合法",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrPermissionDenied) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrArticleInTrash) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子在回收站里面，请先恢复",
+		})
+		return
+	}
+	if h.versionConflict(ctx, err) {
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -89,11 +156,19 @@ func (h *ArticleHandler) Edit(ctx *gin.Context) {
 	})
 }
 
+// Publish 发表帖子，带上 publish_at 就是定时发表
+// 定时发表的帖子在发出去之前，可以再次调用这个接口修改发表时间
 func (h *ArticleHandler) Publish(ctx *gin.Context) {
 	type Req struct {
-		Id      int64  `json:"id"`
-		Title   string `json:"title,omitempty"`
-		Content string `json:"content,omitempty"`
+		Id       int64    `json:"id"`
+		Title    string   `json:"title,omitempty"`
+		Content  string   `json:"content,omitempty"`
+		Category string   `json:"category,omitempty"`
+		Tags     []string `json:"tags,omitempty"`
+		// 修改已有的帖子时带上 Detail 返回的版本号
+		Version int64 `json:"version"`
+		// 定时发表的时间，毫秒数，不传就是立刻发表
+		PublishAt int64 `json:"publish_at,omitempty"`
 	}
 	var req Req
 	if err := ctx.Bind(&req); err != nil {
@@ -109,14 +184,58 @@ func (h *ArticleHandler) Publish(ctx *gin.Context) {
 		h.l.Error("未发现用户的session信息")
 		return
 	}
-	id, err := h.svc.Publish(ctx, domain.Article{
-		Id:      req.Id,
-		Title:   req.Title,
-		Content: req.Content,
+	art := domain.Article{
+		Id:       req.Id,
+		Title:    req.Title,
+		Content:  req.Content,
+		Category: req.Category,
+		Tags:     req.Tags,
+		Version:  req.Version,
 		Author: domain.Author{
 			Id: claims.Uid,
 		},
-	})
+	}
+	var (
+		id  int64
+		err error
+	)
+	if req.PublishAt > 0 {
+		art.PublishAt = time.UnixMilli(req.PublishAt)
+		if art.PublishAt.Before(time.Now()) {
+			ctx.JSON(http.StatusOK, Result{
+				Code: 4,
+				Msg:  "发表时间不能早于当前时间",
+			})
+			return
+		}
+		id, err = h.svc.SchedulePublish(ctx, art)
+	} else {
+		id, err = h.svc.Publish(ctx, art)
+	}
+	if errors.Is(err, service.ErrIllegalTags) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "标签或者分类不合法",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrPermissionDenied) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrArticleInTrash) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子在回收站里面，请先恢复",
+		})
+		return
+	}
+	if h.versionConflict(ctx, err) {
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -132,6 +251,68 @@ func (h *ArticleHandler) Publish(ctx *gin.Context) {
 
 }
 
+// versionConflict 帖子已经被改过的时候把最新的版本号返回给前端，由前端提示合并
+func (h *ArticleHandler) versionConflict(ctx *gin.Context, err error) bool {
+	var conflict service.ArticleVersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Code: errs.ArticleVersionConflict,
+		Msg:  "帖子已经被修改过了",
+		Data: conflict.Current,
+	})
+	return true
+}
+
+// CancelPublish 取消定时发表，帖子回到未发表状态
+func (h *ArticleHandler) CancelPublish(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.CancelSchedule(ctx, domain.Article{
+		Id: req.Id,
+		Author: domain.Author{
+			Id: uc.Uid,
+		},
+	})
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "Ok",
+		})
+	case errors.Is(err, service.ErrArticleNotScheduled):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子已经发表或者没有定时发表",
+		})
+	case errors.Is(err, service.ErrArticleInTrash):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子在回收站里面，请先恢复",
+		})
+	case errors.Is(err, service.ErrPermissionDenied):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("取消定时发表失败",
+			logger.Int64("id", req.Id),
+			logger.Int64("uid", uc.Uid),
+			logger.Error(err))
+	}
+}
+
 // Withdraw 设置帖子不可见
 func (h *ArticleHandler) Withdraw(ctx *gin.Context) {
 	type Req struct {
@@ -157,6 +338,13 @@ func (h *ArticleHandler) Withdraw(ctx *gin.Context) {
 			Id: claims.Uid,
 		},
 	})
+	if errors.Is(err, service.ErrPermissionDenied) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -191,20 +379,67 @@ func (h *ArticleHandler) List(ctx *gin.Context) {
 		return
 	}
 	ctx.JSON(http.StatusOK, Result{
-		Data: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
-			return ArticleVo{
-				Id:    src.Id,
-				Title: src.Title,
-				//摘要
-				Abstract: src.Abstract(),
-				//Content:  src.Content,
-				AuthorId: src.Author.Id,
-				// 列表，你不需要
-				Status: src.Status.ToUint8(),
-				Ctime:  src.Ctime.Format(time.DateTime),
-				Utime:  src.Utime.Format(time.DateTime),
-			}
-		}),
+		Data: h.toListVo(arts),
+	})
+}
+
+// ListByCursor 作者的帖子列表，cursor 是上一页返回的 nextCursor，第一页不传
+func (h *ArticleHandler) ListByCursor(ctx *gin.Context) {
+	type Req struct {
+		Cursor string `json:"cursor"`
+		Limit  int    `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	cur, err := domain.ParseArticleCursor(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "cursor 参数错误",
+		})
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	arts, err := h.svc.GetByAuthorAfter(ctx, uc.Uid, cur, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查找文章列表失败",
+			logger.Error(err),
+			logger.Int("limit", req.Limit),
+			logger.String("cursor", req.Cursor),
+			logger.Int64("uid", uc.Uid))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: ArticleListVo{
+			NextCursor: domain.NextArticleCursor(arts, req.Limit).Encode(),
+			Articles:   h.toListVo(arts),
+		},
+	})
+}
+
+func (h *ArticleHandler) toListVo(arts []domain.Article) []ArticleVo {
+	return slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+		return ArticleVo{
+			Id:    src.Id,
+			Title: src.Title,
+			//摘要
+			Abstract: src.Abstract(),
+			//Content:  src.Content,
+			AuthorId: src.Author.Id,
+			// 列表，你不需要
+			Status:    src.Status.ToUint8(),
+			Category:  src.Category,
+			Tags:      src.Tags,
+			PublishAt: formatPublishAt(src.PublishAt),
+			Ctime:     src.Ctime.Format(time.DateTime),
+			Utime:     src.Utime.Format(time.DateTime),
+		}
 	})
 }
 
@@ -233,7 +468,20 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 		return
 	}
 	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
-	if art.Author.Id != uc.Uid {
+	role, err := h.svc.Role(ctx, art, uc.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "系统错误",
+			Code: 5,
+		})
+		h.l.Error("查询合作者失败",
+			logger.Int64("id", id),
+			logger.Int64("uid", uc.Uid),
+			logger.Error(err))
+		return
+	}
+	// 作者和接受了邀请的合作者才能看制作库的帖子
+	if !role.CanView() {
 		// 有人在搞鬼
 		ctx.JSON(http.StatusOK, Result{
 			Msg:  "系统错误",
@@ -253,13 +501,375 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 		Content:  art.Content,
 		AuthorId: art.Author.Id,
 		// 列表，你不需要
-		Status: art.Status.ToUint8(),
-		Ctime:  art.Ctime.Format(time.DateTime),
-		Utime:  art.Utime.Format(time.DateTime),
+		Status:    art.Status.ToUint8(),
+		Category:  art.Category,
+		Tags:      art.Tags,
+		PublishAt: formatPublishAt(art.PublishAt),
+		Version:   art.Version,
+		Ctime:     art.Ctime.Format(time.DateTime),
+		Utime:     art.Utime.Format(time.DateTime),
 	}
 	ctx.JSON(http.StatusOK, Result{Data: vo})
 }
 
+// Revisions 查看帖子的历史版本，id 是帖子 id
+func (h *ArticleHandler) Revisions(ctx *gin.Context) {
+	idstr := ctx.Param("id")
+	id, err := strconv.ParseInt(idstr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		h.l.Warn("查询历史版本失败，id 格式不对",
+			logger.String("id", idstr),
+			logger.Error(err))
+		return
+	}
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	revs, err := h.svc.ListRevisions(ctx, id, uc.Uid, offset, limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "系统错误",
+			Code: 5,
+		})
+		h.l.Error("查询历史版本失败",
+			logger.Int64("id", id),
+			logger.Int64("uid", uc.Uid),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[domain.ArticleRevision, ArticleRevisionVo](revs,
+			func(idx int, src domain.ArticleRevision) ArticleRevisionVo {
+				return ArticleRevisionVo{
+					Id:        src.Id,
+					ArticleId: src.Article.Id,
+					Title:     src.Article.Title,
+					Abstract:  src.Article.Abstract(),
+					Status:    src.Article.Status.ToUint8(),
+					Ctime:     src.Ctime.Format(time.DateTime),
+				}
+			}),
+	})
+}
+
+// RevisionDiff 比较历史版本和当前内容，id 是历史版本的 id
+func (h *ArticleHandler) RevisionDiff(ctx *gin.Context) {
+	idstr := ctx.Param("id")
+	id, err := strconv.ParseInt(idstr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		h.l.Warn("比较历史版本失败，id 格式不对",
+			logger.String("id", idstr),
+			logger.Error(err))
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	lines, err := h.svc.DiffRevision(ctx, id, uc.Uid)
+	if errors.Is(err, service.ErrRevisionDiffTooLarge) {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "改动太多，无法比较",
+			Code: 4,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "系统错误",
+			Code: 5,
+		})
+		h.l.Error("比较历史版本失败",
+			logger.Int64("revId", id),
+			logger.Int64("uid", uc.Uid),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[diffx.Line, DiffLineVo](lines,
+			func(idx int, src diffx.Line) DiffLineVo {
+				return DiffLineVo{
+					Op:   src.Op.String(),
+					Text: src.Text,
+				}
+			}),
+	})
+}
+
+// Rollback 恢复到某个历史版本
+func (h *ArticleHandler) Rollback(ctx *gin.Context) {
+	type Req struct {
+		RevisionId int64 `json:"revisionId"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	id, err := h.svc.Rollback(ctx, req.RevisionId, uc.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("回滚帖子失败",
+			logger.Int64("revId", req.RevisionId),
+			logger.Int64("uid", uc.Uid),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "Ok",
+		Data: id,
+	})
+}
+
+// InviteCoAuthor 邀请别人一起写，role 是 2（可以修改和发表）或者 3（只能看）
+func (h *ArticleHandler) InviteCoAuthor(ctx *gin.Context) {
+	type Req struct {
+		Id   int64 `json:"id"`
+		Uid  int64 `json:"uid"`
+		Role uint8 `json:"role"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.InviteCoAuthor(ctx, req.Id, uc.Uid, req.Uid, domain.CoAuthorRole(req.Role))
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "Ok",
+		})
+	case errors.Is(err, service.ErrPermissionDenied):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+	case errors.Is(err, service.ErrIllegalCoAuthor):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "不能邀请自己或者角色不对",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("邀请合作者失败",
+			logger.Int64("id", req.Id),
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("invitee", req.Uid),
+			logger.Error(err))
+	}
+}
+
+// AcceptCoAuthor 接受帖子的邀请
+func (h *ArticleHandler) AcceptCoAuthor(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.AcceptCoAuthor(ctx, req.Id, uc.Uid)
+	h.coAuthorResult(ctx, err, "接受邀请失败", req.Id, uc.Uid)
+}
+
+// RevokeCoAuthor 作者撤销合作者，uid 是自己的时候就是退出合作
+func (h *ArticleHandler) RevokeCoAuthor(ctx *gin.Context) {
+	type Req struct {
+		Id  int64 `json:"id"`
+		Uid int64 `json:"uid"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.RevokeCoAuthor(ctx, req.Id, uc.Uid, req.Uid)
+	h.coAuthorResult(ctx, err, "撤销合作者失败", req.Id, uc.Uid)
+}
+
+func (h *ArticleHandler) coAuthorResult(ctx *gin.Context, err error, msg string, id int64, uid int64) {
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "Ok",
+		})
+	case errors.Is(err, service.ErrPermissionDenied):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+	case errors.Is(err, service.ErrCoAuthorNotFound):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有这个邀请",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg,
+			logger.Int64("id", id),
+			logger.Int64("uid", uid),
+			logger.Error(err))
+	}
+}
+
+// CoAuthors 帖子的合作者，包括还没接受和已经撤销的邀请
+func (h *ArticleHandler) CoAuthors(ctx *gin.Context) {
+	idstr := ctx.Param("id")
+	id, err := strconv.ParseInt(idstr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	cas, err := h.svc.ListCoAuthors(ctx, id, uc.Uid)
+	if errors.Is(err, service.ErrPermissionDenied) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询合作者失败",
+			logger.Int64("id", id),
+			logger.Int64("uid", uc.Uid),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[domain.CoAuthor, CoAuthorVo](cas, func(idx int, src domain.CoAuthor) CoAuthorVo {
+			return CoAuthorVo{
+				Uid:     src.Uid,
+				Name:    src.Name,
+				Role:    src.Role.ToUint8(),
+				Status:  src.Status.ToUint8(),
+				Inviter: src.Inviter,
+				Ctime:   src.Ctime.Format(time.DateTime),
+				Utime:   src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// Delete 放进回收站，发表过的帖子读者就看不到了
+func (h *ArticleHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.Delete(ctx, req.Id, uc.Uid)
+	h.trashResult(ctx, err, "删除帖子失败", req.Id, uc.Uid)
+}
+
+// Restore 从回收站恢复，发表过的帖子恢复之后要重新发表
+func (h *ArticleHandler) Restore(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.Restore(ctx, req.Id, uc.Uid)
+	h.trashResult(ctx, err, "恢复帖子失败", req.Id, uc.Uid)
+}
+
+func (h *ArticleHandler) trashResult(ctx *gin.Context, err error, msg string, id int64, uid int64) {
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "Ok",
+		})
+	case errors.Is(err, service.ErrPermissionDenied):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+	case errors.Is(err, service.ErrArticleNotInTrash):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子不在回收站里面",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg,
+			logger.Int64("id", id),
+			logger.Int64("uid", uid),
+			logger.Error(err))
+	}
+}
+
+// Trash 回收站里面的帖子，最近删除的在前面
+func (h *ArticleHandler) Trash(ctx *gin.Context) {
+	var page Page
+	if err := ctx.Bind(&page); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	arts, err := h.svc.ListTrash(ctx, uc.Uid, page.Offset, page.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查找回收站失败",
+			logger.Error(err),
+			logger.Int("offset", page.Offset),
+			logger.Int("limit", page.Limit),
+			logger.Int64("uid", uc.Uid))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+			return ArticleVo{
+				Id:        src.Id,
+				Title:     src.Title,
+				Abstract:  src.Abstract(),
+				AuthorId:  src.Author.Id,
+				Status:    src.Status.ToUint8(),
+				Category:  src.Category,
+				Tags:      src.Tags,
+				DeletedAt: src.DeletedAt.Format(time.DateTime),
+				Ctime:     src.Ctime.Format(time.DateTime),
+				Utime:     src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
 func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 	idstr := ctx.Param("id")
 	id, err := strconv.ParseInt(idstr, 10, 64)
@@ -275,6 +885,13 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 	}
 	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
 	art, err := h.svc.GetPubById(ctx, id, uc.Uid)
+	if errors.Is(err, service.ErrPubArticleNotFound) {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "帖子不存在",
+			Code: 4,
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Msg:  "系统错误",
@@ -315,16 +932,32 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 		}
 	}()*/
 	intr := resp.Intr
+	//专栏的上一篇和下一篇，拿不到不影响看帖子
+	nav, err := h.svc.SeriesNav(ctx, id)
+	if err != nil {
+		h.l.Error("获取专栏信息失败", logger.Int64("id", id), logger.Error(err))
+	}
 	ctx.JSON(http.StatusOK, Result{
 		Data: ArticleVo{
-			Id:    art.Id,
-			Title: art.Title,
+			Id:       art.Id,
+			Title:    art.Title,
+			Abstract: art.Abstract(),
 
-			Content:    art.Content,
-			AuthorId:   art.Author.Id,
-			AuthorName: art.Author.Name,
+			Content:     art.Content,
+			ContentUrl:  art.ContentURL,
+			HTML:        art.Rendered.HTML,
+			WordCnt:     art.Rendered.WordCnt,
+			ReadingTime: readingMinutes(art.Rendered.ReadingTime),
+			AuthorId:    art.Author.Id,
+			AuthorName:  art.Author.Name,
+			CoAuthorNames: slice.Map[domain.Author, string](art.CoAuthors,
+				func(idx int, src domain.Author) string {
+					return src.Name
+				}),
 
-			Status: art.Status.ToUint8(),
+			Status:   art.Status.ToUint8(),
+			Category: art.Category,
+			Tags:     art.Tags,
 
 			ReadCnt:    intr.ReadCnt,
 			LikeCnt:    intr.LikeCnt,
@@ -332,12 +965,101 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 			Liked:      intr.Liked,
 			Collected:  intr.Collected,
 
+			Series: h.toSeriesNavVo(nav),
+
 			Ctime: art.Ctime.Format(time.DateTime),
 			Utime: art.Utime.Format(time.DateTime),
 		},
 	})
 }
 
+// PubListByTag 某个标签下已发表的帖子，只返回摘要
+func (h *ArticleHandler) PubListByTag(ctx *gin.Context) {
+	tag := ctx.Param("tag")
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	arts, err := h.svc.ListPubByTag(ctx, tag, offset, limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("按照标签查询帖子失败",
+			logger.String("tag", tag),
+			logger.Int("offset", offset),
+			logger.Int("limit", limit),
+			logger.Error(err))
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	// 计数和有没有点赞、收藏一次查出来，拿不到不影响列表
+	intrs := map[int64]*intrv1.Interactive{}
+	if len(arts) > 0 {
+		resp, er := h.intrSvc.GetByIdsWithUser(ctx, &intrv1.GetByIdsWithUserRequest{
+			Biz: h.biz,
+			Ids: slice.Map[domain.Article, int64](arts, func(idx int, src domain.Article) int64 {
+				return src.Id
+			}),
+			Uid: uc.Uid,
+		})
+		if er != nil {
+			h.l.Error("批量获取帖子计数失败", logger.String("tag", tag), logger.Error(er))
+		} else {
+			intrs = resp.GetIntrs()
+		}
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+			intr := intrs[src.Id]
+			return ArticleVo{
+				Id:          src.Id,
+				Title:       src.Title,
+				Abstract:    src.Abstract(),
+				WordCnt:     src.Rendered.WordCnt,
+				ReadingTime: readingMinutes(src.Rendered.ReadingTime),
+				AuthorId:    src.Author.Id,
+				Category:    src.Category,
+				Tags:        src.Tags,
+				ReadCnt:     intr.GetReadCnt(),
+				LikeCnt:     intr.GetLikeCnt(),
+				CollectCnt:  intr.GetCollectCnt(),
+				Liked:       intr.GetLiked(),
+				Collected:   intr.GetCollected(),
+				Ctime:       src.Ctime.Format(time.DateTime),
+				Utime:       src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// TagCounts 标签和标签下的帖子数量 /tags?limit=?
+func (h *ArticleHandler) TagCounts(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	cnts, err := h.svc.CountTags(ctx, limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("统计标签失败", logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[domain.TagCount, TagCountVo](cnts, func(idx int, src domain.TagCount) TagCountVo {
+			return TagCountVo{
+				Tag:   src.Tag,
+				Count: src.Count,
+			}
+		}),
+	})
+}
+
 // Like 点赞或取消点赞
 func (h *ArticleHandler) Like(c *gin.Context) {
 	type Req struct {
@@ -350,8 +1072,16 @@ func (h *ArticleHandler) Like(c *gin.Context) {
 		return
 	}
 	uc := c.MustGet("claims").(*jwt2.UserClaims)
+	if !h.abuse.AllowLike(c, uc.Uid, h.biz, req.Id) {
+		c.JSON(http.StatusOK, Result{Code: 4, Msg: "操作太频繁，请稍后再试"})
+		return
+	}
 	var err error
 	if req.Like {
+		// 只能给已发表的帖子点赞，取消点赞不用检查
+		if !h.checkPub(c, req.Id) {
+			return
+		}
 		// 点赞
 		_, err = h.intrSvc.Like(c, &intrv1.LikeRequest{
 			Biz:   h.biz,
@@ -391,13 +1121,21 @@ func (h *ArticleHandler) Collect(ctx *gin.Context) {
 		return
 	}
 	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
-
+	if !h.checkPub(ctx, req.Id) {
+		return
+	}
 	_, err := h.intrSvc.Collect(ctx, &intrv1.CollectRequest{
 		Biz:   h.biz,
 		BizId: req.Id,
 		Cid:   req.Cid,
 		Uid:   uc.Uid,
 	})
+	if status.Code(err) == codes.NotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4, Msg: "收藏夹不存在",
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5, Msg: "系统错误",
@@ -412,3 +1150,48 @@ func (h *ArticleHandler) Collect(ctx *gin.Context) {
 		Msg: "OK",
 	})
 }
+
+// checkPub 交互服务只校验 id，帖子是不是已发表要在这里确认，返回 false 的时候已经写好了响应
+func (h *ArticleHandler) checkPub(ctx *gin.Context, id int64) bool {
+	arts, err := h.svc.ListPubByIds(ctx, []int64{id})
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("查询已发表的帖子失败", logger.Int64("aid", id), logger.Error(err))
+		return false
+	}
+	if len(arts) == 0 {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "帖子不存在"})
+		return false
+	}
+	return true
+}
+
+// CancelCollect 取消收藏，不需要知道在哪个收藏夹里面
+func (h *ArticleHandler) CancelCollect(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	_, err := h.intrSvc.CancelCollect(ctx, &intrv1.CancelCollectRequest{
+		Biz:   h.biz,
+		BizId: req.Id,
+		Uid:   uc.Uid,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5, Msg: "系统错误",
+		})
+		h.l.Error("取消收藏失败",
+			logger.Error(err),
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("aid", req.Id))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
@@ -0,0 +1,227 @@
+package web
+
+import (
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	jwt2 "geektime/webook/internal/web/jwt"
+	"geektime/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// EditSeries 新建或者修改专栏，id 为 0 的时候新建
+func (h *ArticleHandler) EditSeries(ctx *gin.Context) {
+	type Req struct {
+		Id          int64  `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	id, err := h.svc.SaveSeries(ctx, domain.ArticleSeries{
+		Id:          req.Id,
+		Title:       req.Title,
+		Description: req.Description,
+		Author:      domain.Author{Id: uc.Uid},
+	})
+	if err != nil {
+		h.seriesResult(ctx, err, "保存专栏失败", req.Id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: id,
+	})
+}
+
+func (h *ArticleHandler) DeleteSeries(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.DeleteSeries(ctx, req.Id, uc.Uid)
+	h.seriesResult(ctx, err, "删除专栏失败", req.Id, uc.Uid)
+}
+
+// ListSeries 作者自己的专栏，不带帖子
+func (h *ArticleHandler) ListSeries(ctx *gin.Context) {
+	var page Page
+	if err := ctx.Bind(&page); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	ss, err := h.svc.ListSeries(ctx, uc.Uid, page.Offset, page.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查找专栏失败",
+			logger.Error(err),
+			logger.Int("offset", page.Offset),
+			logger.Int("limit", page.Limit),
+			logger.Int64("uid", uc.Uid))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[domain.ArticleSeries, SeriesVo](ss, func(idx int, src domain.ArticleSeries) SeriesVo {
+			return h.toSeriesVo(src)
+		}),
+	})
+}
+
+// SeriesDetail 作者查看专栏，包括还没发表的帖子
+func (h *ArticleHandler) SeriesDetail(ctx *gin.Context) {
+	idstr := ctx.Param("id")
+	id, err := strconv.ParseInt(idstr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	s, err := h.svc.GetSeries(ctx, id, uc.Uid)
+	if err != nil {
+		h.seriesResult(ctx, err, "查询专栏失败", id, uc.Uid)
+		return
+	}
+	vo := h.toSeriesVo(s)
+	vo.Articles = slice.Map[domain.Article, ArticleVo](s.Articles, func(idx int, src domain.Article) ArticleVo {
+		return ArticleVo{
+			Id:     src.Id,
+			Title:  src.Title,
+			Status: src.Status.ToUint8(),
+			Utime:  src.Utime.Format(time.DateTime),
+		}
+	})
+	ctx.JSON(http.StatusOK, Result{
+		Data: vo,
+	})
+}
+
+// AddSeriesArticle 把自己的帖子加到专栏最后面
+func (h *ArticleHandler) AddSeriesArticle(ctx *gin.Context) {
+	type Req struct {
+		Id        int64 `json:"id"`
+		ArticleId int64 `json:"articleId"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.AddSeriesArticle(ctx, req.Id, req.ArticleId, uc.Uid)
+	h.seriesResult(ctx, err, "专栏添加帖子失败", req.Id, uc.Uid)
+}
+
+func (h *ArticleHandler) RemoveSeriesArticle(ctx *gin.Context) {
+	type Req struct {
+		Id        int64 `json:"id"`
+		ArticleId int64 `json:"articleId"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.RemoveSeriesArticle(ctx, req.Id, req.ArticleId, uc.Uid)
+	h.seriesResult(ctx, err, "专栏移除帖子失败", req.Id, uc.Uid)
+}
+
+// ReorderSeries articleIds 是专栏里面所有帖子的新顺序
+func (h *ArticleHandler) ReorderSeries(ctx *gin.Context) {
+	type Req struct {
+		Id         int64   `json:"id"`
+		ArticleIds []int64 `json:"articleIds"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	err := h.svc.ReorderSeries(ctx, req.Id, uc.Uid, req.ArticleIds)
+	h.seriesResult(ctx, err, "专栏排序失败", req.Id, uc.Uid)
+}
+
+func (h *ArticleHandler) seriesResult(ctx *gin.Context, err error, msg string, id int64, uid int64) {
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "Ok",
+		})
+	case errors.Is(err, service.ErrPermissionDenied):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+	case errors.Is(err, service.ErrSeriesNotFound):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "专栏不存在",
+		})
+	case errors.Is(err, service.ErrIllegalSeries):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "专栏标题不能为空，最多 64 个字",
+		})
+	case errors.Is(err, service.ErrArticleInOtherSeries):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子已经在专栏里面了",
+		})
+	case errors.Is(err, service.ErrIllegalSeriesOrder):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "专栏里面的帖子已经变了，请刷新之后重新排序",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg,
+			logger.Error(err),
+			logger.Int64("id", id),
+			logger.Int64("uid", uid))
+	}
+}
+
+func (h *ArticleHandler) toSeriesVo(s domain.ArticleSeries) SeriesVo {
+	return SeriesVo{
+		Id:          s.Id,
+		Title:       s.Title,
+		Description: s.Description,
+		Ctime:       s.Ctime.Format(time.DateTime),
+		Utime:       s.Utime.Format(time.DateTime),
+	}
+}
+
+// toSeriesNavVo 帖子不在专栏里面的时候返回 nil
+func (h *ArticleHandler) toSeriesNavVo(nav domain.SeriesNav) *SeriesNavVo {
+	if nav.Series.Id == 0 {
+		return nil
+	}
+	res := &SeriesNavVo{
+		Id:    nav.Series.Id,
+		Title: nav.Series.Title,
+	}
+	if nav.Prev.Id > 0 {
+		res.Prev = &ArticleVo{Id: nav.Prev.Id, Title: nav.Prev.Title}
+	}
+	if nav.Next.Id > 0 {
+		res.Next = &ArticleVo{Id: nav.Next.Id, Title: nav.Next.Title}
+	}
+	return res
+}
@@ -1,15 +1,36 @@
 package web
 
+import "time"
+
 // VO view object，对标前端的
 
 type ArticleVo struct {
-	Id         int64  `json:"id,omitempty"`
-	Title      string `json:"title,omitempty"`
-	Abstract   string `json:"abstract,omitempty"`
-	Content    string `json:"content,omitempty"`
-	AuthorId   int64  `json:"authorId,omitempty"`
-	AuthorName string `json:"authorName,omitempty"`
-	Status     uint8  `json:"status,omitempty"`
+	Id       int64  `json:"id,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Abstract string `json:"abstract,omitempty"`
+	// Content Markdown 原文
+	Content string `json:"content,omitempty"`
+	// ContentUrl 内容放在对象存储的时候，前端通过这个链接读取 Markdown 原文
+	ContentUrl string `json:"contentUrl,omitempty"`
+	// HTML 发表时渲染并过滤过的内容，只有线上库的帖子有
+	HTML string `json:"html,omitempty"`
+	// 字数和预计阅读分钟数
+	WordCnt     int64  `json:"wordCnt,omitempty"`
+	ReadingTime int64  `json:"readingTime,omitempty"`
+	AuthorId    int64  `json:"authorId,omitempty"`
+	AuthorName  string `json:"authorName,omitempty"`
+	// CoAuthorNames 接受了邀请的合作者
+	CoAuthorNames []string `json:"coAuthorNames,omitempty"`
+	Status        uint8    `json:"status,omitempty"`
+	// 分类和标签
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	// 定时发表的时间
+	PublishAt string `json:"publishAt,omitempty"`
+	// Version 制作库的版本号，修改的时候原样带回来
+	Version int64 `json:"version,omitempty"`
+	// DeletedAt 放进回收站的时间，只有回收站的列表有
+	DeletedAt string `json:"deletedAt,omitempty"`
 	//计数
 	ReadCnt    int64 `json:"readCnt,omitempty"`
 	LikeCnt    int64 `json:"likeCnt,omitempty"`
@@ -17,7 +38,142 @@ type ArticleVo struct {
 	//我个人有没有收藏，有没有点赞
 	Liked     bool `json:"liked"`
 	Collected bool `json:"collected"`
+	// Series 帖子所在专栏的上一篇和下一篇，只有读者看帖子的时候有
+	Series *SeriesNavVo `json:"series,omitempty"`
 
 	Ctime string `json:"ctime,omitempty"`
 	Utime string `json:"utime,omitempty"`
 }
+
+// ArticleListVo 游标翻页的列表，NextCursor 为空说明没有下一页了
+type ArticleListVo struct {
+	Articles   []ArticleVo `json:"articles"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+// ArticleRevisionVo 帖子的历史版本
+type ArticleRevisionVo struct {
+	Id        int64  `json:"id,omitempty"`
+	ArticleId int64  `json:"articleId,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Abstract  string `json:"abstract,omitempty"`
+	Status    uint8  `json:"status,omitempty"`
+	Ctime     string `json:"ctime,omitempty"`
+}
+
+// CoAuthorVo 帖子的合作者，role 2 是编辑，3 是只读；status 1 待接受，2 已接受，3 已撤销
+type CoAuthorVo struct {
+	Uid     int64  `json:"uid"`
+	Name    string `json:"name"`
+	Role    uint8  `json:"role"`
+	Status  uint8  `json:"status"`
+	Inviter int64  `json:"inviter"`
+	Ctime   string `json:"ctime"`
+	Utime   string `json:"utime"`
+}
+
+// TagCountVo 标签下已发表的帖子数量
+type TagCountVo struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// DiffLineVo 逐行比较的一行
+type DiffLineVo struct {
+	// equal, delete, insert
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+func readingMinutes(d time.Duration) int64 {
+	return int64(d / time.Minute)
+}
+
+func formatPublishAt(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateTime)
+}
+
+// AuthorProfileVo 作者的公开主页，Articles 是已发表的帖子
+type AuthorProfileVo struct {
+	Id         int64       `json:"id,omitempty"`
+	Nickname   string      `json:"nickname,omitempty"`
+	AboutMe    string      `json:"aboutMe,omitempty"`
+	ArticleCnt int64       `json:"articleCnt"`
+	Followers  int64       `json:"followers"`
+	Followees  int64       `json:"followees"`
+	Articles   []ArticleVo `json:"articles"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+// SeriesVo 专栏，Articles 按照专栏里面的顺序排列
+type SeriesVo struct {
+	Id          int64       `json:"id,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Articles    []ArticleVo `json:"articles,omitempty"`
+	Ctime       string      `json:"ctime,omitempty"`
+	Utime       string      `json:"utime,omitempty"`
+}
+
+// SeriesNavVo 专栏里面的上一篇和下一篇，没有的时候是 nil
+type SeriesNavVo struct {
+	Id    int64      `json:"id,omitempty"`
+	Title string     `json:"title,omitempty"`
+	Prev  *ArticleVo `json:"prev,omitempty"`
+	Next  *ArticleVo `json:"next,omitempty"`
+}
+
+// HistoryVo 一条阅读历史，帖子撤回了之后 Title 为空
+type HistoryVo struct {
+	Biz      string `json:"biz"`
+	BizId    int64  `json:"bizId"`
+	Title    string `json:"title,omitempty"`
+	ReadTime string `json:"readTime"`
+}
+
+type HistoryListVo struct {
+	Records    []HistoryVo `json:"records"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+type CollectionVo struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Ctime string `json:"ctime"`
+	Utime string `json:"utime"`
+}
+
+// CollectionItemVo 收藏夹里面的一条收藏，帖子撤回了之后 Title 为空
+type CollectionItemVo struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	Title string `json:"title,omitempty"`
+	Ctime string `json:"ctime"`
+}
+
+type JobVo struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Executor   string `json:"executor"`
+	Expression string `json:"expression"`
+	Cfg        string `json:"cfg"`
+	// Status waiting, running 或者 paused
+	Status   string `json:"status"`
+	NextTime string `json:"nextTime"`
+	Ctime    string `json:"ctime"`
+	Utime    string `json:"utime"`
+}
+
+// JobExecutionVo 还没执行完的记录 EndTime 为空
+type JobExecutionVo struct {
+	Id   int64  `json:"id"`
+	Node string `json:"node"`
+	// Status running, succeeded 或者 failed
+	Status    string `json:"status"`
+	Err       string `json:"err,omitempty"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime,omitempty"`
+}
@@ -0,0 +1,104 @@
+package web
+
+import (
+	"errors"
+	followv1 "geektime/webook/api/proto/gen/follow/v1"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	"geektime/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AuthorHandler 作者的公开主页，不登录也可以看
+type AuthorHandler struct {
+	svc       service.ArticleService
+	followSvc followv1.FollowServiceClient
+	l         logger.LoggerV1
+}
+
+func NewAuthorHandler(svc service.ArticleService,
+	followSvc followv1.FollowServiceClient,
+	l logger.LoggerV1) *AuthorHandler {
+	return &AuthorHandler{
+		svc:       svc,
+		followSvc: followSvc,
+		l:         l,
+	}
+}
+
+func (h *AuthorHandler) RegisterRoutes(r *gin.Engine) {
+	// /users/:id/public?cursor=? 第一页不带 cursor
+	r.GET("/users/:id/public", h.Profile)
+}
+
+func (h *AuthorHandler) Profile(ctx *gin.Context) {
+	idstr := ctx.Param("id")
+	uid, err := strconv.ParseInt(idstr, 10, 64)
+	if err != nil || uid <= 0 {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "id 参数错误"})
+		return
+	}
+	cur, err := domain.ParseArticleCursor(ctx.Query("cursor"))
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "翻页参数错误"})
+		return
+	}
+	profile, err := h.svc.AuthorProfile(ctx, uid)
+	switch {
+	case errors.Is(err, service.ErrAuthorNotFound):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户不存在"})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("获取作者主页失败", logger.Int64("uid", uid), logger.Error(err))
+		return
+	}
+	arts := profile.Articles
+	if !cur.IsZero() {
+		arts, err = h.svc.ListPubByAuthor(ctx, uid, cur, domain.AuthorProfilePageSize)
+		if err != nil {
+			ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+			h.l.Error("获取作者的帖子失败", logger.Int64("uid", uid), logger.Error(err))
+			return
+		}
+	}
+	vo := AuthorProfileVo{
+		Id:         profile.Author.Id,
+		Nickname:   profile.Author.Name,
+		AboutMe:    profile.AboutMe,
+		ArticleCnt: profile.ArticleCnt,
+		Articles:   h.toVo(arts),
+		NextCursor: domain.NextArticleCursor(arts, domain.AuthorProfilePageSize).Encode(),
+	}
+	// 关注数据不和主页一起缓存：关注、取关发生在 follow 服务，webook 这边没法及时删主页的缓存，
+	// 一起缓存的话粉丝数会一直是旧的。follow 服务自己在 Redis 里面缓存了这两个数，这里直接查就可以
+	// 关注数据拿不到不影响主页的其它内容
+	static, err := h.followSvc.GetFollowStatic(ctx, &followv1.GetFollowStaticRequest{Uid: uid})
+	if err != nil {
+		h.l.Error("获取关注数据失败", logger.Int64("uid", uid), logger.Error(err))
+	} else {
+		vo.Followers, vo.Followees = static.Followers, static.Followees
+	}
+	ctx.JSON(http.StatusOK, Result{Data: vo})
+}
+
+func (h *AuthorHandler) toVo(arts []domain.Article) []ArticleVo {
+	return slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+		return ArticleVo{
+			Id:          src.Id,
+			Title:       src.Title,
+			Abstract:    src.Abstract(),
+			WordCnt:     src.Rendered.WordCnt,
+			ReadingTime: readingMinutes(src.Rendered.ReadingTime),
+			AuthorId:    src.Author.Id,
+			Category:    src.Category,
+			Tags:        src.Tags,
+			Ctime:       src.Ctime.Format(time.DateTime),
+			Utime:       src.Utime.Format(time.DateTime),
+		}
+	})
+}
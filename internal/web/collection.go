@@ -0,0 +1,194 @@
+package web
+
+import (
+	intrv1 "geektime/webook/api/proto/gen/intr/v1"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	jwt2 "geektime/webook/internal/web/jwt"
+	"geektime/webook/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"time"
+)
+
+// CollectionHandler 收藏夹，收藏和取消收藏在 ArticleHandler 里面
+type CollectionHandler struct {
+	intrSvc intrv1.InteractiveServiceClient
+	artSvc  service.ArticleService
+	l       logger.LoggerV1
+}
+
+func NewCollectionHandler(intrSvc intrv1.InteractiveServiceClient,
+	artSvc service.ArticleService,
+	l logger.LoggerV1) *CollectionHandler {
+	return &CollectionHandler{
+		intrSvc: intrSvc,
+		artSvc:  artSvc,
+		l:       l,
+	}
+}
+
+func (h *CollectionHandler) RegisterRoutes(r *gin.Engine) {
+	g := r.Group("/collections")
+	g.GET("", h.List)
+	g.POST("/create", h.Create)
+	g.POST("/rename", h.Rename)
+	g.POST("/delete", h.Delete)
+	// cid 为 0 是默认收藏夹
+	g.POST("/items", h.Items)
+}
+
+func (h *CollectionHandler) List(ctx *gin.Context) {
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	resp, err := h.intrSvc.ListCollections(ctx, &intrv1.ListCollectionsRequest{Uid: uc.Uid})
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("查询收藏夹失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	res := make([]CollectionVo, 0, len(resp.GetCollections()))
+	for _, c := range resp.GetCollections() {
+		res = append(res, CollectionVo{
+			Id:    c.Id,
+			Name:  c.Name,
+			Ctime: time.UnixMilli(c.Ctime).Format(time.DateTime),
+			Utime: time.UnixMilli(c.Utime).Format(time.DateTime),
+		})
+	}
+	ctx.JSON(http.StatusOK, Result{Data: res})
+}
+
+func (h *CollectionHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		Name string `json:"name"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	resp, err := h.intrSvc.CreateCollection(ctx, &intrv1.CreateCollectionRequest{
+		Uid:  uc.Uid,
+		Name: req.Name,
+	})
+	if err != nil {
+		h.errResult(ctx, err, "创建收藏夹失败", 0, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Data: resp.Id})
+}
+
+func (h *CollectionHandler) Rename(ctx *gin.Context) {
+	type Req struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	_, err := h.intrSvc.RenameCollection(ctx, &intrv1.RenameCollectionRequest{
+		Id:   req.Id,
+		Uid:  uc.Uid,
+		Name: req.Name,
+	})
+	if err != nil {
+		h.errResult(ctx, err, "修改收藏夹失败", req.Id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+// Delete 收藏夹里面的收藏也会一起删掉
+func (h *CollectionHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	_, err := h.intrSvc.DeleteCollection(ctx, &intrv1.DeleteCollectionRequest{
+		Id:  req.Id,
+		Uid: uc.Uid,
+	})
+	if err != nil {
+		h.errResult(ctx, err, "删除收藏夹失败", req.Id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+func (h *CollectionHandler) Items(ctx *gin.Context) {
+	type Req struct {
+		Cid    int64 `json:"cid"`
+		Offset int   `json:"offset"`
+		Limit  int   `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	resp, err := h.intrSvc.ListCollection(ctx, &intrv1.ListCollectionRequest{
+		Uid:    uc.Uid,
+		Cid:    req.Cid,
+		Offset: int32(req.Offset),
+		Limit:  int32(req.Limit),
+	})
+	if err != nil {
+		h.errResult(ctx, err, "查询收藏夹的收藏失败", req.Cid, uc.Uid)
+		return
+	}
+	items := resp.GetItems()
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		if item.Biz == domain.BizArticle {
+			ids = append(ids, item.BizId)
+		}
+	}
+	titles := make(map[int64]string, len(ids))
+	// 标题查不到只影响展示
+	arts, err := h.artSvc.ListPubByIds(ctx, ids)
+	if err != nil {
+		h.l.Error("查询收藏的帖子失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+	}
+	for _, art := range arts {
+		titles[art.Id] = art.Title
+	}
+	res := make([]CollectionItemVo, 0, len(items))
+	for _, item := range items {
+		vo := CollectionItemVo{
+			Biz:   item.Biz,
+			BizId: item.BizId,
+			Ctime: time.UnixMilli(item.Ctime).Format(time.DateTime),
+		}
+		if item.Biz == domain.BizArticle {
+			vo.Title = titles[item.BizId]
+		}
+		res = append(res, vo)
+	}
+	ctx.JSON(http.StatusOK, Result{Data: res})
+}
+
+func (h *CollectionHandler) errResult(ctx *gin.Context, err error, msg string, cid int64, uid int64) {
+	switch status.Code(err) {
+	case codes.NotFound:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "收藏夹不存在"})
+	case codes.InvalidArgument:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "收藏夹名字不能为空，最多 32 个字"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error(msg,
+			logger.Error(err),
+			logger.Int64("cid", cid),
+			logger.Int64("uid", uid))
+	}
+}
@@ -0,0 +1,141 @@
+package web
+
+import (
+	intrv1 "geektime/webook/api/proto/gen/intr/v1"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	jwt2 "geektime/webook/internal/web/jwt"
+	"geektime/webook/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+const historyPageSize = 20
+
+// HistoryHandler 阅读历史，记录是 interactive 消费 article_read 写进去的
+type HistoryHandler struct {
+	intrSvc intrv1.InteractiveServiceClient
+	artSvc  service.ArticleService
+	l       logger.LoggerV1
+}
+
+func NewHistoryHandler(intrSvc intrv1.InteractiveServiceClient,
+	artSvc service.ArticleService,
+	l logger.LoggerV1) *HistoryHandler {
+	return &HistoryHandler{
+		intrSvc: intrSvc,
+		artSvc:  artSvc,
+		l:       l,
+	}
+}
+
+func (h *HistoryHandler) RegisterRoutes(r *gin.Engine) {
+	g := r.Group("/history")
+	// /history?cursor=? 第一页不带 cursor
+	g.GET("", h.List)
+	g.POST("/delete", h.Delete)
+	g.POST("/clear", h.Clear)
+}
+
+func (h *HistoryHandler) List(ctx *gin.Context) {
+	// 游标的格式和帖子列表一样，时间是最后一次阅读的时间
+	cur, err := domain.ParseArticleCursor(ctx.Query("cursor"))
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "翻页参数错误"})
+		return
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	req := &intrv1.ListHistoryRequest{
+		Uid:   uc.Uid,
+		Limit: historyPageSize,
+	}
+	if !cur.IsZero() {
+		req.CursorReadTime = cur.Utime.UnixMilli()
+		req.CursorId = cur.Id
+	}
+	resp, err := h.intrSvc.ListHistory(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("查询阅读历史失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	records := resp.GetRecords()
+	ids := make([]int64, 0, len(records))
+	for _, r := range records {
+		if r.Biz == domain.BizArticle {
+			ids = append(ids, r.BizId)
+		}
+	}
+	titles := make(map[int64]string, len(ids))
+	// 标题查不到只影响展示
+	arts, err := h.artSvc.ListPubByIds(ctx, ids)
+	if err != nil {
+		h.l.Error("查询阅读历史的帖子失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+	}
+	for _, art := range arts {
+		titles[art.Id] = art.Title
+	}
+	vo := HistoryListVo{
+		Records: make([]HistoryVo, 0, len(records)),
+	}
+	for _, r := range records {
+		v := HistoryVo{
+			Biz:      r.Biz,
+			BizId:    r.BizId,
+			ReadTime: time.UnixMilli(r.ReadTime).Format(time.DateTime),
+		}
+		if r.Biz == domain.BizArticle {
+			v.Title = titles[r.BizId]
+		}
+		vo.Records = append(vo.Records, v)
+	}
+	if len(records) == historyPageSize {
+		last := records[len(records)-1]
+		vo.NextCursor = domain.ArticleCursor{
+			Utime: time.UnixMilli(last.ReadTime),
+			Id:    last.Id,
+		}.Encode()
+	}
+	ctx.JSON(http.StatusOK, Result{Data: vo})
+}
+
+func (h *HistoryHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Biz   string `json:"biz"`
+		BizId int64  `json:"bizId"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Biz == "" {
+		req.Biz = domain.BizArticle
+	}
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	_, err := h.intrSvc.DeleteHistory(ctx, &intrv1.DeleteHistoryRequest{
+		Uid:   uc.Uid,
+		Biz:   req.Biz,
+		BizId: req.BizId,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("删除阅读历史失败",
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("bizId", req.BizId),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+func (h *HistoryHandler) Clear(ctx *gin.Context) {
+	uc := ctx.MustGet("claims").(*jwt2.UserClaims)
+	_, err := h.intrSvc.ClearHistory(ctx, &intrv1.ClearHistoryRequest{Uid: uc.Uid})
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("清空阅读历史失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
@@ -0,0 +1,271 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	"geektime/webook/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// JobExecutors 调度器上注册过的执行器，没有执行器的 job 抢占到了也执行不了
+type JobExecutors interface {
+	HasExecutor(name string) bool
+}
+
+// JobHandler 分布式任务的管理接口，挂在管理后台的 server 上，不对用户开放
+type JobHandler struct {
+	svc       service.CronJobService
+	executors JobExecutors
+	l         logger.LoggerV1
+}
+
+func NewJobHandler(svc service.CronJobService, executors JobExecutors, l logger.LoggerV1) *JobHandler {
+	return &JobHandler{
+		svc:       svc,
+		executors: executors,
+		l:         l,
+	}
+}
+
+func (h *JobHandler) RegisterRoutes(r *gin.Engine) {
+	g := r.Group("/jobs")
+	// /jobs?offset=?&limit=?
+	g.GET("", h.List)
+	g.GET("/:id", h.Detail)
+	g.POST("/create", h.Create)
+	g.POST("/update", h.Update)
+	g.POST("/delete", h.Delete)
+	g.POST("/pause", h.Pause)
+	g.POST("/resume", h.Resume)
+	// 马上调度一次
+	g.POST("/trigger", h.Trigger)
+	// /jobs/:id/executions?offset=?&limit=?
+	g.GET("/:id/executions", h.Executions)
+}
+
+type JobReq struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Executor   string `json:"executor"`
+	Expression string `json:"expression"`
+	Cfg        string `json:"cfg"`
+}
+
+func (req JobReq) toDomain() domain.Job {
+	return domain.Job{
+		Id:         req.Id,
+		Name:       req.Name,
+		Executor:   req.Executor,
+		Expression: req.Expression,
+		Cfg:        req.Cfg,
+	}
+}
+
+func (h *JobHandler) List(ctx *gin.Context) {
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	jobs, err := h.svc.ListJobs(ctx, offset, limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("查询 job 列表失败",
+			logger.Int("offset", offset),
+			logger.Int("limit", limit),
+			logger.Error(err))
+		return
+	}
+	res := make([]JobVo, 0, len(jobs))
+	for _, j := range jobs {
+		res = append(res, h.toVo(j))
+	}
+	ctx.JSON(http.StatusOK, Result{Data: res})
+}
+
+func (h *JobHandler) Detail(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "id 参数错误"})
+		return
+	}
+	j, err := h.svc.GetJob(ctx, id)
+	if err != nil {
+		h.errResult(ctx, err, "查询 job 失败", id)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Data: h.toVo(j)})
+}
+
+func (h *JobHandler) Create(ctx *gin.Context) {
+	var req JobReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if !h.checkReq(ctx, req) {
+		return
+	}
+	err := h.svc.AddJob(ctx, req.toDomain())
+	if err != nil {
+		h.errResult(ctx, err, "创建 job 失败", 0)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+func (h *JobHandler) Update(ctx *gin.Context) {
+	var req JobReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if !h.checkReq(ctx, req) {
+		return
+	}
+	err := h.svc.UpdateJob(ctx, req.toDomain())
+	if err != nil {
+		h.errResult(ctx, err, "修改 job 失败", req.Id)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+// checkReq 返回 false 的时候已经写好了响应
+func (h *JobHandler) checkReq(ctx *gin.Context, req JobReq) bool {
+	if req.Name == "" || req.Executor == "" {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "名字和执行器不能为空"})
+		return false
+	}
+	if !h.executors.HasExecutor(req.Executor) {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "执行器不存在"})
+		return false
+	}
+	return true
+}
+
+// Delete 执行记录也会一起删掉
+func (h *JobHandler) Delete(ctx *gin.Context) {
+	h.byId(ctx, h.svc.DeleteJob, "删除 job 失败")
+}
+
+func (h *JobHandler) Pause(ctx *gin.Context) {
+	h.byId(ctx, h.svc.Pause, "暂停 job 失败")
+}
+
+func (h *JobHandler) Resume(ctx *gin.Context) {
+	h.byId(ctx, h.svc.Resume, "恢复 job 失败")
+}
+
+func (h *JobHandler) Trigger(ctx *gin.Context) {
+	h.byId(ctx, h.svc.Trigger, "触发 job 失败")
+}
+
+func (h *JobHandler) Executions(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "id 参数错误"})
+		return
+	}
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	es, err := h.svc.ListExecutions(ctx, id, offset, limit)
+	if err != nil {
+		h.errResult(ctx, err, "查询 job 执行记录失败", id)
+		return
+	}
+	res := make([]JobExecutionVo, 0, len(es))
+	for _, e := range es {
+		vo := JobExecutionVo{
+			Id:        e.Id,
+			Node:      e.Node,
+			Status:    jobExecutionStatusName(e.Status),
+			Err:       e.Err,
+			StartTime: e.StartTime.Format(time.DateTime),
+		}
+		if !e.EndTime.IsZero() {
+			vo.EndTime = e.EndTime.Format(time.DateTime)
+		}
+		res = append(res, vo)
+	}
+	ctx.JSON(http.StatusOK, Result{Data: res})
+}
+
+// byId 只需要 job ID 的操作
+func (h *JobHandler) byId(ctx *gin.Context, fn func(ctx context.Context, id int64) error, msg string) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	err := fn(ctx, req.Id)
+	if err != nil {
+		h.errResult(ctx, err, msg, req.Id)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+func (h *JobHandler) errResult(ctx *gin.Context, err error, msg string, id int64) {
+	switch {
+	case errors.Is(err, service.ErrInvalidJobExpression):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "cron 表达式不合法"})
+	case errors.Is(err, service.ErrJobDuplicate):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "job 名字冲突"})
+	case errors.Is(err, service.ErrJobNotFound):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "job 不存在"})
+	case errors.Is(err, service.ErrJobStatusConflict):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "job 当前的状态不允许这个操作"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error(msg, logger.Int64("jid", id), logger.Error(err))
+	}
+}
+
+func (h *JobHandler) toVo(j domain.Job) JobVo {
+	return JobVo{
+		Id:         j.Id,
+		Name:       j.Name,
+		Executor:   j.Executor,
+		Expression: j.Expression,
+		Cfg:        j.Cfg,
+		Status:     jobStatusName(j.Status),
+		NextTime:   j.NextExecTime.Format(time.DateTime),
+		Ctime:      j.Ctime.Format(time.DateTime),
+		Utime:      j.Utime.Format(time.DateTime),
+	}
+}
+
+func jobStatusName(s domain.JobStatus) string {
+	switch s {
+	case domain.JobStatusWaiting:
+		return "waiting"
+	case domain.JobStatusRunning:
+		return "running"
+	case domain.JobStatusPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+func jobExecutionStatusName(s domain.JobExecutionStatus) string {
+	switch s {
+	case domain.JobExecutionRunning:
+		return "running"
+	case domain.JobExecutionSucceeded:
+		return "succeeded"
+	case domain.JobExecutionFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// AdminTokenMiddlewareBuilder 管理后台的校验，请求头里面带 Authorization: Bearer <token>
+type AdminTokenMiddlewareBuilder struct {
+	token []byte
+}
+
+func NewAdminTokenMiddlewareBuilder(token string) *AdminTokenMiddlewareBuilder {
+	return &AdminTokenMiddlewareBuilder{
+		token: []byte(token),
+	}
+}
+
+// Build 没有配置 token 的时候拒绝所有请求，避免忘了配置就把管理接口放出去
+func (a *AdminTokenMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if len(a.token) == 0 {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		header := ctx.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		// 用常量时间比较，避免通过响应时间猜出 token
+		if token == header || subtle.ConstantTimeCompare([]byte(token), a.token) != 1 {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+	}
+}
@@ -32,8 +32,9 @@ func (l *LoginJWTMiddlewareBuilder) Build() gin.HandlerFunc {
 	gob.Register(time.Now())
 	return func(ctx *gin.Context) {
 		//登录和注册不需要校验
+		//带参数的路由用注册时候的路径匹配，比如 /users/:id/public
 		for _, path := range l.paths {
-			if ctx.Request.URL.Path == path {
+			if ctx.Request.URL.Path == path || ctx.FullPath() == path {
 				return
 			}
 		}
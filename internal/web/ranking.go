@@ -0,0 +1,63 @@
+package web
+
+import (
+	"errors"
+	"geektime/webook/internal/domain"
+	"geektime/webook/internal/service"
+	"geektime/webook/pkg/logger"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RankingHandler struct {
+	svc service.RankingService
+	l   logger.LoggerV1
+}
+
+func NewRankingHandler(svc service.RankingService, l logger.LoggerV1) *RankingHandler {
+	return &RankingHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *RankingHandler) RegisterRoutes(r *gin.Engine) {
+	// /ranking/daily?offset=?&limit=? 榜单的名字在配置里面
+	r.GET("/ranking/:board", h.Board)
+}
+
+func (h *RankingHandler) Board(ctx *gin.Context) {
+	board := ctx.Param("board")
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	arts, err := h.svc.GetBoard(ctx, board, max(offset, 0), limit)
+	switch {
+	case errors.Is(err, service.ErrUnknownBoard):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "榜单不存在"})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("查询榜单失败", logger.String("board", board), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+			return ArticleVo{
+				Id:       src.Id,
+				Title:    src.Title,
+				Abstract: src.Abstract(),
+				AuthorId: src.Author.Id,
+				Category: src.Category,
+				Tags:     src.Tags,
+				Ctime:    src.Ctime.Format(time.DateTime),
+				Utime:    src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
@@ -2,6 +2,7 @@ package web
 
 import (
 	rewardv1 "geektime/webook/api/proto/gen/reward/v1"
+	"geektime/webook/internal/domain"
 	"geektime/webook/internal/service"
 	"geektime/webook/internal/web/jwt"
 	"github.com/gin-gonic/gin"
@@ -51,7 +52,7 @@ func (h *RewardHandler) Reward(ctx *gin.Context) {
 	}
 	// 最关键的一步骤，就是拿到二维码
 	resp, err := h.rewardClient.PreReward(ctx, &rewardv1.PreRewardRequest{
-		Biz:       "article",
+		Biz:       domain.BizArticle,
 		BizId:     artResp.Id,
 		BizName:   artResp.Title,
 		TargetUid: artResp.Author.Id,
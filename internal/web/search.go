@@ -0,0 +1,105 @@
+package web
+
+import (
+	searchv1 "geektime/webook/api/proto/gen/search/v1"
+	"geektime/webook/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type SearchHandler struct {
+	searchSvc searchv1.SearchServiceClient
+	l         logger.LoggerV1
+}
+
+func NewSearchHandler(searchSvc searchv1.SearchServiceClient, l logger.LoggerV1) *SearchHandler {
+	return &SearchHandler{
+		searchSvc: searchSvc,
+		l:         l,
+	}
+}
+
+func (h *SearchHandler) RegisterRoutes(r *gin.Engine) {
+	g := r.Group("/search")
+	// /search/articles?q=?&author_id=?&start=?&end=?&offset=?&limit=?
+	// start 和 end 是更新时间的毫秒数
+	g.GET("/articles", h.SearchArticle)
+}
+
+func (h *SearchHandler) SearchArticle(ctx *gin.Context) {
+	var (
+		req = &searchv1.SearchArticleRequest{
+			Expression: ctx.Query("q"),
+		}
+		err error
+	)
+	req.AuthorId, err = h.queryInt64(ctx, "author_id")
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "作者参数错误"})
+		return
+	}
+	req.StartTime, err = h.queryInt64(ctx, "start")
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "开始时间参数错误"})
+		return
+	}
+	req.EndTime, err = h.queryInt64(ctx, "end")
+	if err != nil || (req.EndTime > 0 && req.EndTime < req.StartTime) {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "结束时间参数错误"})
+		return
+	}
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	req.Offset, req.Limit = int32(max(offset, 0)), int32(limit)
+
+	resp, err := h.searchSvc.SearchArticle(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("搜索帖子失败",
+			logger.String("q", req.Expression),
+			logger.Error(err))
+		return
+	}
+	arts := make([]SearchArticleVo, 0, len(resp.GetHits()))
+	for _, hit := range resp.GetHits() {
+		art := hit.GetArticle()
+		arts = append(arts, SearchArticleVo{
+			Id:               art.GetId(),
+			Title:            art.GetTitle(),
+			TitleHighlight:   hit.GetTitleHighlight(),
+			ContentHighlight: hit.GetContentHighlight(),
+			AuthorId:         art.GetAuthorId(),
+			Utime:            time.UnixMilli(art.GetUtime()).Format(time.DateTime),
+		})
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: map[string]any{
+			"total":    resp.GetTotal(),
+			"articles": arts,
+		},
+	})
+}
+
+// queryInt64 参数不传的时候返回 0
+func (h *SearchHandler) queryInt64(ctx *gin.Context, key string) (int64, error) {
+	val := ctx.Query(key)
+	if val == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
+
+type SearchArticleVo struct {
+	Id    int64  `json:"id"`
+	Title string `json:"title"`
+	// 命中的关键字用 <em></em> 包起来，其余的内容已经转义过了
+	TitleHighlight   string `json:"titleHighlight"`
+	ContentHighlight string `json:"contentHighlight"`
+	AuthorId         int64  `json:"authorId"`
+	Utime            string `json:"utime"`
+}
@@ -0,0 +1,40 @@
+package ioc
+
+import (
+	"geektime/webook/internal/repository"
+	"geektime/webook/internal/service"
+	"geektime/webook/pkg/logger"
+	"geektime/webook/pkg/ratelimit"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// InitAntiAbuseService 没有配置的时候用下面的默认值
+func InitAntiAbuseService(repo repository.AntiAbuseRepository,
+	cmd redis.Cmdable, l logger.LoggerV1) service.AntiAbuseService {
+	type Config struct {
+		// 同一个用户在这段时间内重复阅读同一篇帖子只算一次
+		ReadWindow time.Duration `yaml:"readWindow"`
+		// 每个用户每分钟最多算多少次阅读
+		ReadRate int `yaml:"readRate"`
+		// 每个用户每分钟最多点赞、取消点赞多少次
+		LikeRate int `yaml:"likeRate"`
+		// 同一个用户同一种可疑操作在这段时间内只记一条审计
+		AuditWindow time.Duration `yaml:"auditWindow"`
+	}
+	cfg := Config{
+		ReadWindow:  time.Minute * 30,
+		ReadRate:    60,
+		LikeRate:    30,
+		AuditWindow: time.Minute * 10,
+	}
+	err := viper.UnmarshalKey("antiAbuse", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return service.NewAntiAbuseService(repo,
+		ratelimit.NewRedisSlidingWindowLimiter(cmd, time.Minute, cfg.ReadRate),
+		ratelimit.NewRedisSlidingWindowLimiter(cmd, time.Minute, cfg.LikeRate),
+		cfg.ReadWindow, cfg.AuditWindow, l)
+}
@@ -0,0 +1,81 @@
+package ioc
+
+import (
+	"geektime/webook/internal/repository/dao"
+	"geektime/webook/pkg/ossx"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"os"
+	"time"
+)
+
+// InitArticleDAO 根据配置决定帖子存放在 MySQL、MongoDB，还是把线上库的内容放在对象存储
+func InitArticleDAO(db *gorm.DB) dao.ArticleDAO {
+	type Config struct {
+		// mysql、mongodb 或者 oss
+		Storage string `yaml:"storage"`
+		// 大于 0 的时候，读者拿到的是预签名链接而不是内容
+		Presign time.Duration `yaml:"presign"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("article", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	switch cfg.Storage {
+	case "", "mysql":
+		return dao.NewGROMArticleDAO(db)
+	case "mongodb":
+		return dao.NewMongoDBArticleDAO(InitMongoDB(), InitSnowflakeNode())
+	case "oss":
+		return dao.NewArticleS3DAO(db, InitObjectStore(), cfg.Presign)
+	default:
+		panic("未知的帖子存储 " + cfg.Storage)
+	}
+}
+
+// InitObjectStore 初始化对象存储，密钥从环境变量 OSS_ACCESS_KEY_ID 和 OSS_ACCESS_KEY_SECRET 读取
+func InitObjectStore() ossx.ObjectStore {
+	type Config struct {
+		// s3 或者 local
+		Type     string `yaml:"type"`
+		Bucket   string `yaml:"bucket"`
+		Region   string `yaml:"region"`
+		Endpoint string `yaml:"endpoint"`
+		// 下面两个是 local 用的
+		Dir     string `yaml:"dir"`
+		BaseURL string `yaml:"baseURL"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("oss", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	switch cfg.Type {
+	case "local":
+		store, err := ossx.NewLocalStore(cfg.Dir, cfg.BaseURL)
+		if err != nil {
+			panic(err)
+		}
+		return store
+	case "s3":
+		sess, err := session.NewSession(&aws.Config{
+			Credentials: credentials.NewStaticCredentials(
+				os.Getenv("OSS_ACCESS_KEY_ID"), os.Getenv("OSS_ACCESS_KEY_SECRET"), ""),
+			Region:   aws.String(cfg.Region),
+			Endpoint: aws.String(cfg.Endpoint),
+			// 强制使用 /bucket/key 的形态
+			S3ForcePathStyle: aws.Bool(true),
+		})
+		if err != nil {
+			panic(err)
+		}
+		return ossx.NewS3Store(s3.New(sess), cfg.Bucket)
+	default:
+		panic("未知的对象存储类型 " + cfg.Type)
+	}
+}
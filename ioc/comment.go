@@ -0,0 +1,30 @@
+package ioc
+
+import (
+	commentv1 "geektime/webook/api/proto/gen/comment/v1"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// InitCommentGRPCClient 评论服务没有注册到 etcd，直接连地址
+func InitCommentGRPCClient() commentv1.CommentServiceClient {
+	type Config struct {
+		Addr   string `yaml:"addr"`
+		Secure bool   `yaml:"secure"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("grpc.client.comment", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	var opts []grpc.DialOption
+	if !cfg.Secure {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	cc, err := grpc.Dial(cfg.Addr, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return commentv1.NewCommentServiceClient(cc)
+}
@@ -1,12 +1,20 @@
 package ioc
 
 import (
+	"context"
+	"errors"
+	"geektime/webook/internal/domain"
 	"geektime/webook/internal/job"
+	"geektime/webook/internal/repository"
 	"geektime/webook/internal/service"
 	"geektime/webook/pkg/logger"
 	rlock "github.com/gotomicro/redis-lock"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+	resolver2 "go.etcd.io/etcd/client/v3/naming/resolver"
+	"net/http"
 	"time"
 )
 
@@ -16,7 +24,18 @@ func InitRankingJob(svc service.RankingService, l logger.LoggerV1,
 	return job.NewRankingJob(svc, l, time.Second*30, rlockClient)
 }
 
-func InitJobs(l logger.LoggerV1, rjob *job.RankingJob) *cron.Cron {
+// InitUnboundedRankingJob 总榜要扫全部帖子，给足时间
+func InitUnboundedRankingJob(svc service.RankingService, l logger.LoggerV1,
+	rlockClient *rlock.Client) *job.UnboundedRankingJob {
+	return job.NewUnboundedRankingJob(svc, l, time.Minute*10, rlockClient)
+}
+
+func InitRankingRebaseJob(svc service.IncrRankingService) *job.RankingRebaseJob {
+	return job.NewRankingRebaseJob(svc, time.Second*10)
+}
+
+func InitJobs(l logger.LoggerV1, rjob *job.RankingJob, unboundedJob *job.UnboundedRankingJob,
+	rebaseJob *job.RankingRebaseJob) *cron.Cron {
 	builder := job.NewCronJobBuilder(l, prometheus.SummaryOpts{
 		Namespace: "geekbang_daming",
 		Subsystem: "webook",
@@ -36,5 +55,107 @@ func InitJobs(l logger.LoggerV1, rjob *job.RankingJob) *cron.Cron {
 	if err != nil {
 		panic(err)
 	}
+	//不限时间段的榜单每小时算一次
+	_, err = expr.AddJob("@every 1h", builder.Build(unboundedJob))
+	if err != nil {
+		panic(err)
+	}
+	//半衰期榜单增量更新，每10分钟把分数衰减到现在
+	_, err = expr.AddJob("@every 10m", builder.Build(rebaseJob))
+	if err != nil {
+		panic(err)
+	}
 	return expr
 }
+
+// InitCronJobService 持有 job 的节点超过 job.lease 没有续约，job 就会被别的节点抢走
+func InitCronJobService(repo repository.CronJobRepository, l logger.LoggerV1) service.CronJobService {
+	lease := viper.GetDuration("job.lease")
+	if lease <= 0 {
+		lease = time.Minute * 3
+	}
+	return service.NewCronJobService(repo, l, lease)
+}
+
+// InitHTTPExecutor 业务方要用 job.http.secret 校验签名，只能访问 job.http.hosts 里面的主机
+func InitHTTPExecutor(l logger.LoggerV1) *job.HTTPExecutor {
+	type Config struct {
+		Secret string   `yaml:"secret"`
+		Hosts  []string `yaml:"hosts"`
+		// Timeout 单次请求的超时
+		Timeout time.Duration `yaml:"timeout"`
+		Retries int           `yaml:"retries"`
+	}
+	cfg := Config{
+		Timeout: time.Second * 10,
+		Retries: 2,
+	}
+	err := viper.UnmarshalKey("job.http", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	client := &http.Client{
+		// 不跟随重定向，不然白名单里面的地址可以把请求转到别的地方
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return job.NewHTTPExecutor(client, cfg.Secret, cfg.Hosts, cfg.Timeout, cfg.Retries, l)
+}
+
+// InitGRPCExecutor 通过 etcd 找到实现了 JobExecutorService 的业务方
+func InitGRPCExecutor(client *etcdv3.Client) *job.GRPCExecutor {
+	timeout := viper.GetDuration("job.grpc.timeout")
+	if timeout <= 0 {
+		timeout = time.Second * 10
+	}
+	resolver, err := resolver2.NewBuilder(client)
+	if err != nil {
+		panic(err)
+	}
+	return job.NewGRPCExecutor(resolver, timeout)
+}
+
+// InitScheduler 基于 MySQL 抢占的分布式调度
+func InitScheduler(l logger.LoggerV1, svc service.CronJobService,
+	publishExec *job.ScheduledPublishExecutor, purgeExec *job.TrashPurgeExecutor,
+	httpExec *job.HTTPExecutor, grpcExec *job.GRPCExecutor) *job.Scheduler {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	//定时发表每 30 秒检查一次，job 已经存在就不用再插入了
+	err := svc.AddJob(ctx, domain.Job{
+		Name:       "scheduled_publish",
+		Executor:   publishExec.Name(),
+		Expression: "@every 30s",
+	})
+	if err != nil && !errors.Is(err, service.ErrJobDuplicate) {
+		panic(err)
+	}
+	//回收站里面超过 30 天的帖子每小时清理一次
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       "trash_purge",
+		Executor:   purgeExec.Name(),
+		Expression: "@every 1h",
+		Cfg:        `{"retentionDays":30}`,
+	})
+	if err != nil && !errors.Is(err, service.ErrJobDuplicate) {
+		panic(err)
+	}
+	//支付超时的微信订单每分钟同步一次，由 payment 服务执行
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       "sync_wechat_order_job",
+		Executor:   grpcExec.Name(),
+		Expression: "@every 1m",
+		Cfg:        `{"service":"payment"}`,
+	})
+	if err != nil && !errors.Is(err, service.ErrJobDuplicate) {
+		panic(err)
+	}
+	scheduler := job.NewScheduler(svc, l)
+	scheduler.RegisterExecutor(publishExec)
+	scheduler.RegisterExecutor(purgeExec)
+	//远程执行器，业务方的 job 不用编译进来
+	scheduler.RegisterExecutor(httpExec)
+	scheduler.RegisterExecutor(grpcExec)
+	return scheduler
+}
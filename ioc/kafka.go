@@ -1,6 +1,8 @@
 package ioc
 
 import (
+	"geektime/webook/internal/events/ranking"
+	"geektime/webook/pkg/saramax"
 	"github.com/IBM/sarama"
 	"github.com/spf13/viper"
 )
@@ -29,3 +31,7 @@ func InitSyncProducer(c sarama.Client) sarama.SyncProducer {
 	}
 	return p
 }
+
+func InitConsumers(c1 *ranking.CntChangeConsumer) []saramax.Consumer {
+	return []saramax.Consumer{c1}
+}
@@ -0,0 +1,48 @@
+package ioc
+
+import (
+	"context"
+	"geektime/webook/internal/repository/dao"
+	"github.com/bwmarrin/snowflake"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+func InitMongoDB() *mongo.Database {
+	type Config struct {
+		URI      string `yaml:"uri"`
+		Database string `yaml:"database"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("mongo", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	client, err := mongo.Connect(options.Client().ApplyURI(cfg.URI))
+	if err != nil {
+		panic(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err = client.Ping(ctx, nil)
+	if err != nil {
+		panic(err)
+	}
+	mdb := client.Database(cfg.Database)
+	err = dao.InitCollection(mdb)
+	if err != nil {
+		panic(err)
+	}
+	return mdb
+}
+
+// InitSnowflakeNode 每个实例的 node 都要不一样，不然会生成重复的 id
+func InitSnowflakeNode() *snowflake.Node {
+	node, err := snowflake.NewNode(viper.GetInt64("mongo.node"))
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
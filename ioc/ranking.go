@@ -0,0 +1,51 @@
+package ioc
+
+import (
+	"fmt"
+	"geektime/webook/internal/domain"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// InitRankingBoards 榜单和热度公式都在配置里面
+func InitRankingBoards() []domain.RankingBoard {
+	type Formula struct {
+		Like     float64       `yaml:"like"`
+		Read     float64       `yaml:"read"`
+		Collect  float64       `yaml:"collect"`
+		Comment  float64       `yaml:"comment"`
+		Gravity  float64       `yaml:"gravity"`
+		HalfLife time.Duration `yaml:"halfLife"`
+	}
+	type Board struct {
+		Name    string        `yaml:"name"`
+		Period  time.Duration `yaml:"period"`
+		Tag     string        `yaml:"tag"`
+		N       int           `yaml:"n"`
+		Formula Formula       `yaml:"formula"`
+	}
+	var cfg []Board
+	err := viper.UnmarshalKey("ranking.boards", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	names := make(map[string]struct{}, len(cfg))
+	res := make([]domain.RankingBoard, 0, len(cfg))
+	for _, b := range cfg {
+		if _, ok := names[b.Name]; ok || b.Name == "" {
+			panic(fmt.Errorf("榜单名字不合法或者重复了: %q", b.Name))
+		}
+		names[b.Name] = struct{}{}
+		if b.N <= 0 {
+			b.N = 100
+		}
+		res = append(res, domain.RankingBoard{
+			Name:    b.Name,
+			Period:  b.Period,
+			Tag:     b.Tag,
+			N:       b.N,
+			Formula: domain.RankingFormula(b.Formula),
+		})
+	}
+	return res
+}
@@ -0,0 +1,36 @@
+package ioc
+
+import (
+	searchv1 "geektime/webook/api/proto/gen/search/v1"
+	"github.com/spf13/viper"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+	resolver2 "go.etcd.io/etcd/client/v3/naming/resolver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// InitSearchGRPCClient 使用服务发现的客户端
+func InitSearchGRPCClient(client *etcdv3.Client) searchv1.SearchServiceClient {
+	type Config struct {
+		Addr   string `yaml:"addr"`
+		Secure bool   `yaml:"secure"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("grpc.client.search", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	resolver, err := resolver2.NewBuilder(client)
+	if err != nil {
+		panic(err)
+	}
+	opts := []grpc.DialOption{grpc.WithResolvers(resolver)}
+	if !cfg.Secure {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	cc, err := grpc.Dial(cfg.Addr, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return searchv1.NewSearchServiceClient(cc)
+}
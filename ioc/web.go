@@ -4,10 +4,12 @@ import (
 	"geektime/webook/internal/web"
 	jwt2 "geektime/webook/internal/web/jwt"
 	"geektime/webook/internal/web/middleware"
+	"geektime/webook/pkg/ginx"
 	"geektime/webook/pkg/ginx/middlewares/metric"
 	"geektime/webook/pkg/logger"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"strings"
@@ -18,7 +20,12 @@ func InitWebServer(
 	mdls []gin.HandlerFunc,
 	userHandler *web.UserHandler,
 	wechatHandler *web.OAuth2WechatHandler,
-	articleHandler *web.ArticleHandler) *gin.Engine {
+	articleHandler *web.ArticleHandler,
+	searchHandler *web.SearchHandler,
+	authorHandler *web.AuthorHandler,
+	historyHandler *web.HistoryHandler,
+	collectionHandler *web.CollectionHandler,
+	rankingHandler *web.RankingHandler) *gin.Engine {
 
 	r := gin.Default()
 	r.Use(mdls...)
@@ -26,9 +33,36 @@ func InitWebServer(
 	userHandler.RegisterRoutes(r)
 	wechatHandler.RegisterRoutes(r)
 	articleHandler.RegisterRoutes(r)
+	searchHandler.RegisterRoutes(r)
+	authorHandler.RegisterRoutes(r)
+	historyHandler.RegisterRoutes(r)
+	collectionHandler.RegisterRoutes(r)
+	rankingHandler.RegisterRoutes(r)
 	return r
 }
 
+// InitAdminWebServer 管理后台的 server，默认只监听本机，用配置里面的 token 校验
+func InitAdminWebServer(jobHandler *web.JobHandler) *ginx.Server {
+	type Config struct {
+		Addr  string `yaml:"addr"`
+		Token string `yaml:"token"`
+	}
+	cfg := Config{
+		Addr: "127.0.0.1:8085",
+	}
+	err := viper.UnmarshalKey("admin.http", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	engine := gin.Default()
+	engine.Use(middleware.NewAdminTokenMiddlewareBuilder(cfg.Token).Build())
+	jobHandler.RegisterRoutes(engine)
+	return &ginx.Server{
+		Engine: engine,
+		Addr:   cfg.Addr,
+	}
+}
+
 func InitMiddlewares(jwtHdl jwt2.JwtHandler, l logger.LoggerV1) []gin.HandlerFunc {
 	//web请求日志打印 配置
 	/*bd := middleware.NewLogMiddlewareBuilder(func(ctx context.Context, al *middleware.AccessLog) {
@@ -94,6 +128,8 @@ func InitMiddlewares(jwtHdl jwt2.JwtHandler, l logger.LoggerV1) []gin.HandlerFun
 			IgnorePaths("/oauth2/wechat/authurl").
 			IgnorePaths("/oauth2/wechat/callback").
 			IgnorePaths("/refresh_token").
+			IgnorePaths("/users/:id/public").
+			IgnorePaths("/ranking/:board").
 			Build(),
 	}
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/fsnotify/fsnotify"
 	"github.com/gin-gonic/gin"
@@ -23,19 +25,37 @@ func main() {
 	//	defer cancel()
 	//	tpCancel(ctx)
 	//}()
-	////启动kafka消费者
-	//for _, c := range app.consumers {
-	//	err := c.StartV1()
-	//	if err != nil {
-	//		panic(err)
-	//	}
-	//}
+	//启动kafka消费者
+	for _, c := range app.consumers {
+		err := c.Start()
+		if err != nil {
+			panic(err)
+		}
+	}
 	//启动定时job
 	app.cron.Start()
 	defer func() {
 		// 等待定时任务退出
 		<-app.cron.Stop().Done()
 	}()
+	//启动分布式任务调度
+	schCtx, schCancel := context.WithCancel(context.Background())
+	defer schCancel()
+	go func() {
+		err := app.scheduler.Schedule(schCtx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			zap.L().Error("任务调度退出", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		//后台管理端口
+		//管理后台起不来不影响对外的服务
+		err := app.webAdmin.Start()
+		if err != nil {
+			zap.L().Error("管理后台启动失败", zap.Error(err))
+		}
+	}()
 
 	app.server.GET("/hello", func(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "hello")
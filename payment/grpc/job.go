@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+	jobv1 "geektime/webook/api/proto/gen/job/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Job 交给 webook 调度器远程调度的任务
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// JobExecutorServer 按照 job 的名字找到本地的任务执行
+type JobExecutorServer struct {
+	jobv1.UnimplementedJobExecutorServiceServer
+	jobs map[string]Job
+}
+
+func NewJobExecutorServer(jobs ...Job) *JobExecutorServer {
+	res := &JobExecutorServer{jobs: make(map[string]Job, len(jobs))}
+	for _, j := range jobs {
+		res.jobs[j.Name()] = j
+	}
+	return res
+}
+
+func (s *JobExecutorServer) Register(server *grpc.Server) {
+	jobv1.RegisterJobExecutorServiceServer(server, s)
+}
+
+func (s *JobExecutorServer) Execute(ctx context.Context, req *jobv1.ExecuteRequest) (*jobv1.ExecuteResponse, error) {
+	j, ok := s.jobs[req.GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "未注册 job %s", req.GetName())
+	}
+	err := j.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &jobv1.ExecuteResponse{}, nil
+}
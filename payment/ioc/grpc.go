@@ -11,6 +11,7 @@ import (
 )
 
 func InitGRPCServer(wesvc *grpc2.WechatServiceServer,
+	jobSvc *grpc2.JobExecutorServer,
 	ecli *clientv3.Client,
 	l logger.LoggerV1) *grpcx.Server {
 	type Config struct {
@@ -26,6 +27,7 @@ func InitGRPCServer(wesvc *grpc2.WechatServiceServer,
 		ilogger.NewInterceptorBuilder(l).BuildServerUnaryInterceptor(),
 	))
 	wesvc.Register(server)
+	jobSvc.Register(server)
 	return &grpcx.Server{
 		Server:  server,
 		Port:    cfg.Port,
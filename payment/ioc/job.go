@@ -0,0 +1,13 @@
+package ioc
+
+import (
+	"geektime/webook/payment/grpc"
+	"geektime/webook/payment/job"
+	"geektime/webook/payment/service/wechat"
+	"geektime/webook/pkg/logger"
+)
+
+// InitJobExecutorServer 什么时候执行由 webook 的调度器决定
+func InitJobExecutorServer(svc *wechat.NativePaymentService, l logger.LoggerV1) *grpc.JobExecutorServer {
+	return grpc.NewJobExecutorServer(job.NewSyncWechatOrderJob(svc, l))
+}
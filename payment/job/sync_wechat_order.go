@@ -7,18 +7,24 @@ import (
 	"time"
 )
 
+// SyncWechatOrderJob 由 webook 的调度器通过 JobExecutorService 统一调度
 type SyncWechatOrderJob struct {
 	svc *wechat.NativePaymentService
 	l   logger.LoggerV1
 }
 
+func NewSyncWechatOrderJob(svc *wechat.NativePaymentService, l logger.LoggerV1) *SyncWechatOrderJob {
+	return &SyncWechatOrderJob{svc: svc, l: l}
+}
+
 func (s *SyncWechatOrderJob) Name() string {
 	return "sync_wechat_order_job"
 }
 
 // Run 我这个定时任务，多久运行一次？
 // 不必特别频繁，比如说一分钟运行一次
-func (s *SyncWechatOrderJob) Run() error {
+// ctx 是调度器给的，超时了就不再继续同步
+func (s *SyncWechatOrderJob) Run(ctx context.Context) error {
 	// 定时找到超时的微信支付订单，然后发起同步
 	// 针对过期订单
 	t := time.Now().Add(-time.Minute * 31)
@@ -27,16 +33,16 @@ func (s *SyncWechatOrderJob) Run() error {
 	offset := 0
 	const limit = 100
 	for {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
-		pmts, err := s.svc.FindExpiredPayment(ctx, offset, limit, t)
+		qctx, cancel := context.WithTimeout(ctx, time.Second*3)
+		pmts, err := s.svc.FindExpiredPayment(qctx, offset, limit, t)
 		cancel()
 		if err != nil {
 			// 如果不中断
 			return err
 		}
 		for _, pmt := range pmts {
-			ctx, cancel = context.WithTimeout(context.Background(), time.Second*3)
-			err = s.svc.SyncWechatInfo(ctx, pmt.BizTradeNO)
+			qctx, cancel = context.WithTimeout(ctx, time.Second*3)
+			err = s.svc.SyncWechatInfo(qctx, pmt.BizTradeNO)
 			cancel()
 			if err != nil {
 				s.l.Error("同步微信订单状态失败", logger.Error(err),
@@ -24,6 +24,7 @@ func InitApp() *App {
 		ioc.InitWechatNativeService,
 		ioc.InitWechatConfig,
 		ioc.InitWechatNotifyHandler,
+		ioc.InitJobExecutorServer,
 		ioc.InitGRPCServer,
 		web.NewWechatHandler,
 		ioc.InitGinServer,
@@ -30,8 +30,9 @@ func InitApp() *App {
 	wechatHandler := web.NewWechatHandler(handler, nativePaymentService, loggerV1)
 	server := ioc.InitGinServer(wechatHandler)
 	wechatServiceServer := grpc.NewWechatServiceServer(nativePaymentService)
+	jobExecutorServer := ioc.InitJobExecutorServer(nativePaymentService, loggerV1)
 	clientv3Client := ioc.InitEtcdClient()
-	grpcxServer := ioc.InitGRPCServer(wechatServiceServer, clientv3Client, loggerV1)
+	grpcxServer := ioc.InitGRPCServer(wechatServiceServer, jobExecutorServer, clientv3Client, loggerV1)
 	app := &App{
 		WebServer:  server,
 		GRPCServer: grpcxServer,
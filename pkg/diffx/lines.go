@@ -0,0 +1,118 @@
+package diffx
+
+import (
+	"errors"
+	"strings"
+)
+
+type Op uint8
+
+const (
+	// OpEqual 两边都有的行
+	OpEqual Op = iota
+	// OpDelete 只在旧文本中的行
+	OpDelete
+	// OpInsert 只在新文本中的行
+	OpInsert
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpDelete:
+		return "delete"
+	case OpInsert:
+		return "insert"
+	default:
+		return "equal"
+	}
+}
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// MaxLines 去掉首尾相同的行之后，两边最多允许多少行不一样
+// 动态规划的表是 O(n*m) 的，不限制的话一篇很长的帖子就能把内存吃光
+const MaxLines = 2000
+
+// ErrTooManyLines 改动的范围太大，不做比较
+var ErrTooManyLines = errors.New("diffx: 改动的行数太多")
+
+// Lines 按行比较 oldText 和 newText，基于最长公共子序列
+// 先去掉首尾相同的行，一般的修改只动了中间一小段，剩下的部分用 O(n*m) 的动态规划
+func Lines(oldText, newText string) ([]Line, error) {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA) > MaxLines || len(midB) > MaxLines {
+		return nil, ErrTooManyLines
+	}
+	res := make([]Line, 0, max(len(a), len(b)))
+	for _, text := range a[:prefix] {
+		res = append(res, Line{Op: OpEqual, Text: text})
+	}
+	res = lcsLines(res, midA, midB)
+	for _, text := range a[len(a)-suffix:] {
+		res = append(res, Line{Op: OpEqual, Text: text})
+	}
+	return res, nil
+}
+
+// lcsLines 比较 a 和 b，结果追加到 res 后面
+func lcsLines(res []Line, a, b []string) []Line {
+	n, m := len(a), len(b)
+	// lcs[i][j] 表示 a[i:] 和 b[j:] 的最长公共子序列长度，行数有上限，int32 够用
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			res = append(res, Line{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, Line{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			res = append(res, Line{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		res = append(res, Line{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		res = append(res, Line{Op: OpInsert, Text: b[j]})
+	}
+	return res
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,103 @@
+package diffx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []Line
+	}{
+		{
+			name:    "完全相同",
+			oldText: "a\nb",
+			newText: "a\nb",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+			},
+		},
+		{
+			name:    "新增",
+			oldText: "",
+			newText: "a\nb",
+			want: []Line{
+				{Op: OpInsert, Text: "a"},
+				{Op: OpInsert, Text: "b"},
+			},
+		},
+		{
+			name:    "删除",
+			oldText: "a\nb",
+			newText: "",
+			want: []Line{
+				{Op: OpDelete, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+			},
+		},
+		{
+			name:    "修改中间一行",
+			oldText: "a\nb\nc",
+			newText: "a\nx\nc",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpInsert, Text: "x"},
+				{Op: OpEqual, Text: "c"},
+			},
+		},
+		{
+			// 前缀和后缀不能重叠
+			name:    "重复的行",
+			oldText: "a\na",
+			newText: "a\na\na",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "a"},
+				{Op: OpInsert, Text: "a"},
+			},
+		},
+		{
+			name:    "Windows 换行",
+			oldText: "a\r\nb",
+			newText: "a\nb\nc",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+				{Op: OpInsert, Text: "c"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lines, err := Lines(tc.oldText, tc.newText)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, lines)
+		})
+	}
+}
+
+func TestLines_TooManyLines(t *testing.T) {
+	var oldText, newText strings.Builder
+	for i := 0; i < MaxLines+1; i++ {
+		oldText.WriteString("old " + strconv.Itoa(i) + "\n")
+		newText.WriteString("new " + strconv.Itoa(i) + "\n")
+	}
+	_, err := Lines(oldText.String(), newText.String())
+	assert.Equal(t, ErrTooManyLines, err)
+
+	// 首尾相同的行不算，长文章只改了一行也可以比较
+	long := strings.Repeat("same\n", MaxLines*2)
+	lines, err := Lines(long+"a\n"+long, long+"b\n"+long)
+	require.NoError(t, err)
+	assert.Equal(t, MaxLines*4+3, len(lines))
+	assert.Equal(t, Line{Op: OpDelete, Text: "a"}, lines[MaxLines*2])
+	assert.Equal(t, Line{Op: OpInsert, Text: "b"}, lines[MaxLines*2+1])
+}
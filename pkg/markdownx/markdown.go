@@ -0,0 +1,125 @@
+package markdownx
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// WordsPerMinute 估算阅读时间用的阅读速度，中文按字算，英文按单词算
+const WordsPerMinute = 300
+
+var (
+	// 不开 WithUnsafe，Markdown 里面直接写的 HTML 在渲染的时候就会被丢掉
+	md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	// ugc 是给用户内容用的白名单，会去掉 script、事件属性和 javascript: 链接
+	ugc = bluemonday.UGCPolicy()
+	// strict 去掉所有标签，只留下文本
+	strict = bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true)
+)
+
+// Content 一篇 Markdown 计算出来的全部结果
+type Content struct {
+	// HTML 过滤之后可以直接展示的 HTML
+	HTML string
+	// Abstract 纯文本摘要
+	Abstract string
+	// WordCnt 字数
+	WordCnt int
+	// ReadingTime 预计阅读时间
+	ReadingTime time.Duration
+}
+
+// Process 渲染 Markdown，同时算出摘要、字数和阅读时间
+// abstractLen 是摘要最多的字符数
+func Process(src string, abstractLen int) (Content, error) {
+	h, err := Render(src)
+	if err != nil {
+		return Content{}, err
+	}
+	text := toText(h)
+	words := WordCount(text)
+	return Content{
+		HTML:        h,
+		Abstract:    truncate(text, abstractLen),
+		WordCnt:     words,
+		ReadingTime: ReadingTime(words),
+	}, nil
+}
+
+// Render 把 Markdown 渲染成 HTML，再按照白名单过滤掉可能导致 XSS 的标签和属性
+func Render(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+	return ugc.Sanitize(buf.String()), nil
+}
+
+// PlainText Markdown 对应的纯文本，连续的空白会合并成一个空格
+func PlainText(src string) string {
+	h, err := Render(src)
+	if err != nil {
+		// 渲染失败就退化成直接去掉标签
+		h = src
+	}
+	return toText(h)
+}
+
+// Abstract 纯文本摘要，最多 n 个字符
+func Abstract(src string, n int) string {
+	return truncate(PlainText(src), n)
+}
+
+// WordCount 统计字数，中日韩文字一个字算一个，其余的按照单词算
+func WordCount(text string) int {
+	cnt := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cnt++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				cnt++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return cnt
+}
+
+// ReadingTime 按照 WordsPerMinute 估算阅读时间，向上取整到分钟，有内容的话至少一分钟
+func ReadingTime(words int) time.Duration {
+	if words <= 0 {
+		return 0
+	}
+	minutes := (words + WordsPerMinute - 1) / WordsPerMinute
+	return time.Duration(minutes) * time.Minute
+}
+
+func toText(h string) string {
+	text := html.UnescapeString(strict.Sanitize(h))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func truncate(text string, n int) string {
+	str := []rune(text)
+	if len(str) > n {
+		str = str[:n]
+	}
+	return string(str)
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
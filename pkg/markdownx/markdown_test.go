@@ -0,0 +1,116 @@
+package markdownx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name      string
+		src       string
+		contains  []string
+		forbidden []string
+	}{
+		{
+			name:     "普通 Markdown",
+			src:      "# 标题\n\n**加粗** 和 `code`",
+			contains: []string{"<h1", "标题</h1>", "<strong>加粗</strong>", "<code>code</code>"},
+		},
+		{
+			name:     "GFM 表格",
+			src:      "| a | b |\n| - | - |\n| 1 | 2 |",
+			contains: []string{"<table>", "<td>1</td>"},
+		},
+		{
+			name:      "直接写的 script",
+			src:       "hello <script>alert(1)</script>",
+			contains:  []string{"hello"},
+			forbidden: []string{"<script", "alert(1)</script>"},
+		},
+		{
+			name:      "javascript 链接",
+			src:       "[点我](javascript:alert(1))",
+			contains:  []string{"点我"},
+			forbidden: []string{"javascript:"},
+		},
+		{
+			name:      "图片的事件属性",
+			src:       `<img src="x" onerror="alert(1)">`,
+			forbidden: []string{"onerror"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := Render(tc.src)
+			require.NoError(t, err)
+			for _, s := range tc.contains {
+				assert.Contains(t, h, s)
+			}
+			for _, s := range tc.forbidden {
+				assert.NotContains(t, h, s)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "去掉 Markdown 语法",
+			src:  "# 标题\n\n**加粗** 和 [链接](https://example.com)",
+			want: "标题 加粗 和 链接",
+		},
+		{
+			name: "转义字符还原",
+			src:  "a < b && c > d",
+			want: "a < b && c > d",
+		},
+		{
+			name: "HTML 标签被丢掉",
+			src:  "前<script>alert(1)</script>后",
+			want: "前alert(1)后",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, PlainText(tc.src))
+		})
+	}
+}
+
+func TestWordCount(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "空", text: "", want: 0},
+		{name: "英文", text: "hello, world! go1.22", want: 4},
+		{name: "中文", text: "你好，世界", want: 4},
+		{name: "混合", text: "学习 Go 语言", want: 5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, WordCount(tc.text))
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	src := "# 标题\n\n" + strings.Repeat("字", 700)
+	c, err := Process(src, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "标题 字字字字字字字", c.Abstract)
+	assert.Equal(t, 702, c.WordCnt)
+	assert.Equal(t, 3*time.Minute, c.ReadingTime)
+	assert.Contains(t, c.HTML, "<h1")
+}
@@ -0,0 +1,90 @@
+package ossx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStore 用本地目录模拟对象存储，一个对象就是一个文件，主要给测试和本地开发用
+type LocalStore struct {
+	dir string
+	// baseURL 是对外访问 dir 的地址，PresignGet 拼出来的链接不做签名
+	baseURL string
+}
+
+func NewLocalStore(dir string, baseURL string) (*LocalStore, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalStore{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+func (l *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再改名，避免读到写了一半的内容
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (l *LocalStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+func (l *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	// 和 S3 一样，删除不存在的对象不算错误
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *LocalStore) PresignGet(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s?expires=%d", l.baseURL, url.PathEscape(key),
+		time.Now().Add(expiration).Unix()), nil
+}
+
+// path key 不能跳出 dir
+func (l *LocalStore) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(key) {
+		return "", fmt.Errorf("非法的 key %q", key)
+	}
+	return filepath.Join(l.dir, key), nil
+}
@@ -0,0 +1,55 @@
+package ossx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "http://localhost:8080/oss/")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = store.Get(ctx, "1")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	err = store.Put(ctx, "1", []byte("# 标题"), "text/markdown;charset=utf-8")
+	require.NoError(t, err)
+	data, err := store.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "# 标题", string(data))
+
+	// 覆盖写
+	err = store.Put(ctx, "1", []byte("新的内容"), "text/markdown;charset=utf-8")
+	require.NoError(t, err)
+	data, err = store.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "新的内容", string(data))
+
+	u, err := store.PresignGet(ctx, "1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(u, "http://localhost:8080/oss/1?expires="))
+
+	err = store.Delete(ctx, "1")
+	require.NoError(t, err)
+	_, err = store.Get(ctx, "1")
+	assert.Equal(t, ErrObjectNotFound, err)
+	// 删除不存在的对象
+	assert.NoError(t, store.Delete(ctx, "1"))
+}
+
+func TestLocalStore_IllegalKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "")
+	require.NoError(t, err)
+	ctx := context.Background()
+	for _, key := range []string{"", "../1", "/etc/passwd"} {
+		assert.Error(t, store.Put(ctx, key, []byte("x"), ""), key)
+		_, err = store.Get(ctx, key)
+		assert.Error(t, err, key)
+	}
+}
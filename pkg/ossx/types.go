@@ -0,0 +1,21 @@
+package ossx
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrObjectNotFound 对象不存在
+var ErrObjectNotFound = errors.New("对象不存在")
+
+// ObjectStore 对象存储的抽象
+// 线上用兼容 S3 协议的云存储（腾讯云 COS、阿里云 OSS、MinIO 都可以），测试用本地文件系统
+type ObjectStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get 对象不存在的时候返回 ErrObjectNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	// PresignGet 生成一个有时效的下载链接，前端可以直接用这个链接读取内容
+	PresignGet(ctx context.Context, key string, expiration time.Duration) (string, error)
+}
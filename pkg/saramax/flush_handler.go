@@ -0,0 +1,173 @@
+package saramax
+
+import (
+	"encoding/json"
+	"geektime/webook/pkg/logger"
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
+)
+
+// FlushHandler 攒一批消息再处理，凑够 maxSize 条或者过了 interval 就刷一次
+// 处理成功之后才提交偏移量，处理失败就一直重试，直到分区被分给别人
+// 没有提交的消息会被重新消费，所以 fn 要能容忍重复
+type FlushHandler[T any] struct {
+	fn       func(msgs []*sarama.ConsumerMessage, ts []T) error
+	l        logger.LoggerV1
+	m        *FlushMetrics
+	maxSize  int
+	interval time.Duration
+	// retryInterval 刷新失败之后隔多久重试
+	retryInterval time.Duration
+}
+
+func NewFlushHandler[T any](l logger.LoggerV1, m *FlushMetrics,
+	maxSize int, interval time.Duration,
+	fn func(msgs []*sarama.ConsumerMessage, ts []T) error) *FlushHandler[T] {
+	return &FlushHandler[T]{
+		fn:            fn,
+		l:             l,
+		m:             m,
+		maxSize:       maxSize,
+		interval:      interval,
+		retryInterval: time.Second,
+	}
+}
+
+func (f *FlushHandler[T]) Setup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (f *FlushHandler[T]) Cleanup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (f *FlushHandler[T]) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	msgs := claim.Messages()
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	batch := make([]*sarama.ConsumerMessage, 0, f.maxSize)
+	ts := make([]T, 0, f.maxSize)
+	lastFlush := time.Now()
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		for {
+			err := f.fn(batch, ts)
+			if err == nil {
+				break
+			}
+			f.l.Error("刷新消息失败",
+				logger.String("topic", claim.Topic()),
+				logger.Int32("partition", claim.Partition()),
+				logger.Int("size", len(batch)),
+				logger.Error(err))
+			select {
+			case <-session.Context().Done():
+				// 分区已经不归我们了，不提交偏移量，别人会重新消费
+				return err
+			case <-time.After(f.retryInterval):
+			}
+		}
+		for _, msg := range batch {
+			session.MarkMessage(msg, "")
+		}
+		session.Commit()
+		last := batch[len(batch)-1]
+		f.m.observe(claim, len(batch), time.Since(lastFlush), claim.HighWaterMarkOffset()-last.Offset-1)
+		lastFlush = time.Now()
+		batch = batch[:0]
+		ts = ts[:0]
+		return nil
+	}
+
+	for {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				// 重新分配分区之前把手上的刷掉
+				return flush()
+			}
+			// 反序列化失败的消息也要提交，不然会一直卡在这里
+			batch = append(batch, msg)
+			var t T
+			err := json.Unmarshal(msg.Value, &t)
+			if err != nil {
+				f.l.Error("反序列消息体失败",
+					logger.String("topic", msg.Topic),
+					logger.Int32("partition", msg.Partition),
+					logger.Int64("offset", msg.Offset),
+					logger.Error(err))
+			} else {
+				ts = append(ts, t)
+			}
+			if len(batch) >= f.maxSize {
+				if err = flush(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// FlushMetrics 每次刷新的消息数、两次刷新的间隔和刷新之后的消费延迟
+type FlushMetrics struct {
+	size     *prometheus.SummaryVec
+	interval *prometheus.SummaryVec
+	lag      *prometheus.GaugeVec
+}
+
+func NewFlushMetrics(namespace, subsystem, name string) *FlushMetrics {
+	labels := []string{"topic", "partition"}
+	m := &FlushMetrics{
+		size: prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      name + "_flush_size",
+			Help:      "每次刷新的消息数",
+			Objectives: map[float64]float64{
+				0.5:  0.01,
+				0.9:  0.01,
+				0.99: 0.001,
+			},
+		}, labels),
+		interval: prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      name + "_flush_interval_ms",
+			Help:      "两次刷新的间隔",
+			Objectives: map[float64]float64{
+				0.5:  0.01,
+				0.9:  0.01,
+				0.99: 0.001,
+			},
+		}, labels),
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      name + "_lag",
+			Help:      "刷新之后分区里面还有多少消息没有消费",
+		}, labels),
+	}
+	prometheus.MustRegister(m.size, m.interval, m.lag)
+	return m
+}
+
+func (m *FlushMetrics) observe(claim sarama.ConsumerGroupClaim, size int, interval time.Duration, lag int64) {
+	if m == nil {
+		return
+	}
+	partition := strconv.Itoa(int(claim.Partition()))
+	m.size.WithLabelValues(claim.Topic(), partition).Observe(float64(size))
+	m.interval.WithLabelValues(claim.Topic(), partition).Observe(float64(interval.Milliseconds()))
+	m.lag.WithLabelValues(claim.Topic(), partition).Set(float64(lag))
+}
@@ -0,0 +1,140 @@
+package saramax
+
+import (
+	"context"
+	"errors"
+	"geektime/webook/pkg/logger"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	Id int64
+}
+
+func TestFlushHandler_ConsumeClaim(t *testing.T) {
+	testCases := []struct {
+		name string
+		// 前面几次刷新失败
+		failTimes int
+		// 一直失败，直到分区被分走
+		alwaysFail bool
+
+		wantFlushes [][]int64
+		wantMarked  []int64
+	}{
+		{
+			name:        "凑够一批就刷新",
+			wantFlushes: [][]int64{{1, 2}, {3}},
+			wantMarked:  []int64{0, 1, 2},
+		},
+		{
+			name:        "失败之后重试成功才提交",
+			failTimes:   2,
+			wantFlushes: [][]int64{{1, 2}, {1, 2}, {1, 2}, {3}},
+			wantMarked:  []int64{0, 1, 2},
+		},
+		{
+			name:        "一直失败不提交",
+			alwaysFail:  true,
+			wantFlushes: [][]int64{{1, 2}},
+			wantMarked:  []int64{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			session := &fakeSession{ctx: ctx}
+			claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 3)}
+			for i := int64(0); i < 3; i++ {
+				claim.msgs <- &sarama.ConsumerMessage{
+					Topic:  "test",
+					Offset: i,
+					Value:  []byte(`{"Id":` + strconv.FormatInt(i+1, 10) + `}`),
+				}
+			}
+			var flushes [][]int64
+			fails := 0
+			h := NewFlushHandler[testEvent](logger.NewNopLogger(), nil, 2, time.Millisecond*50,
+				func(msgs []*sarama.ConsumerMessage, ts []testEvent) error {
+					ids := make([]int64, 0, len(ts))
+					for _, e := range ts {
+						ids = append(ids, e.Id)
+					}
+					flushes = append(flushes, ids)
+					if tc.alwaysFail {
+						// 分区被分给别人
+						cancel()
+						return errors.New("mock error")
+					}
+					if fails < tc.failTimes {
+						fails++
+						return errors.New("mock error")
+					}
+					return nil
+				})
+			h.retryInterval = time.Millisecond
+			go func() {
+				time.Sleep(time.Millisecond * 200)
+				close(claim.msgs)
+			}()
+			err := h.ConsumeClaim(session, claim)
+			assert.Equal(t, tc.alwaysFail, err != nil)
+			assert.Equal(t, tc.wantFlushes, flushes)
+			assert.Equal(t, tc.wantMarked, session.marked())
+		})
+	}
+}
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx     context.Context
+	mu      sync.Mutex
+	offsets []int64
+}
+
+func (f *fakeSession) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.offsets = append(f.offsets, msg.Offset)
+}
+
+func (f *fakeSession) Commit() {
+}
+
+func (f *fakeSession) marked() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := make([]int64, 0, len(f.offsets))
+	return append(res, f.offsets...)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	msgs chan *sarama.ConsumerMessage
+}
+
+func (f *fakeClaim) Topic() string {
+	return "test"
+}
+
+func (f *fakeClaim) Partition() int32 {
+	return 0
+}
+
+func (f *fakeClaim) HighWaterMarkOffset() int64 {
+	return 3
+}
+
+func (f *fakeClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return f.msgs
+}
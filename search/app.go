@@ -0,0 +1,11 @@
+package main
+
+import (
+	"geektime/webook/pkg/grpcx"
+	"geektime/webook/pkg/saramax"
+)
+
+type App struct {
+	server    *grpcx.Server
+	consumers []saramax.Consumer
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Article 进入搜索索引的帖子，只有线上库的帖子才会被索引
+type Article struct {
+	Id       int64
+	Title    string
+	Content  string
+	AuthorId int64
+	Status   int32
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+// ArticleSearchQuery 搜索条件
+type ArticleSearchQuery struct {
+	// Keywords 为空的时候只按照过滤条件查询
+	Keywords string
+	// AuthorId 为 0 表示不过滤作者
+	AuthorId int64
+	// Start 和 End 是更新时间的范围，零值表示不限制
+	Start  time.Time
+	End    time.Time
+	Offset int
+	Limit  int
+}
+
+type ArticleHit struct {
+	Article Article
+	// TitleHighlight 命中的关键字用 <em></em> 包起来
+	TitleHighlight string
+	// ContentHighlight 只包含命中附近的一段内容
+	ContentHighlight string
+	Score            float64
+}
+
+type ArticleSearchResult struct {
+	Hits  []ArticleHit
+	Total int64
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"geektime/webook/pkg/logger"
+	"geektime/webook/pkg/saramax"
+	"geektime/webook/search/domain"
+	"geektime/webook/search/service"
+	"github.com/IBM/sarama"
+	"time"
+)
+
+const topicArticlePublished = "article_published"
+
+// articleStatusPublished 这里不能引用 webook 里面的定义，只能手写
+const articleStatusPublished = 2
+
+var _ saramax.Consumer = &ArticleConsumer{}
+
+// PublishedEvent 线上库的帖子发生了变化，发表、撤回都会有
+type PublishedEvent struct {
+	Id       int64
+	Title    string
+	Content  string
+	AuthorId int64
+	Status   uint8
+	// 毫秒数
+	Ctime int64
+	Utime int64
+}
+
+type ArticleConsumer struct {
+	client sarama.Client
+	l      logger.LoggerV1
+	svc    service.SearchService
+	// group 消费者组，索引在进程内的时候每个进程都要用一个新的组从头消费
+	group string
+}
+
+func NewArticleConsumer(client sarama.Client, l logger.LoggerV1,
+	svc service.SearchService, group string) *ArticleConsumer {
+	return &ArticleConsumer{client: client, l: l, svc: svc, group: group}
+}
+
+func (a *ArticleConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient(a.group, a.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(),
+			[]string{topicArticlePublished},
+			saramax.NewHandler[PublishedEvent](a.l, a.Consume))
+		if er != nil {
+			a.l.Error("退出了消费循环异常", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+// Consume 已发表的建索引，撤回了（仅自己可见）的删除索引
+func (a *ArticleConsumer) Consume(msg *sarama.ConsumerMessage, evt PublishedEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if evt.Status != articleStatusPublished {
+		return a.svc.DeleteArticle(ctx, evt.Id)
+	}
+	art := domain.Article{
+		Id:       evt.Id,
+		Title:    evt.Title,
+		Content:  evt.Content,
+		AuthorId: evt.AuthorId,
+		Status:   int32(evt.Status),
+		Utime:    time.UnixMilli(evt.Utime),
+	}
+	// 直接发表的帖子没有带上创建时间
+	if evt.Ctime > 0 {
+		art.Ctime = time.UnixMilli(evt.Ctime)
+	}
+	return a.svc.InputArticle(ctx, art)
+}
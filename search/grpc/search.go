@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+	searchv1 "geektime/webook/api/proto/gen/search/v1"
+	"geektime/webook/search/domain"
+	"geektime/webook/search/service"
+	"google.golang.org/grpc"
+	"time"
+)
+
+type SearchServiceServer struct {
+	searchv1.UnimplementedSearchServiceServer
+	svc service.SearchService
+}
+
+func NewSearchServiceServer(svc service.SearchService) *SearchServiceServer {
+	return &SearchServiceServer{svc: svc}
+}
+
+func (s *SearchServiceServer) Register(server *grpc.Server) {
+	searchv1.RegisterSearchServiceServer(server, s)
+}
+
+func (s *SearchServiceServer) SearchArticle(ctx context.Context, req *searchv1.SearchArticleRequest) (*searchv1.SearchArticleResponse, error) {
+	q := domain.ArticleSearchQuery{
+		Keywords: req.GetExpression(),
+		AuthorId: req.GetAuthorId(),
+		Offset:   int(req.GetOffset()),
+		Limit:    int(req.GetLimit()),
+	}
+	if req.GetStartTime() > 0 {
+		q.Start = time.UnixMilli(req.GetStartTime())
+	}
+	if req.GetEndTime() > 0 {
+		q.End = time.UnixMilli(req.GetEndTime())
+	}
+	res, err := s.svc.SearchArticle(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]*searchv1.ArticleHit, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		hits = append(hits, &searchv1.ArticleHit{
+			Article:          s.toDTO(hit.Article),
+			TitleHighlight:   hit.TitleHighlight,
+			ContentHighlight: hit.ContentHighlight,
+			Score:            hit.Score,
+		})
+	}
+	return &searchv1.SearchArticleResponse{
+		Hits:  hits,
+		Total: res.Total,
+	}, nil
+}
+
+func (s *SearchServiceServer) InputArticle(ctx context.Context, req *searchv1.InputArticleRequest) (*searchv1.InputArticleResponse, error) {
+	err := s.svc.InputArticle(ctx, s.toDomain(req.GetArticle()))
+	return &searchv1.InputArticleResponse{}, err
+}
+
+func (s *SearchServiceServer) DeleteArticle(ctx context.Context, req *searchv1.DeleteArticleRequest) (*searchv1.DeleteArticleResponse, error) {
+	err := s.svc.DeleteArticle(ctx, req.GetId())
+	return &searchv1.DeleteArticleResponse{}, err
+}
+
+func (s *SearchServiceServer) toDTO(art domain.Article) *searchv1.Article {
+	return &searchv1.Article{
+		Id:       art.Id,
+		Title:    art.Title,
+		Content:  art.Content,
+		AuthorId: art.AuthorId,
+		Status:   art.Status,
+		Ctime:    art.Ctime.UnixMilli(),
+		Utime:    art.Utime.UnixMilli(),
+	}
+}
+
+func (s *SearchServiceServer) toDomain(art *searchv1.Article) domain.Article {
+	return domain.Article{
+		Id:       art.GetId(),
+		Title:    art.GetTitle(),
+		Content:  art.GetContent(),
+		AuthorId: art.GetAuthorId(),
+		Status:   art.GetStatus(),
+		Ctime:    time.UnixMilli(art.GetCtime()),
+		Utime:    time.UnixMilli(art.GetUtime()),
+	}
+}
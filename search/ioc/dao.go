@@ -0,0 +1,36 @@
+package ioc
+
+import (
+	"fmt"
+	"geektime/webook/search/repository/dao"
+	"github.com/spf13/viper"
+)
+
+// InitArticleDAO 根据配置选择索引的实现
+func InitArticleDAO() dao.ArticleDAO {
+	backend := indexBackend()
+	switch backend {
+	case "", "memory":
+		return dao.NewInvertedIndexArticleDAO()
+	default:
+		panic(fmt.Errorf("不支持的索引实现 %s", backend))
+	}
+}
+
+// isMemoryIndex 索引是不是在进程内，重启之后要重建
+func isMemoryIndex() bool {
+	backend := indexBackend()
+	return backend == "" || backend == "memory"
+}
+
+func indexBackend() string {
+	type Config struct {
+		Backend string `yaml:"backend"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("index", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg.Backend
+}
@@ -0,0 +1,33 @@
+package ioc
+
+import (
+	"geektime/webook/pkg/grpcx"
+	"geektime/webook/pkg/logger"
+	grpc2 "geektime/webook/search/grpc"
+	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+)
+
+func InitGRPCxServer(searchServer *grpc2.SearchServiceServer,
+	ecli *clientv3.Client,
+	l logger.LoggerV1) *grpcx.Server {
+	type Config struct {
+		Port    int   `yaml:"port"`
+		EtcdTTL int64 `yaml:"etcdTTL"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("grpc.server", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	server := grpc.NewServer()
+	searchServer.Register(server)
+	return &grpcx.Server{
+		Server: server,
+		Port:   cfg.Port,
+		Name:   "search",
+		L:      l,
+		Client: ecli,
+	}
+}
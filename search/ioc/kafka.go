@@ -0,0 +1,50 @@
+package ioc
+
+import (
+	"fmt"
+	"geektime/webook/pkg/logger"
+	"geektime/webook/pkg/saramax"
+	"geektime/webook/search/events"
+	"geektime/webook/search/service"
+	"github.com/IBM/sarama"
+	"github.com/spf13/viper"
+	"os"
+	"time"
+)
+
+func InitKafkaClient() sarama.Client {
+	type Config struct {
+		Addr []string `yaml:"addr"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("kafka", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	saramaCfg := sarama.NewConfig()
+	// 新的消费者组从最早的消息开始消费，这样才能把已经发表的帖子都建上索引
+	saramaCfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	client, err := sarama.NewClient(cfg.Addr, saramaCfg)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// InitArticleConsumer 进程内的索引重启之后是空的，而且每个进程都要有全量的数据，
+// 所以每次启动都用一个新的消费者组，从最早的消息开始重放发表事件来重建索引。
+// article_published 用帖子 id 做 key，topic 要配置成 cleanup.policy=compact，
+// 这样每篇帖子至少保留最后一条消息，重放出来的就是线上库当前的状态
+func InitArticleConsumer(client sarama.Client, l logger.LoggerV1,
+	svc service.SearchService) *events.ArticleConsumer {
+	group := "search"
+	if isMemoryIndex() {
+		hostname, _ := os.Hostname()
+		group = fmt.Sprintf("search-%s-%d", hostname, time.Now().UnixNano())
+	}
+	return events.NewArticleConsumer(client, l, svc, group)
+}
+
+func InitConsumers(article *events.ArticleConsumer) []saramax.Consumer {
+	return []saramax.Consumer{article}
+}
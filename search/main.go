@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	initViper()
+	app := InitApp()
+	for _, c := range app.consumers {
+		err := c.Start()
+		if err != nil {
+			panic(err)
+		}
+	}
+	err := app.server.ListenAndServe()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func initViper() {
+	cfile := pflag.String("config",
+		"config/config.yaml", "配置文件路径")
+	pflag.Parse()
+	viper.SetConfigFile(*cfile)
+	viper.WatchConfig()
+	err := viper.ReadInConfig()
+	if err != nil {
+		panic(err)
+	}
+}
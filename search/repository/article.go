@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"geektime/webook/search/domain"
+	"geektime/webook/search/repository/dao"
+	"time"
+)
+
+type ArticleRepository interface {
+	InputArticle(ctx context.Context, art domain.Article) error
+	DeleteArticle(ctx context.Context, id int64) error
+	SearchArticle(ctx context.Context, q domain.ArticleSearchQuery) (domain.ArticleSearchResult, error)
+}
+
+type articleRepository struct {
+	dao dao.ArticleDAO
+}
+
+func NewArticleRepository(d dao.ArticleDAO) ArticleRepository {
+	return &articleRepository{dao: d}
+}
+
+func (a *articleRepository) InputArticle(ctx context.Context, art domain.Article) error {
+	return a.dao.InputArticle(ctx, a.toEntity(art))
+}
+
+func (a *articleRepository) DeleteArticle(ctx context.Context, id int64) error {
+	return a.dao.DeleteArticle(ctx, id)
+}
+
+func (a *articleRepository) SearchArticle(ctx context.Context, q domain.ArticleSearchQuery) (domain.ArticleSearchResult, error) {
+	res, err := a.dao.Search(ctx, dao.ArticleQuery{
+		Keywords:  q.Keywords,
+		AuthorId:  q.AuthorId,
+		StartTime: a.toMilli(q.Start),
+		EndTime:   a.toMilli(q.End),
+		Offset:    q.Offset,
+		Limit:     q.Limit,
+	})
+	if err != nil {
+		return domain.ArticleSearchResult{}, err
+	}
+	hits := make([]domain.ArticleHit, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		hits = append(hits, domain.ArticleHit{
+			Article:          a.toDomain(hit.Article),
+			TitleHighlight:   hit.TitleHighlight,
+			ContentHighlight: hit.ContentHighlight,
+			Score:            hit.Score,
+		})
+	}
+	return domain.ArticleSearchResult{Hits: hits, Total: res.Total}, nil
+}
+
+// toMilli 零值表示不限制，不能转成负数的毫秒数
+func (a *articleRepository) toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func (a *articleRepository) toEntity(art domain.Article) dao.Article {
+	return dao.Article{
+		Id:       art.Id,
+		Title:    art.Title,
+		Content:  art.Content,
+		AuthorId: art.AuthorId,
+		Status:   art.Status,
+		Ctime:    a.toMilli(art.Ctime),
+		Utime:    a.toMilli(art.Utime),
+	}
+}
+
+func (a *articleRepository) toDomain(art dao.Article) domain.Article {
+	return domain.Article{
+		Id:       art.Id,
+		Title:    art.Title,
+		Content:  art.Content,
+		AuthorId: art.AuthorId,
+		Status:   art.Status,
+		Ctime:    time.UnixMilli(art.Ctime),
+		Utime:    time.UnixMilli(art.Utime),
+	}
+}
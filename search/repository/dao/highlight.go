@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+	// snippetRunes 内容高亮只返回这么多个字
+	snippetRunes = 120
+	// snippetLead 第一个命中的位置前面保留的字数
+	snippetLead = 20
+)
+
+type span struct {
+	start, end int
+}
+
+// highlight 把 text 中命中 terms 的部分用 <em></em> 包起来
+// 帖子内容是用户输入的，其余部分都要转义，前端才能直接渲染
+func highlight(text string, terms map[string]struct{}) string {
+	return render(text, matchSpans(text, terms), 0, len(text))
+}
+
+// snippet 截取第一个命中附近的一段内容再高亮，没有命中就取开头的一段
+func snippet(text string, terms map[string]struct{}) string {
+	spans := matchSpans(text, terms)
+	from := 0
+	if len(spans) > 0 {
+		from = spans[0].start
+		for i := 0; i < snippetLead && from > 0; i++ {
+			_, size := utf8.DecodeLastRuneInString(text[:from])
+			from -= size
+		}
+	}
+	to := from
+	for i := 0; i < snippetRunes && to < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+	return render(text, spans, from, to)
+}
+
+// matchSpans 找出命中的区间，二元切分出来的相邻 token 是重叠的，这里顺便合并
+func matchSpans(text string, terms map[string]struct{}) []span {
+	var res []span
+	if len(terms) == 0 {
+		return res
+	}
+	for _, t := range tokenize(text) {
+		if _, ok := terms[t.term]; !ok {
+			continue
+		}
+		if n := len(res); n > 0 && t.start <= res[n-1].end {
+			res[n-1].end = max(res[n-1].end, t.end)
+			continue
+		}
+		res = append(res, span{start: t.start, end: t.end})
+	}
+	return res
+}
+
+// render 渲染 text[from:to] 这一段，落在范围外的命中会被截断
+func render(text string, spans []span, from, to int) string {
+	var sb strings.Builder
+	last := from
+	for _, s := range spans {
+		if s.end <= from || s.start >= to {
+			continue
+		}
+		start, end := max(s.start, from), min(s.end, to)
+		sb.WriteString(html.EscapeString(text[last:start]))
+		sb.WriteString(highlightPreTag)
+		sb.WriteString(html.EscapeString(text[start:end]))
+		sb.WriteString(highlightPostTag)
+		last = end
+	}
+	sb.WriteString(html.EscapeString(text[last:to]))
+	return sb.String()
+}
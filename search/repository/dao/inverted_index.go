@@ -0,0 +1,183 @@
+package dao
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+)
+
+// titleWeight 标题里的词比内容里的词更重要
+const titleWeight = 3
+
+// InvertedIndexArticleDAO 进程内的倒排索引
+// 数据都在内存里，重启就没了，只适合开发和测试环境，不依赖 Elasticsearch 就能跑起来
+type InvertedIndexArticleDAO struct {
+	lock sync.RWMutex
+	docs map[int64]indexedArticle
+	// postings 词 -> 帖子 id -> 加权后的词频
+	postings map[string]map[int64]int
+}
+
+type indexedArticle struct {
+	art Article
+	// tf 这篇帖子里每个词加权后的词频，删除的时候也要用
+	tf map[string]int
+}
+
+func NewInvertedIndexArticleDAO() ArticleDAO {
+	return &InvertedIndexArticleDAO{
+		docs:     make(map[int64]indexedArticle),
+		postings: make(map[string]map[int64]int),
+	}
+}
+
+func (i *InvertedIndexArticleDAO) InputArticle(ctx context.Context, art Article) error {
+	tf := make(map[string]int)
+	for _, term := range Tokenize(art.Title) {
+		tf[term] += titleWeight
+	}
+	for _, term := range Tokenize(art.Content) {
+		tf[term]++
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.deleteLocked(art.Id)
+	i.docs[art.Id] = indexedArticle{art: art, tf: tf}
+	for term, cnt := range tf {
+		ids, ok := i.postings[term]
+		if !ok {
+			ids = make(map[int64]int)
+			i.postings[term] = ids
+		}
+		ids[art.Id] = cnt
+	}
+	return nil
+}
+
+func (i *InvertedIndexArticleDAO) DeleteArticle(ctx context.Context, id int64) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.deleteLocked(id)
+	return nil
+}
+
+func (i *InvertedIndexArticleDAO) deleteLocked(id int64) {
+	doc, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	for term := range doc.tf {
+		ids := i.postings[term]
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(i.postings, term)
+		}
+	}
+	delete(i.docs, id)
+}
+
+// Search 所有关键字都要命中，按照 TF-IDF 打分
+// 中文是二元切分的，所有的二元词都命中近似于整个词命中
+func (i *InvertedIndexArticleDAO) Search(ctx context.Context, q ArticleQuery) (ArticleSearchResult, error) {
+	terms := uniqueTerms(Tokenize(q.Keywords))
+	type scored struct {
+		art   Article
+		score float64
+	}
+	var matched []scored
+	i.lock.RLock()
+	if len(terms) == 0 {
+		for _, doc := range i.docs {
+			if q.filter(doc.art) {
+				matched = append(matched, scored{art: doc.art})
+			}
+		}
+	} else {
+		// 从最短的倒排链开始求交集
+		sort.Slice(terms, func(a, b int) bool {
+			return len(i.postings[terms[a]]) < len(i.postings[terms[b]])
+		})
+		total := float64(len(i.docs))
+		for id := range i.postings[terms[0]] {
+			doc := i.docs[id]
+			if !q.filter(doc.art) {
+				continue
+			}
+			score := 0.0
+			for _, term := range terms {
+				cnt, ok := doc.tf[term]
+				if !ok {
+					score = -1
+					break
+				}
+				idf := math.Log(1 + total/float64(len(i.postings[term])))
+				score += float64(cnt) * idf
+			}
+			if score >= 0 {
+				matched = append(matched, scored{art: doc.art, score: score})
+			}
+		}
+	}
+	i.lock.RUnlock()
+
+	sort.Slice(matched, func(a, b int) bool {
+		x, y := matched[a], matched[b]
+		if x.score != y.score {
+			return x.score > y.score
+		}
+		if x.art.Utime != y.art.Utime {
+			return x.art.Utime > y.art.Utime
+		}
+		return x.art.Id > y.art.Id
+	})
+	res := ArticleSearchResult{Total: int64(len(matched))}
+	if q.Offset >= len(matched) {
+		return res, nil
+	}
+	end := len(matched)
+	if q.Limit > 0 {
+		end = min(end, q.Offset+q.Limit)
+	}
+	termSet := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		termSet[term] = struct{}{}
+	}
+	res.Hits = make([]ArticleHit, 0, end-q.Offset)
+	for _, m := range matched[q.Offset:end] {
+		res.Hits = append(res.Hits, ArticleHit{
+			Article:          m.art,
+			TitleHighlight:   highlight(m.art.Title, termSet),
+			ContentHighlight: snippet(m.art.Content, termSet),
+			Score:            m.score,
+		})
+	}
+	return res, nil
+}
+
+// filter 作者和更新时间的过滤条件
+func (q ArticleQuery) filter(art Article) bool {
+	if q.AuthorId > 0 && art.AuthorId != q.AuthorId {
+		return false
+	}
+	if q.StartTime > 0 && art.Utime < q.StartTime {
+		return false
+	}
+	if q.EndTime > 0 && art.Utime > q.EndTime {
+		return false
+	}
+	return true
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]struct{}, len(terms))
+	res := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+		res = append(res, term)
+	}
+	return res
+}
@@ -0,0 +1,144 @@
+package dao
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "中文二元切分",
+			text: "微服务架构",
+			want: []string{"微服", "服务", "务架", "架构"},
+		},
+		{
+			name: "单个汉字",
+			text: "我",
+			want: []string{"我"},
+		},
+		{
+			name: "中英文混合",
+			text: "使用Go语言，写 gRPC 服务!",
+			want: []string{"使用", "go", "语言", "写", "grpc", "服务"},
+		},
+		{
+			name: "只有标点",
+			text: "，。! ",
+			want: []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Tokenize(tc.text))
+		})
+	}
+}
+
+func TestInvertedIndexArticleDAO_Search(t *testing.T) {
+	ctx := context.Background()
+	d := NewInvertedIndexArticleDAO()
+	arts := []Article{
+		{Id: 1, Title: "微服务入门", Content: "介绍 gRPC 和服务注册", AuthorId: 1, Utime: 100},
+		{Id: 2, Title: "Go 并发", Content: "goroutine 和 channel，顺带聊聊微服务", AuthorId: 2, Utime: 200},
+		{Id: 3, Title: "数据库索引", Content: "B+ 树", AuthorId: 1, Utime: 300},
+	}
+	for _, art := range arts {
+		require.NoError(t, d.InputArticle(ctx, art))
+	}
+
+	testCases := []struct {
+		name    string
+		q       ArticleQuery
+		wantIds []int64
+		total   int64
+	}{
+		{
+			name: "标题命中的排在前面",
+			q:    ArticleQuery{Keywords: "微服务"},
+			// 1 是标题命中，2 只是内容命中
+			wantIds: []int64{1, 2},
+			total:   2,
+		},
+		{
+			name:    "所有关键字都要命中",
+			q:       ArticleQuery{Keywords: "微服务 channel"},
+			wantIds: []int64{2},
+			total:   1,
+		},
+		{
+			name:    "大小写不敏感",
+			q:       ArticleQuery{Keywords: "GRPC"},
+			wantIds: []int64{1},
+			total:   1,
+		},
+		{
+			name:    "过滤作者",
+			q:       ArticleQuery{Keywords: "微服务", AuthorId: 2},
+			wantIds: []int64{2},
+			total:   1,
+		},
+		{
+			name:    "过滤时间",
+			q:       ArticleQuery{StartTime: 150, EndTime: 300},
+			wantIds: []int64{3, 2},
+			total:   2,
+		},
+		{
+			name:    "分页",
+			q:       ArticleQuery{Offset: 1, Limit: 1},
+			wantIds: []int64{2},
+			total:   3,
+		},
+		{
+			name:  "没有命中",
+			q:     ArticleQuery{Keywords: "前端"},
+			total: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := d.Search(ctx, tc.q)
+			require.NoError(t, err)
+			assert.Equal(t, tc.total, res.Total)
+			var ids []int64
+			for _, hit := range res.Hits {
+				ids = append(ids, hit.Article.Id)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+		})
+	}
+}
+
+func TestInvertedIndexArticleDAO_Update(t *testing.T) {
+	ctx := context.Background()
+	d := NewInvertedIndexArticleDAO()
+	require.NoError(t, d.InputArticle(ctx, Article{Id: 1, Title: "微服务入门"}))
+	// 重新发表，旧的内容不能再搜到
+	require.NoError(t, d.InputArticle(ctx, Article{Id: 1, Title: "数据库入门"}))
+	res, err := d.Search(ctx, ArticleQuery{Keywords: "微服务"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.Total)
+	res, err = d.Search(ctx, ArticleQuery{Keywords: "数据库"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.Total)
+
+	// 撤回之后删除索引
+	require.NoError(t, d.DeleteArticle(ctx, 1))
+	res, err = d.Search(ctx, ArticleQuery{Keywords: "数据库"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.Total)
+}
+
+func TestHighlight(t *testing.T) {
+	terms := map[string]struct{}{"微服": {}, "服务": {}, "grpc": {}}
+	assert.Equal(t, "<em>微服务</em>入门", highlight("微服务入门", terms))
+	assert.Equal(t, "用 <em>gRPC</em> &lt;b&gt;", highlight("用 gRPC <b>", terms))
+	assert.Equal(t, "没有命中", highlight("没有命中", terms))
+}
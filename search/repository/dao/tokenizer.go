@@ -0,0 +1,91 @@
+package dao
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	kindNone = iota
+	// kindCJK 中日韩文字，没有空格分词，按照二元切分
+	kindCJK
+	// kindWord 字母和数字，连续的作为一个词
+	kindWord
+)
+
+// token 分词的结果，start 和 end 是在原文中的字节偏移，高亮的时候要用
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// Tokenize 对文本分词
+// 连续的中日韩文字按照二元切分（bigram），只有一个字的时候就是它本身；
+// 连续的字母和数字作为一个词，统一转成小写；其余的字符都当作分隔符
+func Tokenize(text string) []string {
+	tokens := tokenize(text)
+	res := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		res = append(res, t.term)
+	}
+	return res
+}
+
+func tokenize(text string) []token {
+	type runeAt struct {
+		r          rune
+		start, end int
+	}
+	var (
+		res     []token
+		run     []runeAt
+		runKind = kindNone
+	)
+	flush := func() {
+		switch runKind {
+		case kindCJK:
+			if len(run) == 1 {
+				res = append(res, token{term: string(run[0].r), start: run[0].start, end: run[0].end})
+			}
+			for i := 0; i+1 < len(run); i++ {
+				res = append(res, token{
+					term:  string([]rune{run[i].r, run[i+1].r}),
+					start: run[i].start,
+					end:   run[i+1].end,
+				})
+			}
+		case kindWord:
+			start, end := run[0].start, run[len(run)-1].end
+			res = append(res, token{term: strings.ToLower(text[start:end]), start: start, end: end})
+		}
+		run = run[:0]
+		runKind = kindNone
+	}
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		kind := runeKind(r)
+		if kind != runKind {
+			flush()
+		}
+		if kind != kindNone {
+			runKind = kind
+			run = append(run, runeAt{r: r, start: i, end: i + size})
+		}
+		i += size
+	}
+	flush()
+	return res
+}
+
+func runeKind(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return kindCJK
+	case unicode.IsLetter(r), unicode.IsDigit(r):
+		return kindWord
+	default:
+		return kindNone
+	}
+}
@@ -0,0 +1,45 @@
+package dao
+
+import "context"
+
+// ArticleDAO 索引的抽象
+// 内嵌的倒排索引用在开发和测试环境，线上可以换成 Elasticsearch 的实现
+type ArticleDAO interface {
+	// InputArticle 新建或者覆盖索引
+	InputArticle(ctx context.Context, art Article) error
+	DeleteArticle(ctx context.Context, id int64) error
+	Search(ctx context.Context, q ArticleQuery) (ArticleSearchResult, error)
+}
+
+type Article struct {
+	Id       int64  `json:"id"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	AuthorId int64  `json:"author_id"`
+	Status   int32  `json:"status"`
+	// 毫秒数
+	Ctime int64 `json:"ctime"`
+	Utime int64 `json:"utime"`
+}
+
+type ArticleQuery struct {
+	Keywords string
+	AuthorId int64
+	// 更新时间的范围，毫秒数，0 表示不限制
+	StartTime int64
+	EndTime   int64
+	Offset    int
+	Limit     int
+}
+
+type ArticleHit struct {
+	Article          Article
+	TitleHighlight   string
+	ContentHighlight string
+	Score            float64
+}
+
+type ArticleSearchResult struct {
+	Hits  []ArticleHit
+	Total int64
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"geektime/webook/search/domain"
+	"geektime/webook/search/repository"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+type SearchService interface {
+	SearchArticle(ctx context.Context, q domain.ArticleSearchQuery) (domain.ArticleSearchResult, error)
+	// InputArticle 帖子发表之后建索引，重复发表会覆盖
+	InputArticle(ctx context.Context, art domain.Article) error
+	// DeleteArticle 帖子撤回或者删除之后删除索引
+	DeleteArticle(ctx context.Context, id int64) error
+}
+
+type searchService struct {
+	repo repository.ArticleRepository
+}
+
+func NewSearchService(repo repository.ArticleRepository) SearchService {
+	return &searchService{repo: repo}
+}
+
+func (s *searchService) SearchArticle(ctx context.Context, q domain.ArticleSearchQuery) (domain.ArticleSearchResult, error) {
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultLimit
+	}
+	if q.Limit > maxLimit {
+		q.Limit = maxLimit
+	}
+	return s.repo.SearchArticle(ctx, q)
+}
+
+func (s *searchService) InputArticle(ctx context.Context, art domain.Article) error {
+	return s.repo.InputArticle(ctx, art)
+}
+
+func (s *searchService) DeleteArticle(ctx context.Context, id int64) error {
+	return s.repo.DeleteArticle(ctx, id)
+}
@@ -0,0 +1,34 @@
+//go:build wireinject
+
+package main
+
+import (
+	"geektime/webook/search/grpc"
+	"geektime/webook/search/ioc"
+	"geektime/webook/search/repository"
+	"geektime/webook/search/service"
+	"github.com/google/wire"
+)
+
+var thirdPartySet = wire.NewSet(
+	ioc.InitLogger,
+	ioc.InitEtcdClient,
+	ioc.InitKafkaClient,
+)
+
+var searchSvcSet = wire.NewSet(
+	ioc.InitArticleDAO,
+	repository.NewArticleRepository,
+	service.NewSearchService,
+)
+
+func InitApp() *App {
+	wire.Build(thirdPartySet, searchSvcSet,
+		grpc.NewSearchServiceServer,
+		ioc.InitGRPCxServer,
+		ioc.InitArticleConsumer,
+		ioc.InitConsumers,
+		wire.Struct(new(App), "*"),
+	)
+	return new(App)
+}
@@ -16,17 +16,24 @@ function Page() {
     const [html, setHtml] = useState()
     const params = useSearchParams()
     const artID = params?.get("id")
+    // 详情里面拿到的版本号，保存的时候原样带回去
+    const [version, setVersion] = useState(0)
     const onFinish = (values: any) => {
         if(artID) {
             values.id = parseInt(artID)
         }
         values.content = html
+        values.version = version
         axios.post("/articles/edit", values)
             .then((res) => {
                 if(res.status != 200) {
                     alert(res.statusText);
                     return
                 }
+                if (res.data?.code == 402002) {
+                    alert("帖子已经在别的地方修改过了，请复制内容之后刷新页面")
+                    return
+                }
                 if (res.data?.code == 0) {
                     router.push('/articles/list')
                     return
@@ -42,12 +49,17 @@ function Page() {
             values.id = parseInt(artID)
         }
         values.content = html
+        values.version = version
         axios.post("/articles/publish", values)
             .then((res) => {
                 if(res.status != 200) {
                     alert(res.statusText);
                     return
                 }
+                if (res.data?.code == 402002) {
+                    alert("帖子已经在别的地方修改过了，请复制内容之后刷新页面")
+                    return
+                }
                 if (res.data?.code == 0) {
                     router.push('/articles/view?id='+res.data.data)
                     return
@@ -67,6 +79,7 @@ function Page() {
             .then((res) => res.data)
             .then((data) => {
                 form.setFieldsValue(data.data)
+                setVersion(data.data.version || 0)
                 setHtml(data.data.content)
             })
     }, [form, artID])
@@ -4,6 +4,8 @@ package main
 
 import (
 	events "geektime/webook/internal/events/article"
+	"geektime/webook/internal/events/ranking"
+	"geektime/webook/internal/job"
 	"geektime/webook/internal/repository"
 	"geektime/webook/internal/repository/cache"
 	"geektime/webook/internal/repository/dao"
@@ -17,7 +19,15 @@ import (
 var rankingSvcSet = wire.NewSet(
 	cache.NewRankingRedisCache,
 	repository.NewCachedRankingRepository,
+	ioc.InitRankingBoards,
 	service.NewBatchRankingService,
+	service.NewIncrRankingService,
+)
+
+var jobProviderSet = wire.NewSet(
+	dao.NewGORMJobDAO,
+	repository.NewPreemptJobRepository,
+	ioc.InitCronJobService,
 )
 
 func InitApp() *App {
@@ -28,36 +38,63 @@ func InitApp() *App {
 		ioc.InitRlockClient,
 		//dao
 		dao.NewUserDao,
-		dao.NewGROMArticleDAO,
+		ioc.InitArticleDAO,
+		dao.NewGORMArticleCoAuthorDAO,
+		dao.NewGORMArticleSeriesDAO,
+		dao.NewGORMAbuseAuditDAO,
 		//cache
 		cache.NewUserCache, cache.NewCodeCache,
 		cache.NewArticleRedisCache,
+		cache.NewAntiAbuseRedisCache,
 		//repository
 		repository.NewUserRepository, repository.NewCodeRepository,
 		repository.NewArticleRepository,
+		repository.NewAntiAbuseRepository,
 		//service
 		ioc.InitSMSService,
 		ioc.InitWechatService,
 		service.NewCodeService, service.NewUserService,
 		service.NewArticleService,
+		ioc.InitAntiAbuseService,
 		//GRPC client
 		ioc.InitEtcd,
 		ioc.InitIntrGRPCClientV1,
+		ioc.InitSearchGRPCClient,
+		ioc.InitFollowGRPCClient,
+		ioc.InitCommentGRPCClient,
 		//handler
 		jwt2.NewRedisJWTHandler,
 		web.NewUserHandler,
 		web.NewOAuth2WechatHandler,
 		web.NewArticleHandler,
+		web.NewSearchHandler,
+		web.NewAuthorHandler,
+		web.NewHistoryHandler,
+		web.NewCollectionHandler,
+		web.NewRankingHandler,
 		ioc.InitMiddlewares,
 		ioc.InitWebServer,
 		//job
 		rankingSvcSet,
 		ioc.InitRankingJob,
+		ioc.InitUnboundedRankingJob,
+		ioc.InitRankingRebaseJob,
 		ioc.InitJobs,
+		jobProviderSet,
+		job.NewScheduledPublishExecutor,
+		job.NewTrashPurgeExecutor,
+		ioc.InitHTTPExecutor,
+		ioc.InitGRPCExecutor,
+		ioc.InitScheduler,
+		wire.Bind(new(web.JobExecutors), new(*job.Scheduler)),
+		web.NewJobHandler,
+		ioc.InitAdminWebServer,
 		//kafka, consumer and producer
 		ioc.InitKafkaClient,
 		ioc.InitSyncProducer,
 		events.NewKafkaProducer,
+		ranking.NewCntChangeConsumer,
+		ioc.InitConsumers,
 
 		//组装App结构体的所有字段
 		wire.Struct(new(App), "*"),
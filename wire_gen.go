@@ -8,6 +8,8 @@ package main
 
 import (
 	"geektime/webook/internal/events/article"
+	"geektime/webook/internal/events/ranking"
+	"geektime/webook/internal/job"
 	"geektime/webook/internal/repository"
 	"geektime/webook/internal/repository/cache"
 	"geektime/webook/internal/repository/dao"
@@ -41,30 +43,65 @@ func InitApp() *App {
 	userHandler := web.NewUserHandler(userService, codeService, jwtHandler)
 	wechatService := ioc.InitWechatService()
 	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, userService, jwtHandler)
-	articleDAO := dao.NewGROMArticleDAO(db)
+	articleDAO := ioc.InitArticleDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewArticleRepository(articleDAO, articleCache, loggerV1, userDAO)
+	articleCoAuthorDAO := dao.NewGORMArticleCoAuthorDAO(db)
+	articleSeriesDAO := dao.NewGORMArticleSeriesDAO(db)
+	articleRepository := repository.NewArticleRepository(articleDAO, articleCache, loggerV1, userDAO, articleCoAuthorDAO, articleSeriesDAO)
 	client := ioc.InitKafkaClient()
 	syncProducer := ioc.InitSyncProducer(client)
 	producer := article.NewKafkaProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, loggerV1, producer)
+	abuseAuditDAO := dao.NewGORMAbuseAuditDAO(db)
+	antiAbuseCache := cache.NewAntiAbuseRedisCache(cmdable)
+	antiAbuseRepository := repository.NewAntiAbuseRepository(abuseAuditDAO, antiAbuseCache)
+	antiAbuseService := ioc.InitAntiAbuseService(antiAbuseRepository, cmdable, loggerV1)
+	articleService := service.NewArticleService(articleRepository, loggerV1, producer, antiAbuseService)
 	clientv3Client := ioc.InitEtcd()
 	interactiveServiceClient := ioc.InitIntrGRPCClientV1(clientv3Client)
-	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveServiceClient)
-	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler)
+	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveServiceClient, antiAbuseService)
+	searchServiceClient := ioc.InitSearchGRPCClient(clientv3Client)
+	searchHandler := web.NewSearchHandler(searchServiceClient, loggerV1)
+	followServiceClient := ioc.InitFollowGRPCClient(clientv3Client)
+	authorHandler := web.NewAuthorHandler(articleService, followServiceClient, loggerV1)
+	historyHandler := web.NewHistoryHandler(interactiveServiceClient, articleService, loggerV1)
+	collectionHandler := web.NewCollectionHandler(interactiveServiceClient, articleService, loggerV1)
 	rankingCache := cache.NewRankingRedisCache(cmdable)
 	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
-	rankingService := service.NewBatchRankingService(rankingRepository, articleService, interactiveServiceClient)
+	commentServiceClient := ioc.InitCommentGRPCClient()
+	v2 := ioc.InitRankingBoards()
+	rankingService := service.NewBatchRankingService(rankingRepository, articleService, interactiveServiceClient, commentServiceClient, v2)
+	rankingHandler := web.NewRankingHandler(rankingService, loggerV1)
+	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler, searchHandler, authorHandler, historyHandler, collectionHandler, rankingHandler)
 	rlockClient := ioc.InitRlockClient(cmdable)
 	rankingJob := ioc.InitRankingJob(rankingService, loggerV1, rlockClient)
-	cron := ioc.InitJobs(loggerV1, rankingJob)
+	unboundedRankingJob := ioc.InitUnboundedRankingJob(rankingService, loggerV1, rlockClient)
+	incrRankingService := service.NewIncrRankingService(rankingRepository, articleService, v2)
+	rankingRebaseJob := ioc.InitRankingRebaseJob(incrRankingService)
+	cron := ioc.InitJobs(loggerV1, rankingJob, unboundedRankingJob, rankingRebaseJob)
+	cntChangeConsumer := ranking.NewCntChangeConsumer(incrRankingService, client, loggerV1)
+	v3 := ioc.InitConsumers(cntChangeConsumer)
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	cronJobService := ioc.InitCronJobService(cronJobRepository, loggerV1)
+	scheduledPublishExecutor := job.NewScheduledPublishExecutor(articleService, loggerV1)
+	trashPurgeExecutor := job.NewTrashPurgeExecutor(articleService, loggerV1)
+	httpExecutor := ioc.InitHTTPExecutor(loggerV1)
+	grpcExecutor := ioc.InitGRPCExecutor(clientv3Client)
+	scheduler := ioc.InitScheduler(loggerV1, cronJobService, scheduledPublishExecutor, trashPurgeExecutor, httpExecutor, grpcExecutor)
+	jobHandler := web.NewJobHandler(cronJobService, scheduler, loggerV1)
+	server := ioc.InitAdminWebServer(jobHandler)
 	app := &App{
-		server: engine,
-		cron:   cron,
+		server:    engine,
+		consumers: v3,
+		cron:      cron,
+		scheduler: scheduler,
+		webAdmin:  server,
 	}
 	return app
 }
 
 // wire.go:
 
-var rankingSvcSet = wire.NewSet(cache.NewRankingRedisCache, repository.NewCachedRankingRepository, service.NewBatchRankingService)
+var rankingSvcSet = wire.NewSet(cache.NewRankingRedisCache, repository.NewCachedRankingRepository, ioc.InitRankingBoards, service.NewBatchRankingService, service.NewIncrRankingService)
+
+var jobProviderSet = wire.NewSet(dao.NewGORMJobDAO, repository.NewPreemptJobRepository, ioc.InitCronJobService)